@@ -0,0 +1,365 @@
+package aws
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+// Names of key usages, as in RFC 5280
+var x509KeyUsageNames = []struct {
+	Usage x509.KeyUsage
+	Name  string
+}{
+	{x509.KeyUsageDigitalSignature, "digitalSignature"},
+	{x509.KeyUsageContentCommitment, "contentCommitment"},
+	{x509.KeyUsageKeyEncipherment, "keyEncipherment"},
+	{x509.KeyUsageDataEncipherment, "dataEncipherment"},
+	{x509.KeyUsageKeyAgreement, "keyAgreement"},
+	{x509.KeyUsageCertSign, "keyCertSign"},
+	{x509.KeyUsageCRLSign, "cRLSign"},
+	{x509.KeyUsageEncipherOnly, "encipherOnly"},
+	{x509.KeyUsageDecipherOnly, "decipherOnly"},
+}
+
+// Names of extended key usages, as in RFC 5280 where defined there
+var x509ExtKeyUsageNames = map[x509.ExtKeyUsage]string{
+	x509.ExtKeyUsageAny:                            "any",
+	x509.ExtKeyUsageServerAuth:                     "serverAuth",
+	x509.ExtKeyUsageClientAuth:                     "clientAuth",
+	x509.ExtKeyUsageCodeSigning:                    "codeSigning",
+	x509.ExtKeyUsageEmailProtection:                "emailProtection",
+	x509.ExtKeyUsageIPSECEndSystem:                 "ipsecEndSystem",
+	x509.ExtKeyUsageIPSECTunnel:                    "ipsecTunnel",
+	x509.ExtKeyUsageIPSECUser:                      "ipsecUser",
+	x509.ExtKeyUsageTimeStamping:                   "timeStamping",
+	x509.ExtKeyUsageOCSPSigning:                    "OCSPSigning",
+	x509.ExtKeyUsageMicrosoftServerGatedCrypto:     "msSGC",
+	x509.ExtKeyUsageNetscapeServerGatedCrypto:      "nsSGC",
+	x509.ExtKeyUsageMicrosoftCommercialCodeSigning: "msCodeCom",
+	x509.ExtKeyUsageMicrosoftKernelCodeSigning:     "msKernelCode",
+}
+
+// x509Certificate is the detail of a certificate which is not returned by the
+// AWS APIs
+type x509Certificate struct {
+	PublicKeyAlgorithm      string
+	PublicKeySize           *int
+	PublicKeyCurve          *string
+	SubjectAlternativeNames []*x509SubjectAlternativeName
+	IsCA                    bool
+	MaxPathLength           *int
+	KeyUsages               []string
+	ExtendedKeyUsages       []string
+	OcspServers             []string
+	IssuingCertificateUrls  []string
+	CrlDistributionPoints   []string
+	Sha256Fingerprint       string
+}
+
+type x509SubjectAlternativeName struct {
+	Type  string
+	Value string
+}
+
+// x509ChainCertificate is the validation of a certificate of a chain, which
+// signs the certificate before it, starting from the leaf
+type x509ChainCertificate struct {
+	Subject           string
+	Issuer            string
+	SerialNumber      string
+	NotBefore         time.Time
+	NotAfter          time.Time
+	IsCA              bool
+	Expired           bool
+	SignsPrevious     bool
+	Sha256Fingerprint string
+}
+
+// x509CertificateData is a certificate and its chain, parsed from PEM
+type x509CertificateData struct {
+	Certificate *x509Certificate
+	// Nil if the chain could not be parsed
+	ChainValidation []*x509ChainCertificate
+	ChainExpired    *bool
+}
+
+// x509CertificateColumns returns the columns of the x509CertificateData
+// returned by hydrate
+func x509CertificateColumns(hydrate plugin.HydrateFunc) []*plugin.Column {
+	return []*plugin.Column{
+		{
+			Name:        "public_key_algorithm",
+			Description: "The algorithm of the public key of the certificate (RSA | ECDSA | Ed25519).",
+			Type:        proto.ColumnType_STRING,
+			Hydrate:     hydrate,
+			Transform:   transform.FromField("Certificate.PublicKeyAlgorithm"),
+		},
+		{
+			Name:        "public_key_size",
+			Description: "The size of the public key of the certificate in bits.",
+			Type:        proto.ColumnType_INT,
+			Hydrate:     hydrate,
+			Transform:   transform.FromField("Certificate.PublicKeySize"),
+		},
+		{
+			Name:        "public_key_curve",
+			Description: "The elliptic curve of an ECDSA public key, e.g. P-256.",
+			Type:        proto.ColumnType_STRING,
+			Hydrate:     hydrate,
+			Transform:   transform.FromField("Certificate.PublicKeyCurve"),
+		},
+		{
+			Name:        "x509_subject_alternative_names",
+			Description: "All the subject alternative names of the certificate, with their type (DNS | IP | Email | URI).",
+			Type:        proto.ColumnType_JSON,
+			Hydrate:     hydrate,
+			Transform:   transform.FromField("Certificate.SubjectAlternativeNames"),
+		},
+		{
+			Name:        "is_ca",
+			Description: "True if the basic constraints of the certificate allow it to sign other certificates.",
+			Type:        proto.ColumnType_BOOL,
+			Hydrate:     hydrate,
+			Transform:   transform.FromField("Certificate.IsCA"),
+		},
+		{
+			Name:        "max_path_length",
+			Description: "The most intermediate certificates which may follow a CA certificate in a chain. Null if not limited.",
+			Type:        proto.ColumnType_INT,
+			Hydrate:     hydrate,
+			Transform:   transform.FromField("Certificate.MaxPathLength"),
+		},
+		{
+			Name:        "key_usages",
+			Description: "The key usages of the certificate, e.g. digitalSignature.",
+			Type:        proto.ColumnType_JSON,
+			Hydrate:     hydrate,
+			Transform:   transform.FromField("Certificate.KeyUsages"),
+		},
+		{
+			Name:        "extended_key_usages",
+			Description: "The extended key usages of the certificate, e.g. serverAuth, or the OID of usages without a name.",
+			Type:        proto.ColumnType_JSON,
+			Hydrate:     hydrate,
+			Transform:   transform.FromField("Certificate.ExtendedKeyUsages"),
+		},
+		{
+			Name:        "ocsp_servers",
+			Description: "The URLs of the OCSP responders for the certificate.",
+			Type:        proto.ColumnType_JSON,
+			Hydrate:     hydrate,
+			Transform:   transform.FromField("Certificate.OcspServers"),
+		},
+		{
+			Name:        "issuing_certificate_urls",
+			Description: "The URLs of the certificate of the issuer.",
+			Type:        proto.ColumnType_JSON,
+			Hydrate:     hydrate,
+			Transform:   transform.FromField("Certificate.IssuingCertificateUrls"),
+		},
+		{
+			Name:        "crl_distribution_points",
+			Description: "The URLs of the certificate revocation lists for the certificate.",
+			Type:        proto.ColumnType_JSON,
+			Hydrate:     hydrate,
+			Transform:   transform.FromField("Certificate.CrlDistributionPoints"),
+		},
+		{
+			Name:        "sha256_fingerprint",
+			Description: "The SHA-256 fingerprint of the certificate, as colon separated hex.",
+			Type:        proto.ColumnType_STRING,
+			Hydrate:     hydrate,
+			Transform:   transform.FromField("Certificate.Sha256Fingerprint"),
+		},
+		{
+			Name:        "chain_validation",
+			Description: "The certificates of the chain, from the issuer of the certificate up, with whether each has expired and whether it signs the certificate before it.",
+			Type:        proto.ColumnType_JSON,
+			Hydrate:     hydrate,
+			Transform:   transform.FromField("ChainValidation"),
+		},
+		{
+			Name:        "chain_expired",
+			Description: "True if a certificate of the chain has expired.",
+			Type:        proto.ColumnType_BOOL,
+			Hydrate:     hydrate,
+			Transform:   transform.FromField("ChainExpired"),
+		},
+	}
+}
+
+//// HYDRATE FUNCTIONS
+
+// getX509CertificateData parses a PEM certificate and chain once for all the
+// x509 columns of a row. A certificate which cannot be parsed is logged and
+// gives null columns, rather than failing the whole query.
+func getX509CertificateData(ctx context.Context, certificatePem *string, chainPem *string) (interface{}, error) {
+	data, err := parseX509Certificate(types.SafeString(certificatePem), types.SafeString(chainPem), time.Now())
+	if err != nil {
+		plugin.Logger(ctx).Warn("getX509CertificateData", "error", err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+	return data, nil
+}
+
+//// UTILITY FUNCTIONS
+
+// parseX509Certificate parses the first certificate of a PEM string and the
+// chain which follows it. If only the chain cannot be parsed, the certificate
+// is returned with the error.
+func parseX509Certificate(certificatePem string, chainPem string, now time.Time) (*x509CertificateData, error) {
+	certificates, err := parsePemCertificates(certificatePem)
+	if err != nil || len(certificates) == 0 {
+		return nil, err
+	}
+	data := &x509CertificateData{Certificate: x509CertificateDetail(certificates[0])}
+
+	chain, err := parsePemCertificates(chainPem)
+	if err != nil {
+		return data, err
+	}
+	data.ChainValidation = x509ChainValidation(certificates[0], chain, now)
+	expired := false
+	for _, certificate := range data.ChainValidation {
+		if certificate.Expired {
+			expired = true
+		}
+	}
+	data.ChainExpired = &expired
+	return data, nil
+}
+
+// parsePemCertificates returns the certificates of the CERTIFICATE blocks of a
+// PEM string, in order
+func parsePemCertificates(data string) ([]*x509.Certificate, error) {
+	certificates := []*x509.Certificate{}
+	rest := []byte(data)
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return certificates, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, certificate)
+	}
+}
+
+func x509CertificateDetail(certificate *x509.Certificate) *x509Certificate {
+	detail := &x509Certificate{
+		PublicKeyAlgorithm:      certificate.PublicKeyAlgorithm.String(),
+		SubjectAlternativeNames: []*x509SubjectAlternativeName{},
+		IsCA:                    certificate.BasicConstraintsValid && certificate.IsCA,
+		KeyUsages:               []string{},
+		ExtendedKeyUsages:       []string{},
+		OcspServers:             certificate.OCSPServer,
+		IssuingCertificateUrls:  certificate.IssuingCertificateURL,
+		CrlDistributionPoints:   certificate.CRLDistributionPoints,
+		Sha256Fingerprint:       sha256Fingerprint(certificate.Raw),
+	}
+
+	switch key := certificate.PublicKey.(type) {
+	case *rsa.PublicKey:
+		size := key.N.BitLen()
+		detail.PublicKeySize = &size
+	case *ecdsa.PublicKey:
+		size := key.Curve.Params().BitSize
+		curve := key.Curve.Params().Name
+		detail.PublicKeySize = &size
+		detail.PublicKeyCurve = &curve
+	case ed25519.PublicKey:
+		size := len(key) * 8
+		detail.PublicKeySize = &size
+	}
+
+	for _, name := range certificate.DNSNames {
+		detail.SubjectAlternativeNames = append(detail.SubjectAlternativeNames, &x509SubjectAlternativeName{"DNS", name})
+	}
+	for _, ip := range certificate.IPAddresses {
+		detail.SubjectAlternativeNames = append(detail.SubjectAlternativeNames, &x509SubjectAlternativeName{"IP", ip.String()})
+	}
+	for _, email := range certificate.EmailAddresses {
+		detail.SubjectAlternativeNames = append(detail.SubjectAlternativeNames, &x509SubjectAlternativeName{"Email", email})
+	}
+	for _, uri := range certificate.URIs {
+		detail.SubjectAlternativeNames = append(detail.SubjectAlternativeNames, &x509SubjectAlternativeName{"URI", uri.String()})
+	}
+
+	// A negative MaxPathLen, or zero without MaxPathLenZero, means no limit
+	if detail.IsCA && (certificate.MaxPathLen > 0 || certificate.MaxPathLenZero) {
+		maxPathLength := certificate.MaxPathLen
+		detail.MaxPathLength = &maxPathLength
+	}
+
+	for _, usage := range x509KeyUsageNames {
+		if certificate.KeyUsage&usage.Usage != 0 {
+			detail.KeyUsages = append(detail.KeyUsages, usage.Name)
+		}
+	}
+	for _, usage := range certificate.ExtKeyUsage {
+		if name, ok := x509ExtKeyUsageNames[usage]; ok {
+			detail.ExtendedKeyUsages = append(detail.ExtendedKeyUsages, name)
+		} else {
+			detail.ExtendedKeyUsages = append(detail.ExtendedKeyUsages, fmt.Sprintf("%d", usage))
+		}
+	}
+	for _, oid := range certificate.UnknownExtKeyUsage {
+		detail.ExtendedKeyUsages = append(detail.ExtendedKeyUsages, oid.String())
+	}
+
+	return detail
+}
+
+// x509ChainValidation returns the certificates of a chain with whether each
+// has expired at now and signs the certificate before it
+func x509ChainValidation(leaf *x509.Certificate, chain []*x509.Certificate, now time.Time) []*x509ChainCertificate {
+	validation := []*x509ChainCertificate{}
+	previous := leaf
+	for _, certificate := range chain {
+		validation = append(validation, &x509ChainCertificate{
+			Subject:           certificate.Subject.String(),
+			Issuer:            certificate.Issuer.String(),
+			SerialNumber:      certificate.SerialNumber.String(),
+			NotBefore:         certificate.NotBefore,
+			NotAfter:          certificate.NotAfter,
+			IsCA:              certificate.BasicConstraintsValid && certificate.IsCA,
+			Expired:           now.After(certificate.NotAfter),
+			SignsPrevious:     previous.CheckSignatureFrom(certificate) == nil,
+			Sha256Fingerprint: sha256Fingerprint(certificate.Raw),
+		})
+		previous = certificate
+	}
+	return validation
+}
+
+// sha256Fingerprint returns the SHA-256 digest of a DER certificate as colon
+// separated hex, as openssl prints it
+func sha256Fingerprint(der []byte) string {
+	digest := sha256.Sum256(der)
+	parts := make([]string, len(digest))
+	for i, b := range digest {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
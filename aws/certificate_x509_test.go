@@ -0,0 +1,241 @@
+package aws
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+var x509TestNow = time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC)
+
+// createTestCertificate returns a certificate for key signed by parent, or
+// self signed if parent is nil
+func createTestCertificate(t *testing.T, template *x509.Certificate, key interface{}, parent *x509.Certificate, parentKey interface{}) *x509.Certificate {
+	if parent == nil {
+		parent = template
+		parentKey = key
+	}
+	var publicKey interface{}
+	switch k := key.(type) {
+	case *rsa.PrivateKey:
+		publicKey = &k.PublicKey
+	case *ecdsa.PrivateKey:
+		publicKey = &k.PublicKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, publicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return certificate
+}
+
+func createTestChain(t *testing.T) (leaf, intermediate, root *x509.Certificate) {
+	rootKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	intermediateKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	leafKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	root = createTestCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test Root"},
+		NotBefore:             x509TestNow.AddDate(-5, 0, 0),
+		NotAfter:              x509TestNow.AddDate(5, 0, 0),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}, rootKey, nil, nil)
+
+	// The intermediate expired a day before now
+	intermediate = createTestCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(2),
+		Subject:               pkix.Name{CommonName: "Test Intermediate"},
+		NotBefore:             x509TestNow.AddDate(-1, 0, 0),
+		NotAfter:              x509TestNow.AddDate(0, 0, -1),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		MaxPathLen:            0,
+		MaxPathLenZero:        true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+	}, intermediateKey, root, rootKey)
+
+	leaf = createTestCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(3),
+		Subject:               pkix.Name{CommonName: "www.example.com"},
+		NotBefore:             x509TestNow.AddDate(0, -1, 0),
+		NotAfter:              x509TestNow.AddDate(0, 11, 0),
+		BasicConstraintsValid: true,
+		DNSNames:              []string{"www.example.com", "example.com"},
+		IPAddresses:           []net.IP{net.ParseIP("192.0.2.10"), net.ParseIP("2001:db8::1")},
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+		OCSPServer:            []string{"http://ocsp.example.com"},
+		IssuingCertificateURL: []string{"http://ca.example.com/intermediate.crt"},
+		CRLDistributionPoints: []string{"http://crl.example.com/intermediate.crl"},
+	}, leafKey, intermediate, intermediateKey)
+
+	return leaf, intermediate, root
+}
+
+func TestX509CertificateDetail(t *testing.T) {
+	leaf, intermediate, _ := createTestChain(t)
+
+	size := 2048
+	expected := &x509Certificate{
+		PublicKeyAlgorithm: "RSA",
+		PublicKeySize:      &size,
+		SubjectAlternativeNames: []*x509SubjectAlternativeName{
+			{"DNS", "www.example.com"},
+			{"DNS", "example.com"},
+			{"IP", "192.0.2.10"},
+			{"IP", "2001:db8::1"},
+		},
+		IsCA:                   false,
+		KeyUsages:              []string{"digitalSignature", "keyEncipherment"},
+		ExtendedKeyUsages:      []string{"serverAuth", "clientAuth"},
+		OcspServers:            []string{"http://ocsp.example.com"},
+		IssuingCertificateUrls: []string{"http://ca.example.com/intermediate.crt"},
+		CrlDistributionPoints:  []string{"http://crl.example.com/intermediate.crl"},
+		Sha256Fingerprint:      sha256Fingerprint(leaf.Raw),
+	}
+
+	output := x509CertificateDetail(leaf)
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("FAILED: expected %+v, got %+v", expected, output)
+	}
+
+	output = x509CertificateDetail(intermediate)
+	if !output.IsCA || output.MaxPathLength == nil || *output.MaxPathLength != 0 {
+		t.Errorf("FAILED: expected a CA with max path length 0, got %+v", output)
+	}
+	if output.PublicKeyAlgorithm != "ECDSA" || *output.PublicKeySize != 256 || *output.PublicKeyCurve != "P-256" {
+		t.Errorf("FAILED: expected an ECDSA P-256 key, got %s %d %s", output.PublicKeyAlgorithm, *output.PublicKeySize, *output.PublicKeyCurve)
+	}
+	if !reflect.DeepEqual(output.KeyUsages, []string{"keyCertSign", "cRLSign"}) {
+		t.Errorf("FAILED: expected keyCertSign and cRLSign, got %v", output.KeyUsages)
+	}
+}
+
+func TestParseX509Certificate(t *testing.T) {
+	leaf, intermediate, root := createTestChain(t)
+	encode := func(certificates ...*x509.Certificate) string {
+		var data string
+		for _, certificate := range certificates {
+			data += string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certificate.Raw}))
+		}
+		return data
+	}
+	malformed := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")}))
+
+	output, err := parseX509Certificate(encode(leaf), encode(intermediate, root), x509TestNow)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.Certificate.PublicKeySize == nil || *output.Certificate.PublicKeySize != 2048 {
+		t.Errorf("FAILED: expected a public key size of 2048, got %v", output.Certificate.PublicKeySize)
+	}
+	if len(output.ChainValidation) != 2 || output.ChainExpired == nil || !*output.ChainExpired {
+		t.Errorf("FAILED: expected a chain of 2 with an expired certificate, got %d and %v", len(output.ChainValidation), output.ChainExpired)
+	}
+
+	output, err = parseX509Certificate("", "", x509TestNow)
+	if err != nil || output != nil {
+		t.Errorf("FAILED: expected nil for no certificate, got %v and error %v", output, err)
+	}
+
+	output, err = parseX509Certificate(malformed, "", x509TestNow)
+	if err == nil || output != nil {
+		t.Errorf("FAILED: expected an error and nil for a malformed certificate, got %v and error %v", output, err)
+	}
+
+	output, err = parseX509Certificate(encode(leaf), malformed, x509TestNow)
+	if err == nil || output == nil || output.Certificate == nil || output.ChainValidation != nil || output.ChainExpired != nil {
+		t.Errorf("FAILED: expected the certificate without a chain and an error for a malformed chain, got %+v and error %v", output, err)
+	}
+}
+
+func TestX509ChainValidation(t *testing.T) {
+	leaf, intermediate, root := createTestChain(t)
+
+	type chainTest struct {
+		Name     string
+		Chain    []*x509.Certificate
+		Expected [][2]bool
+	}
+
+	tests := []chainTest{
+		{"complete chain", []*x509.Certificate{intermediate, root}, [][2]bool{{true, true}, {false, true}}},
+		{"chain out of order", []*x509.Certificate{root, intermediate}, [][2]bool{{false, false}, {true, false}}},
+		{"no chain", []*x509.Certificate{}, [][2]bool{}},
+	}
+
+	for _, test := range tests {
+		output := x509ChainValidation(leaf, test.Chain, x509TestNow)
+		result := [][2]bool{}
+		for _, certificate := range output {
+			result = append(result, [2]bool{certificate.Expired, certificate.SignsPrevious})
+		}
+		if !reflect.DeepEqual(result, test.Expected) {
+			t.Errorf("Case '%s': expected [expired, signs previous] %v, got %v", test.Name, test.Expected, result)
+		}
+	}
+}
+
+func TestParsePemCertificates(t *testing.T) {
+	leaf, intermediate, _ := createTestChain(t)
+	chain := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: leaf.Raw})) +
+		string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: []byte("ignored")})) +
+		string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: intermediate.Raw}))
+
+	output, err := parsePemCertificates(chain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(output) != 2 || !output[0].Equal(leaf) || !output[1].Equal(intermediate) {
+		t.Errorf("FAILED: expected the leaf and intermediate certificates, got %d certificates", len(output))
+	}
+
+	output, err = parsePemCertificates("")
+	if err != nil || len(output) != 0 {
+		t.Errorf("FAILED: expected no certificates for an empty string, got %d and error %v", len(output), err)
+	}
+
+	_, err = parsePemCertificates(string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("garbage")})))
+	if err == nil {
+		t.Errorf("FAILED: expected an error for a malformed certificate")
+	}
+}
+
+func TestSha256Fingerprint(t *testing.T) {
+	// SHA-256 of the empty string
+	expected := "E3:B0:C4:42:98:FC:1C:14:9A:FB:F4:C8:99:6F:B9:24:27:AE:41:E4:64:9B:93:4C:A4:95:99:1B:78:52:B8:55"
+	output := sha256Fingerprint([]byte{})
+	if output != expected {
+		t.Errorf("FAILED: expected %s, got %s", expected, output)
+	}
+	if strings.Count(output, ":") != 31 {
+		t.Errorf("FAILED: expected 32 colon separated bytes, got %s", output)
+	}
+}
@@ -86,6 +86,32 @@ func awsS3Columns(columns []*plugin.Column) []*plugin.Column {
 	return append(columns, commonS3Columns...)
 }
 
+// append the common aws columns for resources which are listed from all regions
+// in a single call, taking the region of each row from regionField
+func awsMultiRegionColumns(columns []*plugin.Column, regionField string) []*plugin.Column {
+	return append(columns,
+		&plugin.Column{
+			Name:        "partition",
+			Type:        proto.ColumnType_STRING,
+			Hydrate:     getCommonColumns,
+			Description: "The AWS partition in which the resource is located (aws, aws-cn, or aws-us-gov).",
+		},
+		&plugin.Column{
+			Name:        "region",
+			Type:        proto.ColumnType_STRING,
+			Transform:   transform.FromField(regionField),
+			Description: "The AWS Region in which the resource is located.",
+		},
+		&plugin.Column{
+			Name:        "account_id",
+			Type:        proto.ColumnType_STRING,
+			Hydrate:     getCommonColumns,
+			Description: "The AWS Account ID in which the resource is located.",
+			Transform:   transform.FromCamel(),
+		},
+	)
+}
+
 // struct to store the common column data
 type awsCommonColumnData struct {
 	Partition, Region, AccountId string
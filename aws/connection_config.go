@@ -11,6 +11,10 @@ type awsConfig struct {
 	AccessKey    *string  `cty:"access_key"`
 	SecretKey    *string  `cty:"secret_key"`
 	SessionToken *string  `cty:"session_token"`
+
+	// Path to a Parliament IAM permissions catalog (iam_definition.json) which
+	// replaces the catalog built into the plugin
+	IamPermissionsCatalogPath *string `cty:"iam_permissions_catalog_path"`
 }
 
 var ConfigSchema = map[string]*schema.Attribute{
@@ -30,6 +34,9 @@ var ConfigSchema = map[string]*schema.Attribute{
 	"session_token": {
 		Type: schema.TypeString,
 	},
+	"iam_permissions_catalog_path": {
+		Type: schema.TypeString,
+	},
 }
 
 func ConfigInstance() interface{} {
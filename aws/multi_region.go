@@ -47,3 +47,26 @@ func getInvalidRegions(regions []string) []string {
 	}
 	return invalidRegions
 }
+
+// getQueriedRegions returns the regions of the connection, limited to the ones
+// in an '=' or IN qual on the region column. It is used by tables which list
+// all regions in one call rather than with a region matrix.
+func getQueriedRegions(ctx context.Context, d *plugin.QueryData) []string {
+	qualRegions := getStringQualValues(d, matrixKeyRegion)
+
+	var regions []string
+	for _, item := range BuildRegionList(ctx, d.Connection) {
+		region := item[matrixKeyRegion].(string)
+		if qualRegions == nil || helpers.StringSliceContains(qualRegions, region) {
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
+
+// isGlobalQueried returns true unless a qual on the region column excludes the
+// global region
+func isGlobalQueried(d *plugin.QueryData) bool {
+	qualRegions := getStringQualValues(d, matrixKeyRegion)
+	return qualRegions == nil || helpers.StringSliceContains(qualRegions, "global")
+}
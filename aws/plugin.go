@@ -39,10 +39,17 @@ func Plugin(ctx context.Context) *plugin.Plugin {
 			"aws_api_gatewayv2_stage":                tableAwsAPIGatewayV2Stage(ctx),
 			"aws_availability_zone":                  tableAwsAvailabilityZone(ctx),
 			"aws_cloudformation_stack":               tableAwsCloudFormationStack(ctx),
+			"aws_cloudtrail_event":                   tableAwsCloudtrailEvent(ctx),
+			"aws_cloudtrail_trail":                   tableAwsCloudtrailTrail(ctx),
 			"aws_cloudwatch_log_group":               tableAwsCloudwatchLogGroup(ctx),
 			"aws_cloudwatch_log_metric_filter":       tableAwsCloudwatchLogMetricFilter(ctx),
 			"aws_cloudwatch_log_stream":              tableAwsCloudwatchLogStream(ctx),
 			"aws_config_configuration_recorder":      tableAwsConfigConfigurationRecorder(ctx),
+			"aws_config_resource_history":            tableAwsConfigResourceHistory(ctx),
+			"aws_config_rule":                        tableAwsConfigRule(ctx),
+			"aws_config_rule_evaluation":             tableAwsConfigRuleEvaluation(ctx),
+			"aws_directconnect_connection":           tableAwsDirectConnectConnection(ctx),
+			"aws_directconnect_virtual_interface":    tableAwsDirectConnectVirtualInterface(ctx),
 			"aws_dynamodb_backup":                    tableAwsDynamoDBBackup(ctx),
 			"aws_dynamodb_global_table":              tableAwsDynamoDBGlobalTable(ctx),
 			"aws_dynamodb_table":                     tableAwsDynamoDBTable(ctx),
@@ -59,10 +66,15 @@ func Plugin(ctx context.Context) *plugin.Plugin {
 			"aws_ec2_key_pair":                       tableAwsEc2KeyPair(ctx),
 			"aws_ec2_launch_configuration":           tableAwsEc2LaunchConfiguration(ctx),
 			"aws_ec2_load_balancer_listener":         tableAwsEc2ApplicationLoadBalancerListener(ctx),
+			"aws_ec2_load_balancer_listener_rule":    tableAwsEc2LoadBalancerListenerRule(ctx),
+			"aws_ec2_load_balancer_ssl_policy":       tableAwsEc2LoadBalancerSslPolicy(ctx),
 			"aws_ec2_network_interface":              tableAwsEc2NetworkInterface(ctx),
 			"aws_ec2_network_load_balancer":          tableAwsEc2NetworkLoadBalancer(ctx),
 			"aws_ec2_target_group":                   tableAwsEc2TargetGroup(ctx),
+			"aws_ec2_target_health":                  tableAwsEc2TargetHealth(ctx),
 			"aws_ec2_transit_gateway":                tableAwsEc2TransitGateway(ctx),
+			"aws_ec2_transit_gateway_attachment":     tableAwsEc2TransitGatewayAttachment(ctx),
+			"aws_ec2_transit_gateway_route":          tableAwsEc2TransitGatewayRoute(ctx),
 			"aws_ec2_transit_gateway_route_table":    tableAwsEc2TransitGatewayRouteTable(ctx),
 			"aws_ec2_transit_gateway_vpc_attachment": tableAwsEc2TransitGatewayVpcAttachment(ctx),
 			"aws_iam_access_advisor":                 tableAwsIamAccessAdvisor(ctx),
@@ -70,17 +82,31 @@ func Plugin(ctx context.Context) *plugin.Plugin {
 			"aws_iam_account_password_policy":        tableAwsIamAccountPasswordPolicy(ctx),
 			"aws_iam_account_summary":                tableAwsIamAccountSummary(ctx),
 			"aws_iam_action":                         tableAwsIamAction(ctx),
+			"aws_iam_condition_key":                  tableAwsIamConditionKey(ctx),
 			"aws_iam_credential_report":              tableAwsIamCredentialReport(ctx),
+			"aws_iam_custom_policy_simulator":        tableAwsIamCustomPolicySimulator(ctx),
 			"aws_iam_group":                          tableAwsIamGroup(ctx),
 			"aws_iam_policy":                         tableAwsIamPolicy(ctx),
+			"aws_iam_policy_action":                  tableAwsIamPolicyAction(ctx),
+			"aws_iam_policy_evaluation":              tableAwsIamPolicyEvaluation(ctx),
+			"aws_iam_policy_finding":                 tableAwsIamPolicyFinding(ctx),
 			"aws_iam_policy_simulator":               tableAwsIamPolicySimulator(ctx),
+			"aws_iam_principal_effective_policy":     tableAwsIamPrincipalEffectivePolicy(ctx),
+			"aws_iam_privilege_escalation_path":      tableAwsIamPrivilegeEscalationPath(ctx),
+			"aws_iam_resource_type":                  tableAwsIamResourceType(ctx),
 			"aws_iam_role":                           tableAwsIamRole(ctx),
+			"aws_iam_role_trust":                     tableAwsIamRoleTrust(ctx),
+			"aws_iam_server_certificate":             tableAwsIamServerCertificate(ctx),
+			"aws_iam_unused_permission":              tableAwsIamUnusedPermission(ctx),
 			"aws_iam_user":                           tableAwsIamUser(ctx),
 			"aws_iam_virtual_mfa_device":             tableAwsIamVirtualMfaDevice(ctx),
 			"aws_kms_key":                            tableAwsKmsKey(ctx),
 			"aws_lambda_alias":                       tableAwsLambdaAlias(ctx),
 			"aws_lambda_function":                    tableAwsLambdaFunction(ctx),
 			"aws_lambda_version":                     tableAwsLambdaVersion(ctx),
+			"aws_organizations_account":              tableAwsOrganizationsAccount(ctx),
+			"aws_organizations_organizational_unit":  tableAwsOrganizationsOrganizationalUnit(ctx),
+			"aws_organizations_policy":               tableAwsOrganizationsPolicy(ctx),
 			"aws_rds_db_cluster":                     tableAwsRDSDBCluster(ctx),
 			"aws_rds_db_cluster_parameter_group":     tableAwsRDSDBClusterParameterGroup(ctx),
 			"aws_rds_db_cluster_snapshot":            tableAwsRDSDBClusterSnapshot(ctx),
@@ -90,10 +116,12 @@ func Plugin(ctx context.Context) *plugin.Plugin {
 			"aws_rds_db_snapshot":                    tableAwsRDSDBSnapshot(ctx),
 			"aws_rds_db_subnet_group":                tableAwsRDSDBSubnetGroup(ctx),
 			"aws_region":                             tableAwsRegion(ctx),
+			"aws_resource_policy":                    tableAwsResourcePolicy(ctx),
 			"aws_route53_record":                     tableAwsRoute53Record(ctx),
 			"aws_route53_zone":                       tableAwsRoute53Zone(ctx),
 			"aws_s3_account_settings":                tableAwsS3AccountSettings(ctx),
 			"aws_s3_bucket":                          tableAwsS3Bucket(ctx),
+			"aws_s3_object":                          tableAwsS3Object(ctx),
 			"aws_sns_topic":                          tableAwsSnsTopic(ctx),
 			"aws_sns_topic_subscription":             tableAwsSnsTopicSubscription(ctx),
 			"aws_sqs_queue":                          tableAwsSqsQueue(ctx),
@@ -101,6 +129,7 @@ func Plugin(ctx context.Context) *plugin.Plugin {
 			"aws_ssm_parameter":                      tableAwsSSMParameter(ctx),
 			"aws_ssm_patch_baseline":                 tableAwsSSMPatchBaseline(ctx),
 			"aws_vpc":                                tableAwsVpc(ctx),
+			"aws_vpc_cidr_overlap":                   tableAwsVpcCidrOverlap(ctx),
 			"aws_vpc_customer_gateway":               tableAwsVpcCustomerGateway(ctx),
 			"aws_vpc_dhcp_options":                   tableAwsVpcDhcpOptions(ctx),
 			"aws_vpc_egress_only_internet_gateway":   tableAwsVpcEgressOnlyIGW(ctx),
@@ -108,14 +137,24 @@ func Plugin(ctx context.Context) *plugin.Plugin {
 			"aws_vpc_endpoint":                       tableAwsVpcEndpoint(ctx),
 			"aws_vpc_endpoint_service":               tableAwsVpcEndpointService(ctx),
 			"aws_vpc_flow_log":                       tableAwsVpcFlowlog(ctx),
+			"aws_vpc_flow_log_record":                tableAwsVpcFlowLogRecord(ctx),
 			"aws_vpc_internet_gateway":               tableAwsVpcInternetGateway(ctx),
+			"aws_vpc_managed_prefix_list":            tableAwsVpcManagedPrefixList(ctx),
+			"aws_vpc_managed_prefix_list_entry":      tableAwsVpcManagedPrefixListEntry(ctx),
 			"aws_vpc_nat_gateway":                    tableAwsVpcNatGateway(ctx),
 			"aws_vpc_network_acl":                    tableAwsVpcNetworkACL(ctx),
+			"aws_vpc_network_acl_evaluation":         tableAwsVpcNetworkACLEvaluation(ctx),
+			"aws_vpc_network_acl_rule":               tableAwsVpcNetworkACLRule(ctx),
+			"aws_vpc_peering_connection":             tableAwsVpcPeeringConnection(ctx),
+			"aws_vpc_reachability":                   tableAwsVpcReachability(ctx),
 			"aws_vpc_route":                          tableAwsVpcRoute(ctx),
 			"aws_vpc_route_table":                    tableAwsVpcRouteTable(ctx),
 			"aws_vpc_security_group":                 tableAwsVpcSecurityGroup(ctx),
+			"aws_vpc_security_group_exposure":        tableAwsVpcSecurityGroupExposure(ctx),
 			"aws_vpc_security_group_rule":            tableAwsVpcSecurityGroupRule(ctx),
 			"aws_vpc_subnet":                         tableAwsVpcSubnet(ctx),
+			"aws_vpc_subnet_utilization":             tableAwsVpcSubnetUtilization(ctx),
+			"aws_vpc_vpn_connection":                 tableAwsVpcVpnConnection(ctx),
 			"aws_vpc_vpn_gateway":                    tableAwsVpcVpnGateway(ctx),
 		},
 	}
@@ -0,0 +1,645 @@
+package aws
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+//
+// Offline evaluation of IAM policies in canonical form.
+// https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_policies_evaluation-logic.html
+//
+
+// The decisions returned by the evaluator use the same values as the IAM policy
+// simulator
+const (
+	policyDecisionAllowed      = "allowed"
+	policyDecisionExplicitDeny = "explicitDeny"
+	policyDecisionImplicitDeny = "implicitDeny"
+)
+
+// policyVariablesVersion is the first policy language version which supports
+// ${...} policy variables. In older policies they are plain text.
+const policyVariablesVersion = "2012-10-17"
+
+// policyEvaluationRequest describes the request that a set of policies is
+// evaluated against
+type policyEvaluationRequest struct {
+	Action    string
+	Resource  string
+	Principal string
+	// Context holds the request context keys, in lower case, and their values
+	Context map[string][]string
+}
+
+// policyEvaluationResult is the outcome of evaluating a set of policies
+type policyEvaluationResult struct {
+	Decision          string
+	MatchedStatements []Statement
+}
+
+// newPolicyEvaluationRequest builds a request, parsing the request context from
+// a JSON object of key to string, number, boolean or array values
+func newPolicyEvaluationRequest(action string, resource string, principal string, contextJSON string) (*policyEvaluationRequest, error) {
+	request := &policyEvaluationRequest{
+		Action:    action,
+		Resource:  resource,
+		Principal: principal,
+		Context:   map[string][]string{},
+	}
+
+	if strings.TrimSpace(contextJSON) == "" {
+		return request, nil
+	}
+
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(contextJSON), &raw); err != nil {
+		return nil, fmt.Errorf("request context must be a JSON object: %s", err)
+	}
+	for key, value := range raw {
+		if value == nil {
+			continue
+		}
+		values, err := toSliceOfStrings(value)
+		if err != nil {
+			return nil, err
+		}
+		// Context keys are case insensitive
+		request.Context[strings.ToLower(key)] = values
+	}
+
+	return request, nil
+}
+
+// evaluatePolicies evaluates the request against all the statements of the
+// given policies. An explicit deny in any statement overrides any allow, and
+// without an allow the request is implicitly denied.
+func evaluatePolicies(policies []Policy, request *policyEvaluationRequest) (*policyEvaluationResult, error) {
+	result := &policyEvaluationResult{
+		Decision:          policyDecisionImplicitDeny,
+		MatchedStatements: []Statement{},
+	}
+
+	allowed, denied := false, false
+	for _, policy := range policies {
+		useVariables := policy.Version == policyVariablesVersion
+		for _, statement := range policy.Statements {
+			matched, err := statementMatches(statement, request, useVariables)
+			if err != nil {
+				return nil, err
+			}
+			if !matched {
+				continue
+			}
+			result.MatchedStatements = append(result.MatchedStatements, statement)
+			switch statement.Effect {
+			case "Deny":
+				denied = true
+			case "Allow":
+				allowed = true
+			}
+		}
+	}
+
+	if denied {
+		result.Decision = policyDecisionExplicitDeny
+	} else if allowed {
+		result.Decision = policyDecisionAllowed
+	}
+
+	return result, nil
+}
+
+// statementMatches returns true if the statement applies to the request
+func statementMatches(statement Statement, request *policyEvaluationRequest, useVariables bool) (bool, error) {
+	if !statementActionMatches(statement, request.Action) {
+		return false, nil
+	}
+	if !statementResourceMatches(statement, request, useVariables) {
+		return false, nil
+	}
+	if !statementPrincipalMatches(statement, request.Principal) {
+		return false, nil
+	}
+	return conditionsMatch(statement.Condition, request, useVariables)
+}
+
+func statementActionMatches(statement Statement, action string) bool {
+	action = strings.ToLower(action)
+
+	// Actions are stored in lower case in canonical form
+	if len(statement.Action) > 0 {
+		for _, pattern := range statement.Action {
+			if wildcardMatch(pattern, action) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(statement.NotAction) > 0 {
+		for _, pattern := range statement.NotAction {
+			if wildcardMatch(pattern, action) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return false
+}
+
+func statementResourceMatches(statement Statement, request *policyEvaluationRequest, useVariables bool) bool {
+	matchesAny := func(patterns []string) bool {
+		for _, pattern := range patterns {
+			if useVariables {
+				substituted, ok := substitutePolicyVariables(pattern, request.Context, true)
+				if !ok {
+					continue
+				}
+				pattern = substituted
+			} else {
+				pattern = escapeWildcards(pattern, false)
+			}
+			if arnMatch(pattern, request.Resource) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(statement.Resource) > 0 {
+		return matchesAny(statement.Resource)
+	}
+	if len(statement.NotResource) > 0 {
+		return !matchesAny(statement.NotResource)
+	}
+
+	// Trust policies have no resource element
+	return true
+}
+
+// statementPrincipalMatches checks the principal of resource-based policies. It
+// is only applied when the request names a principal.
+func statementPrincipalMatches(statement Statement, principal string) bool {
+	if principal == "" {
+		return true
+	}
+	if len(statement.Principal) > 0 {
+		return principalMatches(statement.Principal, principal)
+	}
+	if len(statement.NotPrincipal) > 0 {
+		return !principalMatches(statement.NotPrincipal, principal)
+	}
+	return true
+}
+
+func principalMatches(policyPrincipal Principal, principal string) bool {
+	for _, values := range policyPrincipal {
+		ids, ok := values.([]string)
+		if !ok {
+			continue
+		}
+		for _, id := range ids {
+			if id == "*" || id == principal {
+				return true
+			}
+			// An account ID or root ARN matches every principal in the account
+			account := id
+			if strings.HasPrefix(id, "arn:") && strings.HasSuffix(id, ":root") {
+				account = arnAccountID(id)
+			}
+			if isAccountID(account) && (account == principal || arnAccountID(principal) == account) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//// CONDITIONS
+
+// conditionsMatch returns true if all the conditions of a statement match. Each
+// operator and each key within an operator must match; any of the values given
+// for a key may match.
+func conditionsMatch(conditions map[string]interface{}, request *policyEvaluationRequest, useVariables bool) (bool, error) {
+	for operator, keys := range conditions {
+		keyValues, ok := keys.(map[string]interface{})
+		if !ok {
+			return false, fmt.Errorf("invalid condition block for operator %s", operator)
+		}
+		for key, values := range keyValues {
+			policyValues, ok := values.([]string)
+			if !ok {
+				return false, fmt.Errorf("invalid condition values for %s %s", operator, key)
+			}
+			matched, err := conditionMatches(operator, strings.ToLower(key), policyValues, request, useVariables)
+			if err != nil {
+				return false, err
+			}
+			if !matched {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// conditionOperator is a parsed condition operator, e.g.
+// ForAllValues:StringNotLikeIfExists
+type conditionOperator struct {
+	name      string
+	forAll    bool
+	forAny    bool
+	ifExists  bool
+	negated   bool
+	wildcards bool
+	match     func(contextValue string, policyValue string) bool
+}
+
+func parseConditionOperator(operator string) (*conditionOperator, error) {
+	op := &conditionOperator{}
+	name := strings.ToLower(operator)
+
+	if strings.HasPrefix(name, "forallvalues:") {
+		op.forAll = true
+		name = strings.TrimPrefix(name, "forallvalues:")
+	} else if strings.HasPrefix(name, "foranyvalue:") {
+		op.forAny = true
+		name = strings.TrimPrefix(name, "foranyvalue:")
+	}
+	if strings.HasSuffix(name, "ifexists") && name != "null" {
+		op.ifExists = true
+		name = strings.TrimSuffix(name, "ifexists")
+	}
+	op.name = name
+
+	switch name {
+	case "stringequals", "stringnotequals":
+		op.match = func(c, p string) bool { return c == p }
+	case "stringequalsignorecase", "stringnotequalsignorecase":
+		op.match = strings.EqualFold
+	case "stringlike", "stringnotlike":
+		op.wildcards = true
+		op.match = func(c, p string) bool { return wildcardMatch(p, c) }
+	case "numericequals", "numericnotequals":
+		op.match = compareNumbers(func(c, p float64) bool { return c == p })
+	case "numericlessthan":
+		op.match = compareNumbers(func(c, p float64) bool { return c < p })
+	case "numericlessthanequals":
+		op.match = compareNumbers(func(c, p float64) bool { return c <= p })
+	case "numericgreaterthan":
+		op.match = compareNumbers(func(c, p float64) bool { return c > p })
+	case "numericgreaterthanequals":
+		op.match = compareNumbers(func(c, p float64) bool { return c >= p })
+	case "dateequals", "datenotequals":
+		op.match = compareDates(func(c, p time.Time) bool { return c.Equal(p) })
+	case "datelessthan":
+		op.match = compareDates(func(c, p time.Time) bool { return c.Before(p) })
+	case "datelessthanequals":
+		op.match = compareDates(func(c, p time.Time) bool { return !c.After(p) })
+	case "dategreaterthan":
+		op.match = compareDates(func(c, p time.Time) bool { return c.After(p) })
+	case "dategreaterthanequals":
+		op.match = compareDates(func(c, p time.Time) bool { return !c.Before(p) })
+	case "bool":
+		op.match = strings.EqualFold
+	case "binaryequals":
+		op.match = func(c, p string) bool { return c == p }
+	case "ipaddress", "notipaddress":
+		op.match = ipAddressMatch
+	case "arnequals", "arnlike", "arnnotequals", "arnnotlike":
+		op.wildcards = true
+		op.match = func(c, p string) bool { return arnMatch(p, c) }
+	case "null":
+		// handled separately, as it tests for the presence of the key
+	default:
+		return nil, fmt.Errorf("unsupported condition operator: %s", operator)
+	}
+
+	switch name {
+	case "stringnotequals", "stringnotequalsignorecase", "stringnotlike", "numericnotequals", "datenotequals", "notipaddress", "arnnotequals", "arnnotlike":
+		op.negated = true
+	}
+
+	return op, nil
+}
+
+func conditionMatches(operator string, key string, policyValues []string, request *policyEvaluationRequest, useVariables bool) (bool, error) {
+	op, err := parseConditionOperator(operator)
+	if err != nil {
+		return false, err
+	}
+
+	contextValues, present := request.Context[key]
+
+	// Null tests whether the key is absent (true) or present (false)
+	if op.name == "null" {
+		for _, value := range policyValues {
+			if strings.EqualFold(value, "true") != present {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+
+	if !present || len(contextValues) == 0 {
+		switch {
+		case op.ifExists, op.forAll:
+			return true, nil
+		case op.forAny:
+			return false, nil
+		}
+		// A negated operator matches when the key is missing
+		return op.negated, nil
+	}
+
+	// Resolve policy variables in the condition values. Values which reference a
+	// variable missing from the request are ignored.
+	resolved := make([]string, 0, len(policyValues))
+	for _, value := range policyValues {
+		if useVariables {
+			substituted, ok := substitutePolicyVariables(value, request.Context, op.wildcards)
+			if !ok {
+				continue
+			}
+			value = substituted
+		} else if op.wildcards {
+			value = escapeWildcards(value, false)
+		}
+		resolved = append(resolved, value)
+	}
+
+	valueMatches := func(contextValue string) bool {
+		for _, policyValue := range resolved {
+			if op.match(contextValue, policyValue) {
+				return !op.negated
+			}
+		}
+		return op.negated
+	}
+
+	switch {
+	case op.forAll:
+		for _, value := range contextValues {
+			if !valueMatches(value) {
+				return false, nil
+			}
+		}
+		return true, nil
+	case op.forAny:
+		for _, value := range contextValues {
+			if valueMatches(value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	case op.negated:
+		// None of the request values may match any of the policy values
+		for _, value := range contextValues {
+			if !valueMatches(value) {
+				return false, nil
+			}
+		}
+		return true, nil
+	default:
+		for _, value := range contextValues {
+			if valueMatches(value) {
+				return true, nil
+			}
+		}
+		return false, nil
+	}
+}
+
+func compareNumbers(compare func(float64, float64) bool) func(string, string) bool {
+	return func(contextValue string, policyValue string) bool {
+		c, err := strconv.ParseFloat(contextValue, 64)
+		if err != nil {
+			return false
+		}
+		p, err := strconv.ParseFloat(policyValue, 64)
+		if err != nil {
+			return false
+		}
+		return compare(c, p)
+	}
+}
+
+func compareDates(compare func(time.Time, time.Time) bool) func(string, string) bool {
+	return func(contextValue string, policyValue string) bool {
+		c, ok := parsePolicyDate(contextValue)
+		if !ok {
+			return false
+		}
+		p, ok := parsePolicyDate(policyValue)
+		if !ok {
+			return false
+		}
+		return compare(c, p)
+	}
+}
+
+// parsePolicyDate parses the ISO 8601 formats and epoch times accepted by IAM
+func parsePolicyDate(value string) (time.Time, bool) {
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05Z0700", "2006-01-02T15:04Z07:00", "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	if epoch, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(epoch, 0).UTC(), true
+	}
+	return time.Time{}, false
+}
+
+func ipAddressMatch(contextValue string, policyValue string) bool {
+	ip := net.ParseIP(contextValue)
+	if ip == nil {
+		return false
+	}
+	if !strings.Contains(policyValue, "/") {
+		other := net.ParseIP(policyValue)
+		return other != nil && other.Equal(ip)
+	}
+	_, network, err := net.ParseCIDR(policyValue)
+	if err != nil {
+		return false
+	}
+	return network.Contains(ip)
+}
+
+//// POLICY VARIABLES
+
+// substitutePolicyVariables replaces ${...} policy variables with their values
+// from the request context. The special variables ${*}, ${?} and ${$} stand for
+// the literal characters. A default may be given as ${aws:username, 'anonymous'}.
+// If forWildcards is set, the result is a pattern for wildcardMatch in which the
+// substituted text is escaped so it only matches literally.
+// It returns false if a variable is not in the request context and has no
+// default.
+func substitutePolicyVariables(value string, requestContext map[string][]string, forWildcards bool) (string, bool) {
+	var sb strings.Builder
+	literal := func(s string) {
+		if forWildcards {
+			s = escapeWildcards(s, true)
+		}
+		sb.WriteString(s)
+	}
+
+	rest := value
+	for {
+		start := strings.Index(rest, "${")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(rest[start:], "}")
+		if end < 0 {
+			break
+		}
+		end += start
+
+		// Text outside of variables keeps its wildcards
+		if forWildcards {
+			sb.WriteString(escapeWildcards(rest[:start], false))
+		} else {
+			sb.WriteString(rest[:start])
+		}
+
+		variable := rest[start+2 : end]
+		rest = rest[end+1:]
+
+		switch variable {
+		case "*", "?", "$":
+			literal(variable)
+			continue
+		}
+
+		key, defaultValue, hasDefault := variable, "", false
+		if comma := strings.Index(variable, ","); comma >= 0 {
+			key = variable[:comma]
+			defaultValue = strings.Trim(strings.TrimSpace(variable[comma+1:]), "'")
+			hasDefault = true
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+
+		// Variables must be single valued
+		if values, ok := requestContext[key]; ok && len(values) == 1 {
+			literal(values[0])
+		} else if hasDefault {
+			literal(defaultValue)
+		} else {
+			return "", false
+		}
+	}
+
+	if forWildcards {
+		sb.WriteString(escapeWildcards(rest, false))
+	} else {
+		sb.WriteString(rest)
+	}
+
+	return sb.String(), true
+}
+
+//// MATCHING
+
+// escapeWildcards escapes backslashes, and optionally the * and ? wildcards, so
+// the text is matched literally by wildcardMatch
+func escapeWildcards(s string, all bool) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	if all {
+		s = strings.ReplaceAll(s, "*", `\*`)
+		s = strings.ReplaceAll(s, "?", `\?`)
+	}
+	return s
+}
+
+// wildcardMatch matches a value against a pattern in which * matches any
+// sequence of characters, ? matches any single character and \ escapes the
+// next character
+func wildcardMatch(pattern string, value string) bool {
+	p, v := []rune(pattern), []rune(value)
+	pi, vi := 0, 0
+	starP, starV := -1, 0
+
+	for vi < len(v) {
+		if pi < len(p) {
+			switch {
+			case p[pi] == '*':
+				starP, starV = pi, vi
+				pi++
+				continue
+			case p[pi] == '?':
+				pi++
+				vi++
+				continue
+			case p[pi] == '\\' && pi+1 < len(p):
+				if p[pi+1] == v[vi] {
+					pi += 2
+					vi++
+					continue
+				}
+			case p[pi] == v[vi]:
+				pi++
+				vi++
+				continue
+			}
+		}
+		// Backtrack to the last star, consuming one more character
+		if starP < 0 {
+			return false
+		}
+		pi = starP + 1
+		starV++
+		vi = starV
+	}
+
+	for pi < len(p) && p[pi] == '*' {
+		pi++
+	}
+	return pi == len(p)
+}
+
+// arnMatch matches an ARN against an ARN pattern. Each of the six colon
+// separated components is matched separately, as IAM does, so a wildcard in
+// one component does not span into the next. Patterns which are not ARNs, such
+// as *, are matched against the whole value.
+func arnMatch(pattern string, value string) bool {
+	if !strings.HasPrefix(pattern, "arn:") || !strings.HasPrefix(value, "arn:") {
+		return wildcardMatch(pattern, value)
+	}
+
+	patternParts := strings.SplitN(pattern, ":", 6)
+	valueParts := strings.SplitN(value, ":", 6)
+	if len(patternParts) != 6 || len(valueParts) != 6 {
+		return wildcardMatch(pattern, value)
+	}
+
+	for i := range patternParts {
+		if !wildcardMatch(patternParts[i], valueParts[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// arnAccountID returns the account ID component of an ARN
+func arnAccountID(arn string) string {
+	parts := strings.SplitN(arn, ":", 6)
+	if len(parts) != 6 || parts[0] != "arn" {
+		return ""
+	}
+	return parts[4]
+}
+
+func isAccountID(s string) bool {
+	if len(s) != 12 {
+		return false
+	}
+	_, err := strconv.ParseUint(s, 10, 64)
+	return err == nil
+}
@@ -0,0 +1,245 @@
+package aws
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestEvaluatePolicy(t *testing.T) {
+	type testCase struct {
+		name      string
+		policy    string
+		action    string
+		resource  string
+		principal string
+		context   string
+		expected  string
+	}
+
+	cases := []testCase{
+		{
+			name:     "allow with action wildcard",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "s3:Get*", "Resource": "*"}}`,
+			action:   "s3:GetObject",
+			resource: "arn:aws:s3:::bucket/key",
+			expected: policyDecisionAllowed,
+		},
+		{
+			name:     "actions are case insensitive",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "S3:GETOBJECT", "Resource": "*"}}`,
+			action:   "s3:GetObject",
+			resource: "arn:aws:s3:::bucket/key",
+			expected: policyDecisionAllowed,
+		},
+		{
+			name:     "no matching action",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "s3:Get*", "Resource": "*"}}`,
+			action:   "s3:PutObject",
+			resource: "arn:aws:s3:::bucket/key",
+			expected: policyDecisionImplicitDeny,
+		},
+		{
+			name:     "explicit deny overrides allow",
+			policy:   `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "*", "Resource": "*"}, {"Effect": "Deny", "Action": "s3:*", "Resource": "arn:aws:s3:::secret/*"}]}`,
+			action:   "s3:GetObject",
+			resource: "arn:aws:s3:::secret/key",
+			expected: policyDecisionExplicitDeny,
+		},
+		{
+			name:     "not action",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "NotAction": "iam:*", "Resource": "*"}}`,
+			action:   "iam:CreateUser",
+			resource: "*",
+			expected: policyDecisionImplicitDeny,
+		},
+		{
+			name:     "not resource",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "s3:*", "NotResource": "arn:aws:s3:::secret/*"}}`,
+			action:   "s3:GetObject",
+			resource: "arn:aws:s3:::public/key",
+			expected: policyDecisionAllowed,
+		},
+		{
+			name:     "resource wildcards do not span arn components",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "sqs:*", "Resource": "arn:aws:sqs:us-east-1:*"}}`,
+			action:   "sqs:SendMessage",
+			resource: "arn:aws:sqs:us-west-2:123456789012:queue",
+			expected: policyDecisionImplicitDeny,
+		},
+		{
+			name:     "policy variable",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::home/${aws:username}/*"}}`,
+			action:   "s3:GetObject",
+			resource: "arn:aws:s3:::home/bob/notes.txt",
+			context:  `{"aws:username": "bob"}`,
+			expected: policyDecisionAllowed,
+		},
+		{
+			name:     "policy variable for another user",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::home/${aws:username}/*"}}`,
+			action:   "s3:GetObject",
+			resource: "arn:aws:s3:::home/alice/notes.txt",
+			context:  `{"aws:username": "bob"}`,
+			expected: policyDecisionImplicitDeny,
+		},
+		{
+			name:     "policy variable values are matched literally",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::home/${aws:username}/*"}}`,
+			action:   "s3:GetObject",
+			resource: "arn:aws:s3:::home/alice/notes.txt",
+			context:  `{"aws:username": "*"}`,
+			expected: policyDecisionImplicitDeny,
+		},
+		{
+			name:     "policy variables are text in old policies",
+			policy:   `{"Version": "2008-10-17", "Statement": {"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::home/${aws:username}/*"}}`,
+			action:   "s3:GetObject",
+			resource: "arn:aws:s3:::home/bob/notes.txt",
+			context:  `{"aws:username": "bob"}`,
+			expected: policyDecisionImplicitDeny,
+		},
+		{
+			name:     "missing policy variable without default",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::home/${aws:username}/*"}}`,
+			action:   "s3:GetObject",
+			resource: "arn:aws:s3:::home/bob/notes.txt",
+			expected: policyDecisionImplicitDeny,
+		},
+		{
+			name:     "missing policy variable with default",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::home/${aws:username, 'guest'}/*"}}`,
+			action:   "s3:GetObject",
+			resource: "arn:aws:s3:::home/guest/notes.txt",
+			expected: policyDecisionAllowed,
+		},
+		{
+			name:     "ip address condition",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Deny", "Action": "*", "Resource": "*", "Condition": {"NotIpAddress": {"aws:SourceIp": ["10.0.0.0/8", "192.168.1.1"]}}}}`,
+			action:   "ec2:RunInstances",
+			resource: "*",
+			context:  `{"aws:SourceIp": "8.8.8.8"}`,
+			expected: policyDecisionExplicitDeny,
+		},
+		{
+			name:     "ip address condition inside range",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Deny", "Action": "*", "Resource": "*", "Condition": {"NotIpAddress": {"aws:SourceIp": ["10.0.0.0/8", "192.168.1.1"]}}}}`,
+			action:   "ec2:RunInstances",
+			resource: "*",
+			context:  `{"aws:SourceIp": "10.1.2.3"}`,
+			expected: policyDecisionImplicitDeny,
+		},
+		{
+			name:     "bool condition with missing key",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "*", "Resource": "*", "Condition": {"Bool": {"aws:MultiFactorAuthPresent": "true"}}}}`,
+			action:   "iam:DeleteUser",
+			resource: "*",
+			expected: policyDecisionImplicitDeny,
+		},
+		{
+			name:     "bool condition if exists with missing key",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "*", "Resource": "*", "Condition": {"BoolIfExists": {"aws:MultiFactorAuthPresent": "true"}}}}`,
+			action:   "iam:DeleteUser",
+			resource: "*",
+			expected: policyDecisionAllowed,
+		},
+		{
+			name:     "null condition",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Deny", "Action": "ec2:RunInstances", "Resource": "*", "Condition": {"Null": {"aws:RequestTag/owner": "true"}}}}`,
+			action:   "ec2:RunInstances",
+			resource: "*",
+			expected: policyDecisionExplicitDeny,
+		},
+		{
+			name:     "numeric and date conditions",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "sts:AssumeRole", "Resource": "*", "Condition": {"NumericLessThan": {"aws:MultiFactorAuthAge": "3600"}, "DateLessThan": {"aws:CurrentTime": "2030-01-01T00:00:00Z"}}}}`,
+			action:   "sts:AssumeRole",
+			resource: "*",
+			context:  `{"aws:MultiFactorAuthAge": 300, "aws:CurrentTime": "2025-06-01T12:00:00Z"}`,
+			expected: policyDecisionAllowed,
+		},
+		{
+			name:     "for all values",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "ec2:CreateTags", "Resource": "*", "Condition": {"ForAllValues:StringEquals": {"aws:TagKeys": ["owner", "project"]}}}}`,
+			action:   "ec2:CreateTags",
+			resource: "*",
+			context:  `{"aws:TagKeys": ["owner", "cost-center"]}`,
+			expected: policyDecisionImplicitDeny,
+		},
+		{
+			name:     "for any value",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "ec2:CreateTags", "Resource": "*", "Condition": {"ForAnyValue:StringLike": {"aws:TagKeys": "proj*"}}}}`,
+			action:   "ec2:CreateTags",
+			resource: "*",
+			context:  `{"aws:TagKeys": ["owner", "project"]}`,
+			expected: policyDecisionAllowed,
+		},
+		{
+			name:     "string not equals with missing key",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Deny", "Action": "*", "Resource": "*", "Condition": {"StringNotEquals": {"aws:PrincipalOrgID": "o-abc"}}}}`,
+			action:   "s3:GetObject",
+			resource: "*",
+			expected: policyDecisionExplicitDeny,
+		},
+		{
+			name:      "resource policy principal account",
+			policy:    `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::111122223333:root"}, "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/*"}}`,
+			action:    "s3:GetObject",
+			resource:  "arn:aws:s3:::bucket/key",
+			principal: "arn:aws:iam::111122223333:role/reader",
+			expected:  policyDecisionAllowed,
+		},
+		{
+			name:      "resource policy other account",
+			policy:    `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Principal": {"AWS": "111122223333"}, "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/*"}}`,
+			action:    "s3:GetObject",
+			resource:  "arn:aws:s3:::bucket/key",
+			principal: "arn:aws:iam::444455556666:role/reader",
+			expected:  policyDecisionImplicitDeny,
+		},
+	}
+
+	for _, c := range cases {
+		var policy Policy
+		if err := json.Unmarshal([]byte(c.policy), &policy); err != nil {
+			t.Errorf("Case '%s': unmarshal failed: %v", c.name, err)
+			continue
+		}
+		request, err := newPolicyEvaluationRequest(c.action, c.resource, c.principal, c.context)
+		if err != nil {
+			t.Errorf("Case '%s': invalid context: %v", c.name, err)
+			continue
+		}
+		result, err := evaluatePolicies([]Policy{policy}, request)
+		if err != nil {
+			t.Errorf("Case '%s': evaluation failed: %v", c.name, err)
+			continue
+		}
+		if result.Decision != c.expected {
+			t.Errorf("Case '%s': expected %s, got %s", c.name, c.expected, result.Decision)
+		}
+	}
+}
+
+func TestWildcardMatch(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		value    string
+		expected bool
+	}{
+		{"*", "", true},
+		{"*", "anything", true},
+		{"s3:get*", "s3:getobject", true},
+		{"s3:get*", "s3:putobject", false},
+		{"s3:?etobject", "s3:getobject", true},
+		{"a*b*c", "aXXbYYc", true},
+		{"a*b*c", "aXXbYY", false},
+		{`home/\*`, "home/*", true},
+		{`home/\*`, "home/bob", false},
+	}
+
+	for _, c := range cases {
+		if actual := wildcardMatch(c.pattern, c.value); actual != c.expected {
+			t.Errorf("wildcardMatch(%q, %q): expected %v, got %v", c.pattern, c.value, c.expected, actual)
+		}
+	}
+}
@@ -0,0 +1,759 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/kms"
+	"github.com/aws/aws-sdk-go/service/lambda"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/sns"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+)
+
+//
+// Collection of the policy documents attached to IAM identities and resources,
+// for tables which analyse policies across services.
+//
+
+// The kinds of policy document that are collected
+const (
+	policySourceManagedPolicy        = "managed_policy"
+	policySourceUserInlinePolicy     = "user_inline_policy"
+	policySourceGroupInlinePolicy    = "group_inline_policy"
+	policySourceRoleInlinePolicy     = "role_inline_policy"
+	policySourceRoleTrustPolicy      = "role_trust_policy"
+	policySourceS3BucketPolicy       = "s3_bucket_policy"
+	policySourceKmsKeyPolicy         = "kms_key_policy"
+	policySourceLambdaFunctionPolicy = "lambda_function_policy"
+	policySourceSnsTopicPolicy       = "sns_topic_policy"
+	policySourceSqsQueuePolicy       = "sqs_queue_policy"
+	policySourceVpcEndpointPolicy    = "vpc_endpoint_policy"
+)
+
+// policySourceInfo is a policy document and the identity or resource it is
+// attached to
+type policySourceInfo struct {
+	SourceType string
+	// The ARN of the managed policy, or of the identity or resource the policy
+	// is attached to
+	SourceArn string
+	// The name of the policy for managed and inline policies, otherwise the name
+	// of the resource
+	SourceName string
+	// The CloudFormation type of the resource, e.g. AWS::S3::Bucket
+	ResourceType string
+	Region       string
+	// The policy document as unescaped JSON
+	Policy string
+}
+
+// isResourcePolicy returns true for resource-based policies, including role
+// trust policies, which have a Principal element
+func (source *policySourceInfo) isResourcePolicy() bool {
+	switch source.SourceType {
+	case policySourceManagedPolicy, policySourceUserInlinePolicy, policySourceGroupInlinePolicy, policySourceRoleInlinePolicy:
+		return false
+	}
+	return true
+}
+
+// resourcePolicyCollector lists the resource-based policies of one kind in a
+// region
+type resourcePolicyCollector struct {
+	sourceType   string
+	resourceType string
+	list         func(ctx context.Context, d *plugin.QueryData, region string, fn func(*policySourceInfo)) error
+}
+
+var resourcePolicyCollectors = []resourcePolicyCollector{
+	{policySourceS3BucketPolicy, "AWS::S3::Bucket", listS3BucketPolicySources},
+	{policySourceKmsKeyPolicy, "AWS::KMS::Key", listKmsKeyPolicySources},
+	{policySourceLambdaFunctionPolicy, "AWS::Lambda::Function", listLambdaFunctionPolicySources},
+	{policySourceSnsTopicPolicy, "AWS::SNS::Topic", listSnsTopicPolicySources},
+	{policySourceSqsQueuePolicy, "AWS::SQS::Queue", listSqsQueuePolicySources},
+	{policySourceVpcEndpointPolicy, "AWS::EC2::VPCEndpoint", listVpcEndpointPolicySources},
+}
+
+//// IAM POLICIES
+
+// listIamPolicySources calls fn for the customer managed policies, the inline
+// policies of users, groups and roles, and the trust policies of roles
+func listIamPolicySources(ctx context.Context, d *plugin.QueryData, fn func(*policySourceInfo)) error {
+	// Create Session
+	svc, err := IAMService(ctx, d)
+	if err != nil {
+		return err
+	}
+
+	input := &iam.GetAccountAuthorizationDetailsInput{
+		Filter: aws.StringSlice([]string{"User", "Group", "Role", "LocalManagedPolicy"}),
+	}
+
+	var docErr error
+	emit := func(sourceType string, arn *string, name *string, document *string) {
+		if docErr != nil || document == nil {
+			return
+		}
+		policy, err := url.QueryUnescape(*document)
+		if err != nil {
+			docErr = err
+			return
+		}
+		fn(&policySourceInfo{
+			SourceType: sourceType,
+			SourceArn:  types.SafeString(arn),
+			SourceName: types.SafeString(name),
+			Region:     "global",
+			Policy:     policy,
+		})
+	}
+
+	err = svc.GetAccountAuthorizationDetailsPages(
+		input,
+		func(page *iam.GetAccountAuthorizationDetailsOutput, isLast bool) bool {
+			for _, policy := range page.Policies {
+				for _, version := range policy.PolicyVersionList {
+					if types.BoolValue(version.IsDefaultVersion) {
+						emit(policySourceManagedPolicy, policy.Arn, policy.PolicyName, version.Document)
+					}
+				}
+			}
+			for _, user := range page.UserDetailList {
+				for _, policy := range user.UserPolicyList {
+					emit(policySourceUserInlinePolicy, user.Arn, policy.PolicyName, policy.PolicyDocument)
+				}
+			}
+			for _, group := range page.GroupDetailList {
+				for _, policy := range group.GroupPolicyList {
+					emit(policySourceGroupInlinePolicy, group.Arn, policy.PolicyName, policy.PolicyDocument)
+				}
+			}
+			for _, role := range page.RoleDetailList {
+				emit(policySourceRoleTrustPolicy, role.Arn, role.RoleName, role.AssumeRolePolicyDocument)
+				for _, policy := range role.RolePolicyList {
+					emit(policySourceRoleInlinePolicy, role.Arn, policy.PolicyName, policy.PolicyDocument)
+				}
+			}
+			return !isLast && docErr == nil
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	return docErr
+}
+
+//// RESOURCE POLICIES
+
+// listResourcePolicySources calls fn for the resource-based policies in a
+// region. If include is set, only the kinds of policy it accepts are listed.
+func listResourcePolicySources(ctx context.Context, d *plugin.QueryData, region string, include func(sourceType string) bool, fn func(*policySourceInfo)) error {
+	for _, collector := range resourcePolicyCollectors {
+		if include != nil && !include(collector.sourceType) {
+			continue
+		}
+		if err := collector.list(ctx, d, region, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func listS3BucketPolicySources(ctx context.Context, d *plugin.QueryData, region string, fn func(*policySourceInfo)) error {
+	defaultRegion := GetDefaultAwsRegion(d)
+
+	// Create Session
+	svc, err := S3Service(ctx, d, defaultRegion)
+	if err != nil {
+		return err
+	}
+
+	buckets, err := svc.ListBuckets(&s3.ListBucketsInput{})
+	if err != nil {
+		return err
+	}
+
+	commonData, err := getCommonColumns(ctx, d, nil)
+	if err != nil {
+		return err
+	}
+	commonColumnData := commonData.(*awsCommonColumnData)
+
+	for _, bucket := range buckets.Buckets {
+		// Buckets are listed from every region, so cache their location
+		cacheKey := "s3-bucket-location-" + *bucket.Name
+		var location string
+		if cachedData, ok := d.ConnectionManager.Cache.Get(cacheKey); ok {
+			location = cachedData.(string)
+		} else {
+			op, err := getBucketLocation(ctx, d, &plugin.HydrateData{Item: bucket})
+			if err != nil {
+				// One unreadable bucket should not hide the policies of the others
+				plugin.Logger(ctx).Warn("listS3BucketPolicySources", "bucket", *bucket.Name, "location error", err)
+				continue
+			}
+			location = *op.(*s3.GetBucketLocationOutput).LocationConstraint
+			d.ConnectionManager.Cache.Set(cacheKey, location)
+		}
+		if location != region {
+			continue
+		}
+
+		regionSvc, err := S3Service(ctx, d, region)
+		if err != nil {
+			return err
+		}
+		policy, err := regionSvc.GetBucketPolicy(&s3.GetBucketPolicyInput{Bucket: bucket.Name})
+		if err != nil {
+			if a, ok := err.(awserr.Error); !ok || a.Code() != "NoSuchBucketPolicy" {
+				plugin.Logger(ctx).Warn("listS3BucketPolicySources", "bucket", *bucket.Name, "policy error", err)
+			}
+			continue
+		}
+
+		fn(&policySourceInfo{
+			SourceType:   policySourceS3BucketPolicy,
+			SourceArn:    "arn:" + commonColumnData.Partition + ":s3:::" + *bucket.Name,
+			SourceName:   *bucket.Name,
+			ResourceType: "AWS::S3::Bucket",
+			Region:       region,
+			Policy:       types.SafeString(policy.Policy),
+		})
+	}
+
+	return nil
+}
+
+func listKmsKeyPolicySources(ctx context.Context, d *plugin.QueryData, region string, fn func(*policySourceInfo)) error {
+	// Create Session
+	svc, err := KMSService(ctx, d, region)
+	if err != nil {
+		return err
+	}
+
+	var keys []*kms.KeyListEntry
+	err = svc.ListKeysPages(
+		&kms.ListKeysInput{},
+		func(page *kms.ListKeysOutput, isLast bool) bool {
+			keys = append(keys, page.Keys...)
+			return !isLast
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, key := range keys {
+		policy, err := svc.GetKeyPolicy(&kms.GetKeyPolicyInput{
+			KeyId:      key.KeyId,
+			PolicyName: aws.String("default"),
+		})
+		if err != nil {
+			// Key policies can deny the caller access to the key
+			if a, ok := err.(awserr.Error); ok && (a.Code() == "NotFoundException" || a.Code() == "AccessDeniedException") {
+				continue
+			}
+			return err
+		}
+
+		fn(&policySourceInfo{
+			SourceType:   policySourceKmsKeyPolicy,
+			SourceArn:    types.SafeString(key.KeyArn),
+			SourceName:   types.SafeString(key.KeyId),
+			ResourceType: "AWS::KMS::Key",
+			Region:       region,
+			Policy:       types.SafeString(policy.Policy),
+		})
+	}
+
+	return nil
+}
+
+func listLambdaFunctionPolicySources(ctx context.Context, d *plugin.QueryData, region string, fn func(*policySourceInfo)) error {
+	// Create Session
+	svc, err := LambdaService(ctx, d, region)
+	if err != nil {
+		return err
+	}
+
+	var functions []*lambda.FunctionConfiguration
+	err = svc.ListFunctionsPages(
+		&lambda.ListFunctionsInput{},
+		func(page *lambda.ListFunctionsOutput, isLast bool) bool {
+			functions = append(functions, page.Functions...)
+			return !isLast
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, function := range functions {
+		policy, err := svc.GetPolicy(&lambda.GetPolicyInput{FunctionName: function.FunctionName})
+		if err != nil {
+			// Functions without a resource-based policy
+			if a, ok := err.(awserr.Error); ok && a.Code() == "ResourceNotFoundException" {
+				continue
+			}
+			return err
+		}
+
+		fn(&policySourceInfo{
+			SourceType:   policySourceLambdaFunctionPolicy,
+			SourceArn:    types.SafeString(function.FunctionArn),
+			SourceName:   types.SafeString(function.FunctionName),
+			ResourceType: "AWS::Lambda::Function",
+			Region:       region,
+			Policy:       types.SafeString(policy.Policy),
+		})
+	}
+
+	return nil
+}
+
+func listSnsTopicPolicySources(ctx context.Context, d *plugin.QueryData, region string, fn func(*policySourceInfo)) error {
+	// Create Session
+	svc, err := SNSService(ctx, d, region)
+	if err != nil {
+		return err
+	}
+
+	var topics []*sns.Topic
+	err = svc.ListTopicsPages(
+		&sns.ListTopicsInput{},
+		func(page *sns.ListTopicsOutput, isLast bool) bool {
+			topics = append(topics, page.Topics...)
+			return !isLast
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, topic := range topics {
+		op, err := svc.GetTopicAttributes(&sns.GetTopicAttributesInput{TopicArn: topic.TopicArn})
+		if err != nil {
+			return err
+		}
+		policy := types.SafeString(op.Attributes["Policy"])
+		if policy == "" {
+			continue
+		}
+
+		arn := types.SafeString(topic.TopicArn)
+		fn(&policySourceInfo{
+			SourceType:   policySourceSnsTopicPolicy,
+			SourceArn:    arn,
+			SourceName:   arn[strings.LastIndex(arn, ":")+1:],
+			ResourceType: "AWS::SNS::Topic",
+			Region:       region,
+			Policy:       policy,
+		})
+	}
+
+	return nil
+}
+
+func listSqsQueuePolicySources(ctx context.Context, d *plugin.QueryData, region string, fn func(*policySourceInfo)) error {
+	// Create Session
+	svc, err := SQSService(ctx, d, region)
+	if err != nil {
+		return err
+	}
+
+	var queueURLs []*string
+	err = svc.ListQueuesPages(
+		&sqs.ListQueuesInput{},
+		func(page *sqs.ListQueuesOutput, isLast bool) bool {
+			queueURLs = append(queueURLs, page.QueueUrls...)
+			return !isLast
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, queueURL := range queueURLs {
+		op, err := svc.GetQueueAttributes(&sqs.GetQueueAttributesInput{
+			QueueUrl:       queueURL,
+			AttributeNames: aws.StringSlice([]string{"Policy", "QueueArn"}),
+		})
+		if err != nil {
+			// The queue may have been deleted since it was listed
+			if a, ok := err.(awserr.Error); ok && a.Code() == sqs.ErrCodeQueueDoesNotExist {
+				continue
+			}
+			return err
+		}
+		policy := types.SafeString(op.Attributes["Policy"])
+		if policy == "" {
+			continue
+		}
+
+		fn(&policySourceInfo{
+			SourceType:   policySourceSqsQueuePolicy,
+			SourceArn:    types.SafeString(op.Attributes["QueueArn"]),
+			SourceName:   getLastPathElement(types.SafeString(queueURL)),
+			ResourceType: "AWS::SQS::Queue",
+			Region:       region,
+			Policy:       policy,
+		})
+	}
+
+	return nil
+}
+
+func listVpcEndpointPolicySources(ctx context.Context, d *plugin.QueryData, region string, fn func(*policySourceInfo)) error {
+	// Create Session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return err
+	}
+
+	commonData, err := getCommonColumns(ctx, d, nil)
+	if err != nil {
+		return err
+	}
+	commonColumnData := commonData.(*awsCommonColumnData)
+
+	return svc.DescribeVpcEndpointsPages(
+		&ec2.DescribeVpcEndpointsInput{},
+		func(page *ec2.DescribeVpcEndpointsOutput, isLast bool) bool {
+			for _, endpoint := range page.VpcEndpoints {
+				// Gateway load balancer endpoints do not support policies
+				if endpoint.PolicyDocument == nil {
+					continue
+				}
+				fn(&policySourceInfo{
+					SourceType:   policySourceVpcEndpointPolicy,
+					SourceArn:    "arn:" + commonColumnData.Partition + ":ec2:" + region + ":" + types.SafeString(endpoint.OwnerId) + ":vpc-endpoint/" + types.SafeString(endpoint.VpcEndpointId),
+					SourceName:   types.SafeString(endpoint.VpcEndpointId),
+					ResourceType: "AWS::EC2::VPCEndpoint",
+					Region:       region,
+					Policy:       types.SafeString(endpoint.PolicyDocument),
+				})
+			}
+			return !isLast
+		},
+	)
+}
+
+//// PRINCIPAL POLICIES
+
+// Where a policy which applies to a user or role comes from
+const (
+	principalPolicyInline              = "inline_policy"
+	principalPolicyManaged             = "managed_policy"
+	principalPolicyGroupInline         = "group_inline_policy"
+	principalPolicyGroupManaged        = "group_managed_policy"
+	principalPolicyPermissionsBoundary = "permissions_boundary"
+	principalPolicyServiceControl      = "service_control_policy"
+)
+
+// iamPrincipalPolicy is a parsed policy which applies to a user or role
+type iamPrincipalPolicy struct {
+	SourceType string
+	// The ARN of the managed policy or SCP, or of the user, group or role an
+	// inline policy is embedded in
+	SourceArn  string
+	SourceName string
+	// The group the policy is inherited from, for group policies
+	GroupName string
+	// The root, organizational unit or account an SCP is attached to
+	TargetId string
+	Policy   Policy
+}
+
+// iamPrincipalPolicies are the identity-based policies of a user or role,
+// including those inherited from groups, and its permissions boundary
+type iamPrincipalPolicies struct {
+	PrincipalArn        string
+	PrincipalName       string
+	PrincipalType       string
+	Path                string
+	Policies            []*iamPrincipalPolicy
+	PermissionsBoundary *iamPrincipalPolicy
+	// The trust policy, for roles
+	TrustPolicy *Policy
+}
+
+// listIamPrincipalPolicies returns the policies of every user and role in the
+// account. It is read for each query rather than cached for the connection,
+// so that policy changes are reported straight away.
+func listIamPrincipalPolicies(ctx context.Context, d *plugin.QueryData) ([]*iamPrincipalPolicies, error) {
+	// Create Session
+	svc, err := IAMService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	var users []*iam.UserDetail
+	var roles []*iam.RoleDetail
+	groups := map[string]*iam.GroupDetail{}
+	documents := map[string]*string{}
+	err = svc.GetAccountAuthorizationDetailsPages(
+		&iam.GetAccountAuthorizationDetailsInput{},
+		func(page *iam.GetAccountAuthorizationDetailsOutput, isLast bool) bool {
+			users = append(users, page.UserDetailList...)
+			roles = append(roles, page.RoleDetailList...)
+			for _, group := range page.GroupDetailList {
+				groups[types.SafeString(group.GroupName)] = group
+			}
+			for _, policy := range page.Policies {
+				for _, version := range policy.PolicyVersionList {
+					if types.BoolValue(version.IsDefaultVersion) {
+						documents[types.SafeString(policy.Arn)] = version.Document
+					}
+				}
+			}
+			return !isLast
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// managedPolicy returns an attached managed policy. AWS managed policies
+	// which are not in the authorization details are fetched on demand.
+	managedPolicy := func(sourceType string, arn *string, name *string, groupName string) (*iamPrincipalPolicy, error) {
+		document, ok := documents[types.SafeString(arn)]
+		if !ok {
+			policy, err := svc.GetPolicy(&iam.GetPolicyInput{PolicyArn: arn})
+			if err != nil {
+				return nil, err
+			}
+			version, err := svc.GetPolicyVersion(&iam.GetPolicyVersionInput{
+				PolicyArn: arn,
+				VersionId: policy.Policy.DefaultVersionId,
+			})
+			if err != nil {
+				return nil, err
+			}
+			document = version.PolicyVersion.Document
+			documents[types.SafeString(arn)] = document
+		}
+		return newIamPrincipalPolicy(sourceType, types.SafeString(arn), types.SafeString(name), groupName, document)
+	}
+
+	// attachedPolicies returns the inline and managed policies of a user, group or role
+	attachedPolicies := func(arn *string, inline []*iam.PolicyDetail, managed []*iam.AttachedPolicy, groupName string) ([]*iamPrincipalPolicy, error) {
+		inlineType, managedType := principalPolicyInline, principalPolicyManaged
+		if groupName != "" {
+			inlineType, managedType = principalPolicyGroupInline, principalPolicyGroupManaged
+		}
+		var policies []*iamPrincipalPolicy
+		for _, detail := range inline {
+			policy, err := newIamPrincipalPolicy(inlineType, types.SafeString(arn), types.SafeString(detail.PolicyName), groupName, detail.PolicyDocument)
+			if err != nil {
+				return nil, err
+			}
+			policies = append(policies, policy)
+		}
+		for _, attached := range managed {
+			policy, err := managedPolicy(managedType, attached.PolicyArn, attached.PolicyName, groupName)
+			if err != nil {
+				return nil, err
+			}
+			policies = append(policies, policy)
+		}
+		return policies, nil
+	}
+
+	permissionsBoundary := func(boundary *iam.AttachedPermissionsBoundary) (*iamPrincipalPolicy, error) {
+		if boundary == nil || boundary.PermissionsBoundaryArn == nil {
+			return nil, nil
+		}
+		arn := types.SafeString(boundary.PermissionsBoundaryArn)
+		return managedPolicy(principalPolicyPermissionsBoundary, boundary.PermissionsBoundaryArn, aws.String(arn[strings.LastIndex(arn, "/")+1:]), "")
+	}
+
+	var principals []*iamPrincipalPolicies
+	for _, user := range users {
+		principal := &iamPrincipalPolicies{
+			PrincipalArn:  types.SafeString(user.Arn),
+			PrincipalName: types.SafeString(user.UserName),
+			PrincipalType: "user",
+			Path:          types.SafeString(user.Path),
+		}
+		if principal.Policies, err = attachedPolicies(user.Arn, user.UserPolicyList, user.AttachedManagedPolicies, ""); err != nil {
+			return nil, err
+		}
+		for _, groupName := range user.GroupList {
+			group, ok := groups[types.SafeString(groupName)]
+			if !ok {
+				continue
+			}
+			policies, err := attachedPolicies(group.Arn, group.GroupPolicyList, group.AttachedManagedPolicies, types.SafeString(groupName))
+			if err != nil {
+				return nil, err
+			}
+			principal.Policies = append(principal.Policies, policies...)
+		}
+		if principal.PermissionsBoundary, err = permissionsBoundary(user.PermissionsBoundary); err != nil {
+			return nil, err
+		}
+		principals = append(principals, principal)
+	}
+
+	for _, role := range roles {
+		principal := &iamPrincipalPolicies{
+			PrincipalArn:  types.SafeString(role.Arn),
+			PrincipalName: types.SafeString(role.RoleName),
+			PrincipalType: "role",
+			Path:          types.SafeString(role.Path),
+		}
+		if principal.Policies, err = attachedPolicies(role.Arn, role.RolePolicyList, role.AttachedManagedPolicies, ""); err != nil {
+			return nil, err
+		}
+		if principal.PermissionsBoundary, err = permissionsBoundary(role.PermissionsBoundary); err != nil {
+			return nil, err
+		}
+		if role.AssumeRolePolicyDocument != nil {
+			trust, err := newIamPrincipalPolicy(policySourceRoleTrustPolicy, principal.PrincipalArn, principal.PrincipalName, "", role.AssumeRolePolicyDocument)
+			if err != nil {
+				return nil, err
+			}
+			principal.TrustPolicy = &trust.Policy
+		}
+		principals = append(principals, principal)
+	}
+
+	return principals, nil
+}
+
+// newIamPrincipalPolicy parses a URL encoded policy document
+func newIamPrincipalPolicy(sourceType string, arn string, name string, groupName string, document *string) (*iamPrincipalPolicy, error) {
+	decoded, err := url.QueryUnescape(types.SafeString(document))
+	if err != nil {
+		return nil, err
+	}
+	policy := &iamPrincipalPolicy{
+		SourceType: sourceType,
+		SourceArn:  arn,
+		SourceName: name,
+		GroupName:  groupName,
+	}
+	if err := json.Unmarshal([]byte(decoded), &policy.Policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// getServiceControlPolicies returns the SCPs which apply to the account, by the
+// root, organizational units and account they are attached to, starting from
+// the root. ok is false if SCPs do not apply to the account, e.g. it is the
+// management account, or the caller cannot read them.
+func getServiceControlPolicies(ctx context.Context, d *plugin.QueryData) (levels [][]*iamPrincipalPolicy, ok bool, err error) {
+	// Create Session
+	svc, err := OrganizationService(ctx, d)
+	if err != nil {
+		return nil, false, err
+	}
+
+	commonData, err := getCommonColumns(ctx, d, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	accountID := commonData.(*awsCommonColumnData).AccountId
+
+	// Member accounts cannot list parents or policies
+	unavailable := func(err error) bool {
+		if a, ok := err.(awserr.Error); ok {
+			switch a.Code() {
+			case "AWSOrganizationsNotInUseException", "AccessDeniedException", "PolicyTypeNotEnabledException":
+				return true
+			}
+		}
+		return false
+	}
+
+	organization, err := svc.DescribeOrganization(&organizations.DescribeOrganizationInput{})
+	if err != nil {
+		if unavailable(err) {
+			return nil, false, nil
+		}
+		return nil, false, err
+	}
+	// SCPs do not affect the management account
+	if types.SafeString(organization.Organization.MasterAccountId) == accountID {
+		return nil, false, nil
+	}
+
+	targets := []string{accountID}
+	for childID := accountID; ; {
+		op, err := svc.ListParents(&organizations.ListParentsInput{ChildId: aws.String(childID)})
+		if err != nil {
+			if unavailable(err) {
+				return nil, false, nil
+			}
+			return nil, false, err
+		}
+		if len(op.Parents) == 0 {
+			break
+		}
+		parent := op.Parents[0]
+		targets = append([]string{types.SafeString(parent.Id)}, targets...)
+		if types.SafeString(parent.Type) == organizations.ParentTypeRoot {
+			break
+		}
+		childID = types.SafeString(parent.Id)
+	}
+
+	for _, target := range targets {
+		var summaries []*organizations.PolicySummary
+		err := svc.ListPoliciesForTargetPages(
+			&organizations.ListPoliciesForTargetInput{
+				Filter:   aws.String(organizations.PolicyTypeServiceControlPolicy),
+				TargetId: aws.String(target),
+			},
+			func(page *organizations.ListPoliciesForTargetOutput, isLast bool) bool {
+				summaries = append(summaries, page.Policies...)
+				return !isLast
+			},
+		)
+		if err != nil {
+			if unavailable(err) {
+				return nil, false, nil
+			}
+			return nil, false, err
+		}
+
+		var level []*iamPrincipalPolicy
+		for _, summary := range summaries {
+			policy, err := describeOrganizationsPolicy(ctx, d, types.SafeString(summary.Id))
+			if err != nil {
+				if unavailable(err) {
+					return nil, false, nil
+				}
+				return nil, false, err
+			}
+			if policy == nil {
+				return nil, false, nil
+			}
+			scp := &iamPrincipalPolicy{
+				SourceType: principalPolicyServiceControl,
+				SourceArn:  types.SafeString(summary.Arn),
+				SourceName: types.SafeString(summary.Name),
+				TargetId:   target,
+			}
+			if err := json.Unmarshal([]byte(types.SafeString(policy.Content)), &scp.Policy); err != nil {
+				return nil, false, err
+			}
+			level = append(level, scp)
+		}
+		levels = append(levels, level)
+	}
+
+	return levels, true, nil
+}
@@ -27,8 +27,14 @@ func tableAwsAcmCertificate(_ context.Context) *plugin.Table {
 		List: &plugin.ListConfig{
 			Hydrate: listAwsAcmCertificates,
 		},
+		HydrateDependencies: []plugin.HydrateDependencies{
+			{
+				Func:    getAwsAcmCertificateX509,
+				Depends: []plugin.HydrateFunc{getAwsAcmCertificateProperties},
+			},
+		},
 		GetMatrixItem: BuildRegionList,
-		Columns: awsRegionalColumns([]*plugin.Column{
+		Columns: awsRegionalColumns(append([]*plugin.Column{
 			{
 				Name:        "certificate_arn",
 				Description: "Amazon Resource Name (ARN) of the certificate. This is of the form: arn:aws:acm:region:123456789012:certificate/12345678-1234-1234-1234-123456789012",
@@ -192,7 +198,7 @@ func tableAwsAcmCertificate(_ context.Context) *plugin.Table {
 				Type:        proto.ColumnType_JSON,
 				Transform:   transform.FromField("Certificate.CertificateArn").Transform(arnToAkas),
 			},
-		}),
+		}, x509CertificateColumns(getAwsAcmCertificateX509)...)),
 	}
 }
 
@@ -278,6 +284,16 @@ func getAwsAcmCertificateProperties(ctx context.Context, d *plugin.QueryData, h
 	return detail, nil
 }
 
+func getAwsAcmCertificateX509(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getAwsAcmCertificateX509")
+	detail, ok := h.HydrateResults["getAwsAcmCertificateProperties"].(*acm.GetCertificateOutput)
+	if !ok {
+		return nil, nil
+	}
+
+	return getX509CertificateData(ctx, detail.Certificate, detail.CertificateChain)
+}
+
 func listTagsForAcmCertificate(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
 	region := plugin.GetMatrixItem(ctx)[matrixKeyRegion].(string)
 	plugin.Logger(ctx).Trace("listTagsForAcmCertificate")
@@ -0,0 +1,274 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/cloudtrail"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableAwsCloudtrailEvent(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_cloudtrail_event",
+		Description: "AWS CloudTrail Event",
+		List: &plugin.ListConfig{
+			Hydrate: listCloudtrailEvents,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "event_id",
+				Description: "The CloudTrail ID of the event.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Event.EventId"),
+			},
+			{
+				Name:        "event_name",
+				Description: "The name of the event returned, for example CreateBucket.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Event.EventName"),
+			},
+			{
+				Name:        "event_source",
+				Description: "The AWS service to which the request was made, for example s3.amazonaws.com.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Event.EventSource"),
+			},
+			{
+				Name:        "event_time",
+				Description: "The date and time of the event. Without a lower bound on this column, only the last 24 hours of events are read.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("Event.EventTime"),
+			},
+			{
+				Name:        "event_type",
+				Description: "Identifies the type of event that generated the event record, for example AwsApiCall or AwsConsoleSignIn.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Detail.eventType"),
+			},
+			{
+				Name:        "username",
+				Description: "A user name or role name of the requester that called the API in the event returned.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Event.Username"),
+			},
+			{
+				Name:        "access_key_id",
+				Description: "The AWS access key ID that was used to sign the request.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Event.AccessKeyId"),
+			},
+			{
+				Name:        "read_only",
+				Description: "Whether the event is a read-only event.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("Event.ReadOnly"),
+			},
+			{
+				Name:        "resource_type",
+				Description: "The type of a resource referenced by the event. Use as a qual to look up events for a resource type.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Resource.ResourceType"),
+			},
+			{
+				Name:        "resource_name",
+				Description: "The name of a resource referenced by the event. Use as a qual to look up events for a resource.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Resource.ResourceName"),
+			},
+			{
+				Name:        "resources",
+				Description: "A list of resources referenced by the event returned.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Event.Resources"),
+			},
+			{
+				Name:        "source_ip_address",
+				Description: "The IP address that the request was made from.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Detail.sourceIPAddress"),
+			},
+			{
+				Name:        "user_agent",
+				Description: "The agent through which the request was made.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Detail.userAgent"),
+			},
+			{
+				Name:        "error_code",
+				Description: "The AWS service error if the request returns an error.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Detail.errorCode"),
+			},
+			{
+				Name:        "error_message",
+				Description: "The description of the error if the request returns an error.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Detail.errorMessage"),
+			},
+			{
+				Name:        "recipient_account_id",
+				Description: "The account ID that received this event.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Detail.recipientAccountId"),
+			},
+			{
+				Name:        "user_identity",
+				Description: "Information about the user that made the request.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Detail.userIdentity"),
+			},
+			{
+				Name:        "request_parameters",
+				Description: "The parameters, if any, that were sent with the request.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Detail.requestParameters"),
+			},
+			{
+				Name:        "response_elements",
+				Description: "The response element for actions that make changes (create, update, or delete actions).",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Detail.responseElements"),
+			},
+			{
+				Name:        "cloud_trail_event",
+				Description: "The full CloudTrail event record.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Detail"),
+			},
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Event.EventId"),
+			},
+		}),
+	}
+}
+
+type cloudtrailEventRow struct {
+	Event *cloudtrail.Event
+	// the resource matching the resource_name and resource_type quals, or the
+	// first resource of the event if there are none
+	Resource *cloudtrail.Resource
+	// the parsed CloudTrailEvent JSON
+	Detail map[string]interface{}
+}
+
+// LookupEvents accepts a single lookup attribute. If more than one of these
+// columns has a qual, the first in this list is passed to AWS and postgres
+// filters the results on the others.
+var cloudtrailEventLookupColumns = []struct {
+	Column       string
+	AttributeKey string
+}{
+	{"event_id", cloudtrail.LookupAttributeKeyEventId},
+	{"access_key_id", cloudtrail.LookupAttributeKeyAccessKeyId},
+	{"resource_name", cloudtrail.LookupAttributeKeyResourceName},
+	{"event_name", cloudtrail.LookupAttributeKeyEventName},
+	{"username", cloudtrail.LookupAttributeKeyUsername},
+	{"resource_type", cloudtrail.LookupAttributeKeyResourceType},
+	{"event_source", cloudtrail.LookupAttributeKeyEventSource},
+}
+
+// LookupEvents returns about 50 events a page and is throttled to 2 requests a
+// second, so without a lower bound on event_time only this much is read
+const cloudtrailEventDefaultWindow = 24 * time.Hour
+
+//// LIST FUNCTION
+
+func listCloudtrailEvents(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// TODO put me in helper function
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	plugin.Logger(ctx).Trace("listCloudtrailEvents", "AWS_REGION", region)
+
+	// Create session
+	svc, err := CloudTrailService(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &cloudtrail.LookupEventsInput{}
+
+	for _, lookup := range cloudtrailEventLookupColumns {
+		if value := getOptionalStringQual(d, lookup.Column); value != "" {
+			input.LookupAttributes = []*cloudtrail.LookupAttribute{
+				{
+					AttributeKey:   aws.String(lookup.AttributeKey),
+					AttributeValue: aws.String(value),
+				},
+			}
+			break
+		}
+	}
+
+	resourceName := getOptionalStringQual(d, "resource_name")
+	resourceType := getOptionalStringQual(d, "resource_type")
+
+	start, end := getTimestampQualRange(d, "event_time")
+	if start == nil {
+		from := time.Now()
+		if end != nil {
+			from = *end
+		}
+		from = from.Add(-cloudtrailEventDefaultWindow)
+		start = &from
+	}
+	input.StartTime = start
+	if end != nil {
+		input.EndTime = end
+	}
+
+	err = svc.LookupEventsPages(
+		input,
+		func(page *cloudtrail.LookupEventsOutput, isLast bool) bool {
+			for _, event := range page.Events {
+				row := &cloudtrailEventRow{
+					Event:    event,
+					Resource: cloudtrailEventResource(event, resourceName, resourceType),
+				}
+				if detail := types.SafeString(event.CloudTrailEvent); detail != "" {
+					if err := json.Unmarshal([]byte(detail), &row.Detail); err != nil {
+						plugin.Logger(ctx).Error("listCloudtrailEvents", "unmarshal_error", err)
+					}
+				}
+				d.StreamListItem(ctx, row)
+			}
+			return !isLast
+		},
+	)
+
+	return nil, err
+}
+
+//// UTILITY FUNCTIONS
+
+func cloudtrailEventResource(event *cloudtrail.Event, resourceName string, resourceType string) *cloudtrail.Resource {
+	if len(event.Resources) == 0 {
+		return nil
+	}
+
+	for _, resource := range event.Resources {
+		if resourceName != "" && types.SafeString(resource.ResourceName) != resourceName {
+			continue
+		}
+		if resourceType != "" && types.SafeString(resource.ResourceType) != resourceType {
+			continue
+		}
+		return resource
+	}
+
+	return event.Resources[0]
+}
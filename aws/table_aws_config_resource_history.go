@@ -0,0 +1,210 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/configservice"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableAwsConfigResourceHistory(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_config_resource_history",
+		Description: "AWS Config Resource History",
+		List: &plugin.ListConfig{
+			KeyColumns: plugin.AllColumns([]string{"resource_type", "resource_id"}),
+			Hydrate:    listConfigResourceHistory,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "resource_type",
+				Description: "The type of AWS resource, e.g. AWS::EC2::SecurityGroup.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "resource_id",
+				Description: "The ID of the resource, e.g. sg-xxxxxx.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "resource_name",
+				Description: "The custom name of the resource, if available.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "arn",
+				Description: "The Amazon Resource Name (ARN) of the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "configuration_item_capture_time",
+				Description: "The time when the configuration recording was initiated. Quals on this column limit the time range that is read.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "configuration_item_status",
+				Description: "The configuration item status (OK | ResourceDiscovered | ResourceNotRecorded | ResourceDeleted | ResourceDeletedNotRecorded).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "configuration_state_id",
+				Description: "An identifier that indicates the ordering of the configuration items of a resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "configuration_item_md5_hash",
+				Description: "Unique MD5 hash that represents the configuration item's state.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("ConfigurationItemMD5Hash"),
+			},
+			{
+				Name:        "resource_creation_time",
+				Description: "The time stamp when the resource was created.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "availability_zone",
+				Description: "The Availability Zone associated with the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "version",
+				Description: "The version number of the resource configuration.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "configuration",
+				Description: "The description of the resource configuration.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Configuration").Transform(parseJSONString),
+			},
+			{
+				Name:        "supplementary_configuration",
+				Description: "Configuration attributes that AWS Config returns for certain resource types to supplement the information returned for the configuration parameter.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("SupplementaryConfiguration").Transform(supplementaryConfigurationToObject),
+			},
+			{
+				Name:        "relationships",
+				Description: "A list of related AWS resources.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "related_events",
+				Description: "A list of CloudTrail event IDs.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "tags_src",
+				Description: "A mapping of key value tags associated with the resource.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Tags"),
+			},
+
+			// Standard columns
+			{
+				Name:        "tags",
+				Description: resourceInterfaceDescription("tags"),
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Tags"),
+			},
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("ResourceId"),
+			},
+		}),
+	}
+}
+
+//// LIST FUNCTION
+
+func listConfigResourceHistory(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	plugin.Logger(ctx).Trace("listConfigResourceHistory", "AWS_REGION", region)
+
+	resourceType := d.KeyColumnQuals["resource_type"].GetStringValue()
+	resourceID := d.KeyColumnQuals["resource_id"].GetStringValue()
+	if resourceType == "" || resourceID == "" {
+		return nil, nil
+	}
+
+	// Create session
+	svc, err := ConfigService(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &configservice.GetResourceConfigHistoryInput{
+		ResourceType: aws.String(resourceType),
+		ResourceId:   aws.String(resourceID),
+	}
+
+	// LaterTime and EarlierTime are both inclusive
+	start, end := getTimestampQualRange(d, "configuration_item_capture_time")
+	if start != nil {
+		input.EarlierTime = start
+	}
+	if end != nil {
+		input.LaterTime = end
+	}
+
+	err = svc.GetResourceConfigHistoryPages(
+		input,
+		func(page *configservice.GetResourceConfigHistoryOutput, isLast bool) bool {
+			for _, item := range page.ConfigurationItems {
+				d.StreamListItem(ctx, item)
+			}
+			return !isLast
+		},
+	)
+	if err != nil {
+		// The resource only exists in one of the regions being queried
+		if a, ok := err.(awserr.Error); ok {
+			if a.Code() == "ResourceNotDiscoveredException" {
+				return nil, nil
+			}
+		}
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+// supplementaryConfigurationToObject parses each supplementary configuration
+// value, which AWS Config returns as a JSON string
+func supplementaryConfigurationToObject(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	supplementaryConfiguration, ok := d.Value.(map[string]*string)
+	if !ok || supplementaryConfiguration == nil {
+		return nil, nil
+	}
+
+	result := map[string]interface{}{}
+	for key, value := range supplementaryConfiguration {
+		var parsed interface{}
+		if err := json.Unmarshal([]byte(types.SafeString(value)), &parsed); err != nil {
+			// not all values are JSON documents
+			result[key] = types.SafeString(value)
+			continue
+		}
+		result[key] = parsed
+	}
+
+	return result, nil
+}
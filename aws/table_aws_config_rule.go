@@ -0,0 +1,299 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/configservice"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableAwsConfigRule(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_config_rule",
+		Description: "AWS Config Rule",
+		Get: &plugin.GetConfig{
+			KeyColumns:        plugin.SingleColumn("name"),
+			ShouldIgnoreError: isNotFoundError([]string{"NoSuchConfigRuleException"}),
+			Hydrate:           getConfigRule,
+		},
+		List: &plugin.ListConfig{
+			Hydrate: listConfigRules,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "name",
+				Description: "The name that you assign to the AWS Config rule.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("ConfigRuleName"),
+			},
+			{
+				Name:        "rule_id",
+				Description: "The ID of the AWS Config rule.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("ConfigRuleId"),
+			},
+			{
+				Name:        "arn",
+				Description: "The Amazon Resource Name (ARN) of the AWS Config rule.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("ConfigRuleArn"),
+			},
+			{
+				Name:        "rule_state",
+				Description: "Indicates whether the AWS Config rule is active or is currently being deleted by AWS Config (ACTIVE | DELETING | DELETING_RESULTS | EVALUATING).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("ConfigRuleState"),
+			},
+			{
+				Name:        "description",
+				Description: "The description that you provide for the AWS Config rule.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "created_by",
+				Description: "Service principal name of the service that created the rule, if the rule was created by a service.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "owner",
+				Description: "Indicates whether AWS or the customer owns and manages the AWS Config rule (AWS | CUSTOM_LAMBDA).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Source.Owner"),
+			},
+			{
+				Name:        "source_identifier",
+				Description: "For AWS Config managed rules, the identifier of the rule, e.g. S3_BUCKET_VERSIONING_ENABLED. For custom rules, the ARN of the Lambda function.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Source.SourceIdentifier"),
+			},
+			{
+				Name:        "compliance_type",
+				Description: "Indicates whether the AWS Config rule is compliant (COMPLIANT | NON_COMPLIANT | NOT_APPLICABLE | INSUFFICIENT_DATA).",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     getConfigRuleCompliance,
+				Transform:   transform.FromField("ComplianceType"),
+			},
+			{
+				Name:        "non_compliant_resource_count",
+				Description: "The number of resources that are noncompliant with the rule, capped at 100.",
+				Type:        proto.ColumnType_INT,
+				Hydrate:     getConfigRuleCompliance,
+				Transform:   transform.FromField("ComplianceContributorCount.CappedCount"),
+			},
+			{
+				Name:        "non_compliant_resource_count_cap_exceeded",
+				Description: "Indicates whether the number of noncompliant resources exceeds the cap of 100.",
+				Type:        proto.ColumnType_BOOL,
+				Hydrate:     getConfigRuleCompliance,
+				Transform:   transform.FromField("ComplianceContributorCount.CapExceeded"),
+			},
+			{
+				Name:        "maximum_execution_frequency",
+				Description: "The maximum frequency with which AWS Config runs evaluations for a rule.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "input_parameters",
+				Description: "A string, in JSON format, that is passed to the AWS Config rule Lambda function.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("InputParameters").Transform(parseJSONString),
+			},
+			{
+				Name:        "scope",
+				Description: "Defines which resources can trigger an evaluation for the rule.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "source",
+				Description: "Provides the rule owner (AWS or customer), the rule identifier, and the notifications that cause the function to evaluate your AWS resources.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "tags_src",
+				Description: "A list of tags assigned to the rule.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getConfigRuleTags,
+				Transform:   transform.FromValue(),
+			},
+
+			// Standard columns
+			{
+				Name:        "tags",
+				Description: resourceInterfaceDescription("tags"),
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getConfigRuleTags,
+				Transform:   transform.FromValue().Transform(configTurbotTags),
+			},
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("ConfigRuleName"),
+			},
+			{
+				Name:        "akas",
+				Description: resourceInterfaceDescription("akas"),
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("ConfigRuleArn").Transform(arnToAkas),
+			},
+		}),
+	}
+}
+
+//// LIST FUNCTION
+
+func listConfigRules(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	plugin.Logger(ctx).Trace("listConfigRules", "AWS_REGION", region)
+
+	// Create session
+	svc, err := ConfigService(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &configservice.DescribeConfigRulesInput{}
+	for {
+		op, err := svc.DescribeConfigRules(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range op.ConfigRules {
+			d.StreamListItem(ctx, rule)
+		}
+		if op.NextToken == nil {
+			break
+		}
+		input.NextToken = op.NextToken
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getConfigRule(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getConfigRule")
+	name := d.KeyColumnQuals["name"].GetStringValue()
+
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+
+	// Create Session
+	svc, err := ConfigService(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &configservice.DescribeConfigRulesInput{
+		ConfigRuleNames: []*string{aws.String(name)},
+	}
+
+	op, err := svc.DescribeConfigRules(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(op.ConfigRules) > 0 {
+		return op.ConfigRules[0], nil
+	}
+
+	return nil, nil
+}
+
+func getConfigRuleCompliance(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getConfigRuleCompliance")
+	rule := h.Item.(*configservice.ConfigRule)
+
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+
+	// Create Session
+	svc, err := ConfigService(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &configservice.DescribeComplianceByConfigRuleInput{
+		ConfigRuleNames: []*string{rule.ConfigRuleName},
+	}
+
+	op, err := svc.DescribeComplianceByConfigRule(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(op.ComplianceByConfigRules) > 0 && op.ComplianceByConfigRules[0].Compliance != nil {
+		return op.ComplianceByConfigRules[0].Compliance, nil
+	}
+
+	return nil, nil
+}
+
+func getConfigRuleTags(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getConfigRuleTags")
+	rule := h.Item.(*configservice.ConfigRule)
+
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+
+	// Create Session
+	svc, err := ConfigService(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []*configservice.Tag
+	input := &configservice.ListTagsForResourceInput{
+		ResourceArn: rule.ConfigRuleArn,
+	}
+	for {
+		op, err := svc.ListTagsForResource(input)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, op.Tags...)
+		if op.NextToken == nil {
+			break
+		}
+		input.NextToken = op.NextToken
+	}
+
+	return tags, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+func configTurbotTags(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	tags, ok := d.Value.([]*configservice.Tag)
+	if !ok || tags == nil {
+		return nil, nil
+	}
+
+	turbotTagsMap := map[string]string{}
+	for _, i := range tags {
+		turbotTagsMap[*i.Key] = types.SafeString(i.Value)
+	}
+
+	return turbotTagsMap, nil
+}
@@ -0,0 +1,125 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/configservice"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableAwsConfigRuleEvaluation(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_config_rule_evaluation",
+		Description: "AWS Config Rule Evaluation",
+		List: &plugin.ListConfig{
+			ParentHydrate: listConfigRules,
+			Hydrate:       listConfigRuleEvaluations,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "config_rule_name",
+				Description: "The name of the AWS Config rule that was used in the evaluation.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("EvaluationResultIdentifier.EvaluationResultQualifier.ConfigRuleName"),
+			},
+			{
+				Name:        "resource_type",
+				Description: "The type of AWS resource that was evaluated.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("EvaluationResultIdentifier.EvaluationResultQualifier.ResourceType"),
+			},
+			{
+				Name:        "resource_id",
+				Description: "The ID of the evaluated AWS resource.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("EvaluationResultIdentifier.EvaluationResultQualifier.ResourceId"),
+			},
+			{
+				Name:        "compliance_type",
+				Description: "Indicates whether the AWS resource complies with the AWS Config rule (COMPLIANT | NON_COMPLIANT | NOT_APPLICABLE).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "annotation",
+				Description: "Supplementary information about how the evaluation determined the compliance.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "config_rule_invoked_time",
+				Description: "The time when the AWS Config rule evaluated the AWS resource.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "result_recorded_time",
+				Description: "The time when AWS Config recorded the evaluation result.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "ordering_timestamp",
+				Description: "The time of the event that triggered the evaluation of the AWS resource.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("EvaluationResultIdentifier.OrderingTimestamp"),
+			},
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("EvaluationResultIdentifier.EvaluationResultQualifier.ResourceId"),
+			},
+		}),
+	}
+}
+
+//// LIST FUNCTION
+
+func listConfigRuleEvaluations(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	plugin.Logger(ctx).Trace("listConfigRuleEvaluations", "AWS_REGION", region)
+
+	rule := h.Item.(*configservice.ConfigRule)
+
+	// Skip rules which are excluded by the where clause
+	if name := getOptionalStringQual(d, "config_rule_name"); name != "" && name != types.SafeString(rule.ConfigRuleName) {
+		return nil, nil
+	}
+
+	// Create session
+	svc, err := ConfigService(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &configservice.GetComplianceDetailsByConfigRuleInput{
+		ConfigRuleName: rule.ConfigRuleName,
+	}
+	if complianceType := getOptionalStringQual(d, "compliance_type"); complianceType != "" {
+		input.ComplianceTypes = []*string{aws.String(complianceType)}
+	}
+
+	for {
+		op, err := svc.GetComplianceDetailsByConfigRule(input)
+		if err != nil {
+			return nil, err
+		}
+		for _, result := range op.EvaluationResults {
+			d.StreamLeafListItem(ctx, result)
+		}
+		if op.NextToken == nil {
+			break
+		}
+		input.NextToken = op.NextToken
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,218 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/directconnect"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+func tableAwsDirectConnectConnection(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_directconnect_connection",
+		Description: "AWS Direct Connect Connection",
+		Get: &plugin.GetConfig{
+			KeyColumns:        plugin.SingleColumn("connection_id"),
+			ShouldIgnoreError: isNotFoundError([]string{"DirectConnectClientException"}),
+			Hydrate:           getDirectConnectConnection,
+		},
+		List: &plugin.ListConfig{
+			Hydrate: listDirectConnectConnections,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "connection_id",
+				Description: "The ID of the connection.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "connection_name",
+				Description: "The name of the connection.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "connection_state",
+				Description: "The state of the connection (ordering | requested | pending | available | down | deleting | deleted | rejected | unknown).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "bandwidth",
+				Description: "The bandwidth of the connection.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "location",
+				Description: "The location of the connection.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "owner_account",
+				Description: "The ID of the AWS account that owns the connection.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "partner_name",
+				Description: "The name of the AWS Direct Connect service provider associated with the connection.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "provider_name",
+				Description: "The name of the service provider associated with the connection.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "vlan",
+				Description: "The ID of the VLAN.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "lag_id",
+				Description: "The ID of the link aggregation group (LAG) the connection is part of.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "aws_device_v2",
+				Description: "The Direct Connect endpoint on which the physical connection terminates.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "has_logical_redundancy",
+				Description: "Indicates whether the connection supports a secondary BGP peer in the same address family (unknown | yes | no).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "jumbo_frame_capable",
+				Description: "Indicates whether jumbo frames (9001 MTU) are supported.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "loa_issue_time",
+				Description: "The time of the most recent call to DescribeLoa for the connection.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "tags_src",
+				Description: "A list of tags that are attached to the connection.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Tags"),
+			},
+			{
+				Name:        "tags",
+				Description: resourceInterfaceDescription("tags"),
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Tags").Transform(directConnectTagsToMap),
+			},
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("ConnectionName"),
+			},
+			{
+				Name:        "akas",
+				Description: resourceInterfaceDescription("akas"),
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getDirectConnectConnectionAkas,
+				Transform:   transform.FromValue(),
+			},
+		}),
+	}
+}
+
+//// LIST FUNCTION
+
+func listDirectConnectConnections(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	plugin.Logger(ctx).Trace("listDirectConnectConnections", "AWS_REGION", region)
+
+	// Create session
+	svc, err := DirectConnectService(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	// List call
+	resp, err := svc.DescribeConnections(&directconnect.DescribeConnectionsInput{})
+	if err != nil {
+		return nil, err
+	}
+	for _, connection := range resp.Connections {
+		d.StreamListItem(ctx, connection)
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getDirectConnectConnection(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getDirectConnectConnection")
+
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	connectionID := d.KeyColumnQuals["connection_id"].GetStringValue()
+
+	// Create session
+	svc, err := DirectConnectService(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the params
+	params := &directconnect.DescribeConnectionsInput{
+		ConnectionId: aws.String(connectionID),
+	}
+
+	// Get call
+	op, err := svc.DescribeConnections(params)
+	if err != nil {
+		plugin.Logger(ctx).Debug("getDirectConnectConnection__", "ERROR", err)
+		return nil, err
+	}
+
+	if op.Connections != nil && len(op.Connections) > 0 {
+		return op.Connections[0], nil
+	}
+	return nil, nil
+}
+
+func getDirectConnectConnectionAkas(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getDirectConnectConnectionAkas")
+	connection := h.Item.(*directconnect.Connection)
+	commonData, err := getCommonColumns(ctx, d, h)
+	if err != nil {
+		return nil, err
+	}
+	commonColumnData := commonData.(*awsCommonColumnData)
+
+	// Get data for turbot defined properties
+	akas := []string{"arn:" + commonColumnData.Partition + ":directconnect:" + commonColumnData.Region + ":" + commonColumnData.AccountId + ":dxcon/" + *connection.ConnectionId}
+
+	return akas, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+func directConnectTagsToMap(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	tags, ok := d.Value.([]*directconnect.Tag)
+	if !ok || tags == nil {
+		return nil, nil
+	}
+
+	turbotTagsMap := map[string]string{}
+	for _, i := range tags {
+		turbotTagsMap[*i.Key] = aws.StringValue(i.Value)
+	}
+	return turbotTagsMap, nil
+}
@@ -0,0 +1,267 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/directconnect"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+func tableAwsDirectConnectVirtualInterface(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_directconnect_virtual_interface",
+		Description: "AWS Direct Connect Virtual Interface",
+		Get: &plugin.GetConfig{
+			KeyColumns:        plugin.SingleColumn("virtual_interface_id"),
+			ShouldIgnoreError: isNotFoundError([]string{"DirectConnectClientException"}),
+			Hydrate:           getDirectConnectVirtualInterface,
+		},
+		List: &plugin.ListConfig{
+			Hydrate: listDirectConnectVirtualInterfaces,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "virtual_interface_id",
+				Description: "The ID of the virtual interface.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "virtual_interface_name",
+				Description: "The name of the virtual interface.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "virtual_interface_type",
+				Description: "The type of virtual interface (private | public | transit).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "virtual_interface_state",
+				Description: "The state of the virtual interface (confirming | verifying | pending | available | down | deleting | deleted | rejected | unknown).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "connection_id",
+				Description: "The ID of the connection the virtual interface runs over.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "direct_connect_gateway_id",
+				Description: "The ID of the Direct Connect gateway the virtual interface is attached to.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "virtual_gateway_id",
+				Description: "The ID of the virtual private gateway the virtual interface is attached to.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "owner_account",
+				Description: "The ID of the AWS account that owns the virtual interface.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "location",
+				Description: "The location of the connection.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "vlan",
+				Description: "The ID of the VLAN.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "asn",
+				Description: "The autonomous system number (ASN) for the BGP configuration on the customer side.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "amazon_side_asn",
+				Description: "The autonomous system number (ASN) for the Amazon side of the connection.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "address_family",
+				Description: "The address family for the BGP peer (ipv4 | ipv6).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "amazon_address",
+				Description: "The IP address assigned to the Amazon interface.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "customer_address",
+				Description: "The IP address assigned to the customer interface.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "mtu",
+				Description: "The maximum transmission unit (MTU), in bytes.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "jumbo_frame_capable",
+				Description: "Indicates whether jumbo frames (9001 MTU) are supported.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "aws_device_v2",
+				Description: "The Direct Connect endpoint on which the virtual interface terminates.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "bgp_peer_up_count",
+				Description: "The number of BGP peers of the virtual interface whose BGP status is up.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.From(directConnectBgpPeerUpCount),
+			},
+			{
+				Name:        "bgp_peers",
+				Description: "The BGP peers configured on the virtual interface, with their BGP status. BGP authentication keys are not included.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.From(directConnectBgpPeers),
+			},
+			{
+				Name:        "route_filter_prefixes",
+				Description: "The routes to be advertised to the AWS network in this region, for public virtual interfaces.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "tags_src",
+				Description: "A list of tags that are attached to the virtual interface.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Tags"),
+			},
+			{
+				Name:        "tags",
+				Description: resourceInterfaceDescription("tags"),
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Tags").Transform(directConnectTagsToMap),
+			},
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("VirtualInterfaceName"),
+			},
+			{
+				Name:        "akas",
+				Description: resourceInterfaceDescription("akas"),
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getDirectConnectVirtualInterfaceAkas,
+				Transform:   transform.FromValue(),
+			},
+		}),
+	}
+}
+
+//// LIST FUNCTION
+
+func listDirectConnectVirtualInterfaces(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	plugin.Logger(ctx).Trace("listDirectConnectVirtualInterfaces", "AWS_REGION", region)
+
+	// Create session
+	svc, err := DirectConnectService(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	// List call
+	resp, err := svc.DescribeVirtualInterfaces(&directconnect.DescribeVirtualInterfacesInput{})
+	if err != nil {
+		return nil, err
+	}
+	for _, virtualInterface := range resp.VirtualInterfaces {
+		d.StreamListItem(ctx, virtualInterface)
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getDirectConnectVirtualInterface(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getDirectConnectVirtualInterface")
+
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	virtualInterfaceID := d.KeyColumnQuals["virtual_interface_id"].GetStringValue()
+
+	// Create session
+	svc, err := DirectConnectService(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the params
+	params := &directconnect.DescribeVirtualInterfacesInput{
+		VirtualInterfaceId: aws.String(virtualInterfaceID),
+	}
+
+	// Get call
+	op, err := svc.DescribeVirtualInterfaces(params)
+	if err != nil {
+		plugin.Logger(ctx).Debug("getDirectConnectVirtualInterface__", "ERROR", err)
+		return nil, err
+	}
+
+	if op.VirtualInterfaces != nil && len(op.VirtualInterfaces) > 0 {
+		return op.VirtualInterfaces[0], nil
+	}
+	return nil, nil
+}
+
+func getDirectConnectVirtualInterfaceAkas(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getDirectConnectVirtualInterfaceAkas")
+	virtualInterface := h.Item.(*directconnect.VirtualInterface)
+	commonData, err := getCommonColumns(ctx, d, h)
+	if err != nil {
+		return nil, err
+	}
+	commonColumnData := commonData.(*awsCommonColumnData)
+
+	// Get data for turbot defined properties
+	akas := []string{"arn:" + commonColumnData.Partition + ":directconnect:" + commonColumnData.Region + ":" + commonColumnData.AccountId + ":dxvif/" + *virtualInterface.VirtualInterfaceId}
+
+	return akas, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+// directConnectBgpPeers returns the BGP peers of a virtual interface without
+// their authentication keys
+func directConnectBgpPeers(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	virtualInterface := d.HydrateItem.(*directconnect.VirtualInterface)
+	peers := []*directconnect.BGPPeer{}
+	for _, peer := range virtualInterface.BgpPeers {
+		sanitized := *peer
+		sanitized.AuthKey = nil
+		peers = append(peers, &sanitized)
+	}
+	return peers, nil
+}
+
+func directConnectBgpPeerUpCount(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	virtualInterface := d.HydrateItem.(*directconnect.VirtualInterface)
+	count := 0
+	for _, peer := range virtualInterface.BgpPeers {
+		if types.SafeString(peer.BgpStatus) == directconnect.BGPStatusUp {
+			count++
+		}
+	}
+	return count, nil
+}
@@ -55,6 +55,27 @@ func tableAwsEc2ApplicationLoadBalancerListener(_ context.Context) *plugin.Table
 				Description: "The security policy that defines which protocols and ciphers are supported.",
 				Type:        proto.ColumnType_STRING,
 			},
+			{
+				Name:        "supports_tls10",
+				Description: "True if the security policy of the listener supports TLS 1.0.",
+				Type:        proto.ColumnType_BOOL,
+				Hydrate:     getEc2LoadBalancerListenerSslPolicy,
+				Transform:   transform.FromField("SupportsTLS10"),
+			},
+			{
+				Name:        "weak_ciphers",
+				Description: "The ciphers of the security policy of the listener which are weak: CBC ciphers with SHA-1 message authentication, RC4, DES and 3DES, NULL, export, anonymous and MD5 ciphers.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getEc2LoadBalancerListenerSslPolicy,
+				Transform:   transform.FromField("WeakCiphers"),
+			},
+			{
+				Name:        "forward_secrecy",
+				Description: "True if every cipher of the security policy of the listener uses an ephemeral key exchange.",
+				Type:        proto.ColumnType_BOOL,
+				Hydrate:     getEc2LoadBalancerListenerSslPolicy,
+				Transform:   transform.FromField("ForwardSecrecy"),
+			},
 			{
 				Name:        "alpn_policy",
 				Description: "The name of the Application-Layer Protocol Negotiation (ALPN) policy.",
@@ -185,6 +206,29 @@ func getEc2LoadBalancerListener(ctx context.Context, d *plugin.QueryData, _ *plu
 	return nil, nil
 }
 
+// getEc2LoadBalancerListenerSslPolicy returns the protocols and ciphers of the
+// security policy of a TLS or HTTPS listener
+func getEc2LoadBalancerListenerSslPolicy(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	listener := h.Item.(*elbv2.Listener)
+	if listener.SslPolicy == nil {
+		return nil, nil
+	}
+
+	policies, err := getElbv2SslPolicies(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+	if policy, ok := policies[*listener.SslPolicy]; ok {
+		return policy, nil
+	}
+	return nil, nil
+}
+
 //// TRANSFORM FUNCTIONS ////
 
 func getEc2ApplicationLoadBalancerListenerTurbotTitle(_ context.Context, d *transform.TransformData) (interface{}, error) {
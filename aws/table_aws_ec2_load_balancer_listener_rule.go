@@ -0,0 +1,498 @@
+package aws
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableAwsEc2LoadBalancerListenerRule(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_ec2_load_balancer_listener_rule",
+		Description: "AWS EC2 Load Balancer Listener Rule",
+		Get: &plugin.GetConfig{
+			KeyColumns:        plugin.SingleColumn("arn"),
+			ShouldIgnoreError: isNotFoundError([]string{"RuleNotFound", "ValidationError"}),
+			Hydrate:           getEc2LoadBalancerListenerRule,
+		},
+		List: &plugin.ListConfig{
+			ParentHydrate: listEc2LoadBalancers,
+			Hydrate:       listEc2LoadBalancerListenerRules,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "arn",
+				Description: "The Amazon Resource Name (ARN) of the rule.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Rule.RuleArn"),
+			},
+			{
+				Name:        "listener_arn",
+				Description: "The Amazon Resource Name (ARN) of the listener.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "load_balancer_arn",
+				Description: "The Amazon Resource Name (ARN) of the load balancer.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "priority",
+				Description: "The priority of the rule, or default for the default rule of the listener.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Rule.Priority"),
+			},
+			{
+				Name:        "is_default",
+				Description: "True if the rule is the default rule of the listener.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("Rule.IsDefault"),
+			},
+			{
+				Name:        "host_headers",
+				Description: "The host names the rule matches.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "path_patterns",
+				Description: "The path patterns the rule matches.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "http_headers",
+				Description: "The HTTP headers the rule matches, with the values of each.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "http_request_methods",
+				Description: "The HTTP request methods the rule matches.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "query_strings",
+				Description: "The query string key/value pairs the rule matches.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "source_ips",
+				Description: "The source IP address ranges the rule matches.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "action_types",
+				Description: "The types of the actions of the rule, in the order they are performed.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "forward_target_groups",
+				Description: "The target groups the rule forwards requests to, with the weight of each.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "forward_stickiness_enabled",
+				Description: "True if requests are sent to the same target group for the duration of the stickiness.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "forward_stickiness_duration_seconds",
+				Description: "The time period during which requests from a client are sent to the same target group.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "redirect_protocol",
+				Description: "The protocol of the redirect (HTTP | HTTPS | #{protocol}).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "redirect_host",
+				Description: "The host name of the redirect.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "redirect_port",
+				Description: "The port of the redirect.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "redirect_path",
+				Description: "The path of the redirect.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "redirect_query",
+				Description: "The query parameters of the redirect.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "redirect_status_code",
+				Description: "The HTTP status code of the redirect (HTTP_301 | HTTP_302).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "fixed_response_status_code",
+				Description: "The HTTP status code of the fixed response.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "fixed_response_content_type",
+				Description: "The content type of the fixed response.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "fixed_response_message_body",
+				Description: "The message of the fixed response.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "authenticate_type",
+				Description: "The type of the authentication action of the rule (authenticate-oidc | authenticate-cognito).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "authenticate_oidc_issuer",
+				Description: "The OIDC issuer identifier of the IdP.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "authenticate_oidc_client_id",
+				Description: "The OAuth 2.0 client identifier.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "authenticate_cognito_user_pool_arn",
+				Description: "The Amazon Resource Name (ARN) of the Amazon Cognito user pool.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "authenticate_cognito_user_pool_domain",
+				Description: "The domain prefix or fully-qualified domain name of the Amazon Cognito user pool.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "authenticate_scope",
+				Description: "The set of user claims requested from the IdP.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "authenticate_on_unauthenticated_request",
+				Description: "The behavior if the user is not authenticated (deny | allow | authenticate).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "conditions",
+				Description: "The conditions of the rule.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Rule.Conditions"),
+			},
+			{
+				Name:        "actions",
+				Description: "The actions of the rule.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Rule.Actions"),
+			},
+
+			// Standard columns
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(getEc2LoadBalancerListenerRuleTitle),
+			},
+			{
+				Name:        "akas",
+				Description: resourceInterfaceDescription("akas"),
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Rule.RuleArn").Transform(arnToAkas),
+			},
+		}),
+		DefaultTransform: transform.FromGo(),
+	}
+}
+
+// listenerRule is a rule with its conditions and actions flattened. Client
+// secrets of OIDC actions are left out.
+type listenerRule struct {
+	Rule            *elbv2.Rule
+	ListenerArn     string
+	LoadBalancerArn string
+
+	HostHeaders        []string
+	PathPatterns       []string
+	HttpHeaders        []*listenerRuleHttpHeader
+	HttpRequestMethods []string
+	QueryStrings       []*elbv2.QueryStringKeyValuePair
+	SourceIps          []string
+
+	ActionTypes                          []string
+	ForwardTargetGroups                  []*elbv2.TargetGroupTuple
+	ForwardStickinessEnabled             *bool
+	ForwardStickinessDurationSeconds     *int64
+	RedirectProtocol                     *string
+	RedirectHost                         *string
+	RedirectPort                         *string
+	RedirectPath                         *string
+	RedirectQuery                        *string
+	RedirectStatusCode                   *string
+	FixedResponseStatusCode              *string
+	FixedResponseContentType             *string
+	FixedResponseMessageBody             *string
+	AuthenticateType                     *string
+	AuthenticateOidcIssuer               *string
+	AuthenticateOidcClientId             *string
+	AuthenticateCognitoUserPoolArn       *string
+	AuthenticateCognitoUserPoolDomain    *string
+	AuthenticateScope                    *string
+	AuthenticateOnUnauthenticatedRequest *string
+}
+
+type listenerRuleHttpHeader struct {
+	Name   *string
+	Values []string
+}
+
+//// LIST FUNCTION
+
+func listEc2LoadBalancerListenerRules(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	loadBalancer := h.Item.(*elbv2.LoadBalancer)
+	plugin.Logger(ctx).Trace("listEc2LoadBalancerListenerRules", "AWS_REGION", region, "loadBalancer", loadBalancer.LoadBalancerArn)
+
+	// Only application load balancers route on rules
+	if types.SafeString(loadBalancer.Type) != elbv2.LoadBalancerTypeEnumApplication {
+		return nil, nil
+	}
+	if loadBalancerArn := getOptionalStringQual(d, "load_balancer_arn"); loadBalancerArn != "" && loadBalancerArn != types.SafeString(loadBalancer.LoadBalancerArn) {
+		return nil, nil
+	}
+	listenerArn := getOptionalStringQual(d, "listener_arn")
+
+	// Create Session
+	svc, err := ELBv2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	var listenerArns []*string
+	err = svc.DescribeListenersPages(
+		&elbv2.DescribeListenersInput{
+			LoadBalancerArn: loadBalancer.LoadBalancerArn,
+		},
+		func(page *elbv2.DescribeListenersOutput, isLast bool) bool {
+			for _, listener := range page.Listeners {
+				if listenerArn == "" || listenerArn == types.SafeString(listener.ListenerArn) {
+					listenerArns = append(listenerArns, listener.ListenerArn)
+				}
+			}
+			return !isLast
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// DescribeRules has no paginator, so follow the markers
+	for _, arn := range listenerArns {
+		params := &elbv2.DescribeRulesInput{
+			ListenerArn: arn,
+		}
+		for {
+			op, err := svc.DescribeRules(params)
+			if err != nil {
+				return nil, err
+			}
+			for _, rule := range op.Rules {
+				d.StreamLeafListItem(ctx, flattenListenerRule(rule))
+			}
+			if op.NextMarker == nil {
+				break
+			}
+			params.Marker = op.NextMarker
+		}
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getEc2LoadBalancerListenerRule(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	ruleArn := d.KeyColumnQuals["arn"].GetStringValue()
+
+	// Create service
+	svc, err := ELBv2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &elbv2.DescribeRulesInput{
+		RuleArns: []*string{aws.String(ruleArn)},
+	}
+
+	op, err := svc.DescribeRules(params)
+	if err != nil {
+		return nil, err
+	}
+
+	if op.Rules != nil && len(op.Rules) > 0 {
+		return flattenListenerRule(op.Rules[0]), nil
+	}
+	return nil, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+func getEc2LoadBalancerListenerRuleTitle(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	rule := d.HydrateItem.(*listenerRule)
+	splitID := strings.Split(types.SafeString(rule.Rule.RuleArn), "/")
+	if len(splitID) < 6 {
+		return rule.Rule.RuleArn, nil
+	}
+	return splitID[2] + "-" + splitID[5], nil
+}
+
+//// UTILITY FUNCTIONS
+
+// flattenListenerRule returns the conditions and actions of a rule as columns.
+// Rules created before condition configs were added only set Field and Values
+// for host-header and path-pattern conditions.
+func flattenListenerRule(rule *elbv2.Rule) *listenerRule {
+	ruleArn := types.SafeString(rule.RuleArn)
+	flattened := &listenerRule{
+		Rule:            rule,
+		ListenerArn:     listenerRuleListenerArn(ruleArn),
+		LoadBalancerArn: listenerRuleLoadBalancerArn(ruleArn),
+		ActionTypes:     []string{},
+	}
+
+	for _, condition := range rule.Conditions {
+		switch types.SafeString(condition.Field) {
+		case "host-header":
+			if condition.HostHeaderConfig != nil {
+				flattened.HostHeaders = append(flattened.HostHeaders, aws.StringValueSlice(condition.HostHeaderConfig.Values)...)
+			} else {
+				flattened.HostHeaders = append(flattened.HostHeaders, aws.StringValueSlice(condition.Values)...)
+			}
+		case "path-pattern":
+			if condition.PathPatternConfig != nil {
+				flattened.PathPatterns = append(flattened.PathPatterns, aws.StringValueSlice(condition.PathPatternConfig.Values)...)
+			} else {
+				flattened.PathPatterns = append(flattened.PathPatterns, aws.StringValueSlice(condition.Values)...)
+			}
+		case "http-header":
+			if condition.HttpHeaderConfig != nil {
+				flattened.HttpHeaders = append(flattened.HttpHeaders, &listenerRuleHttpHeader{
+					Name:   condition.HttpHeaderConfig.HttpHeaderName,
+					Values: aws.StringValueSlice(condition.HttpHeaderConfig.Values),
+				})
+			}
+		case "http-request-method":
+			if condition.HttpRequestMethodConfig != nil {
+				flattened.HttpRequestMethods = append(flattened.HttpRequestMethods, aws.StringValueSlice(condition.HttpRequestMethodConfig.Values)...)
+			}
+		case "query-string":
+			if condition.QueryStringConfig != nil {
+				flattened.QueryStrings = append(flattened.QueryStrings, condition.QueryStringConfig.Values...)
+			}
+		case "source-ip":
+			if condition.SourceIpConfig != nil {
+				flattened.SourceIps = append(flattened.SourceIps, aws.StringValueSlice(condition.SourceIpConfig.Values)...)
+			}
+		}
+	}
+
+	actions := make([]*elbv2.Action, len(rule.Actions))
+	copy(actions, rule.Actions)
+	sort.SliceStable(actions, func(i, j int) bool {
+		return types.Int64Value(actions[i].Order) < types.Int64Value(actions[j].Order)
+	})
+
+	for _, action := range actions {
+		flattened.ActionTypes = append(flattened.ActionTypes, types.SafeString(action.Type))
+		switch types.SafeString(action.Type) {
+		case elbv2.ActionTypeEnumForward:
+			if action.ForwardConfig != nil {
+				flattened.ForwardTargetGroups = action.ForwardConfig.TargetGroups
+				if stickiness := action.ForwardConfig.TargetGroupStickinessConfig; stickiness != nil {
+					flattened.ForwardStickinessEnabled = stickiness.Enabled
+					flattened.ForwardStickinessDurationSeconds = stickiness.DurationSeconds
+				}
+			} else if action.TargetGroupArn != nil {
+				flattened.ForwardTargetGroups = []*elbv2.TargetGroupTuple{{TargetGroupArn: action.TargetGroupArn}}
+			}
+		case elbv2.ActionTypeEnumRedirect:
+			if redirect := action.RedirectConfig; redirect != nil {
+				flattened.RedirectProtocol = redirect.Protocol
+				flattened.RedirectHost = redirect.Host
+				flattened.RedirectPort = redirect.Port
+				flattened.RedirectPath = redirect.Path
+				flattened.RedirectQuery = redirect.Query
+				flattened.RedirectStatusCode = redirect.StatusCode
+			}
+		case elbv2.ActionTypeEnumFixedResponse:
+			if response := action.FixedResponseConfig; response != nil {
+				flattened.FixedResponseStatusCode = response.StatusCode
+				flattened.FixedResponseContentType = response.ContentType
+				flattened.FixedResponseMessageBody = response.MessageBody
+			}
+		case elbv2.ActionTypeEnumAuthenticateOidc:
+			flattened.AuthenticateType = action.Type
+			if oidc := action.AuthenticateOidcConfig; oidc != nil {
+				flattened.AuthenticateOidcIssuer = oidc.Issuer
+				flattened.AuthenticateOidcClientId = oidc.ClientId
+				flattened.AuthenticateScope = oidc.Scope
+				flattened.AuthenticateOnUnauthenticatedRequest = oidc.OnUnauthenticatedRequest
+			}
+		case elbv2.ActionTypeEnumAuthenticateCognito:
+			flattened.AuthenticateType = action.Type
+			if cognito := action.AuthenticateCognitoConfig; cognito != nil {
+				flattened.AuthenticateCognitoUserPoolArn = cognito.UserPoolArn
+				flattened.AuthenticateCognitoUserPoolDomain = cognito.UserPoolDomain
+				flattened.AuthenticateScope = cognito.Scope
+				flattened.AuthenticateOnUnauthenticatedRequest = cognito.OnUnauthenticatedRequest
+			}
+		}
+	}
+
+	return flattened
+}
+
+// listenerRuleListenerArn returns the ARN of the listener of a rule, e.g.
+// arn:aws:elasticloadbalancing:us-east-1:123456789012:listener-rule/app/my-lb/50dc6c495c0c9188/f2f7dc8efc522ab2/9683b2d02a6cabee
+// is a rule of listener
+// arn:aws:elasticloadbalancing:us-east-1:123456789012:listener/app/my-lb/50dc6c495c0c9188/f2f7dc8efc522ab2
+func listenerRuleListenerArn(ruleArn string) string {
+	if !strings.Contains(ruleArn, ":listener-rule/") {
+		return ""
+	}
+	arn := strings.Replace(ruleArn, ":listener-rule/", ":listener/", 1)
+	return arn[:strings.LastIndex(arn, "/")]
+}
+
+// listenerRuleLoadBalancerArn returns the ARN of the load balancer of a rule
+func listenerRuleLoadBalancerArn(ruleArn string) string {
+	listenerArn := listenerRuleListenerArn(ruleArn)
+	if listenerArn == "" {
+		return ""
+	}
+	arn := strings.Replace(listenerArn, ":listener/", ":loadbalancer/", 1)
+	return arn[:strings.LastIndex(arn, "/")]
+}
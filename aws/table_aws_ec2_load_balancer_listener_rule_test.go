@@ -0,0 +1,124 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+func TestFlattenListenerRule(t *testing.T) {
+	rule := &elbv2.Rule{
+		RuleArn:   aws.String("arn:aws:elasticloadbalancing:us-east-1:123456789012:listener-rule/app/my-lb/50dc6c495c0c9188/f2f7dc8efc522ab2/9683b2d02a6cabee"),
+		Priority:  aws.String("10"),
+		IsDefault: aws.Bool(false),
+		Conditions: []*elbv2.RuleCondition{
+			{Field: aws.String("host-header"), Values: aws.StringSlice([]string{"old.example.com"})},
+			{Field: aws.String("path-pattern"), PathPatternConfig: &elbv2.PathPatternConditionConfig{Values: aws.StringSlice([]string{"/api/*"})}},
+			{Field: aws.String("http-header"), HttpHeaderConfig: &elbv2.HttpHeaderConditionConfig{HttpHeaderName: aws.String("X-Env"), Values: aws.StringSlice([]string{"beta"})}},
+			{Field: aws.String("source-ip"), SourceIpConfig: &elbv2.SourceIpConditionConfig{Values: aws.StringSlice([]string{"10.0.0.0/8"})}},
+		},
+		Actions: []*elbv2.Action{
+			{
+				Type:  aws.String("forward"),
+				Order: aws.Int64(2),
+				ForwardConfig: &elbv2.ForwardActionConfig{
+					TargetGroups: []*elbv2.TargetGroupTuple{
+						{TargetGroupArn: aws.String("blue"), Weight: aws.Int64(90)},
+						{TargetGroupArn: aws.String("green"), Weight: aws.Int64(10)},
+					},
+					TargetGroupStickinessConfig: &elbv2.TargetGroupStickinessConfig{Enabled: aws.Bool(true), DurationSeconds: aws.Int64(300)},
+				},
+			},
+			{
+				Type:  aws.String("authenticate-oidc"),
+				Order: aws.Int64(1),
+				AuthenticateOidcConfig: &elbv2.AuthenticateOidcActionConfig{
+					Issuer:                   aws.String("https://idp.example.com"),
+					ClientId:                 aws.String("client"),
+					Scope:                    aws.String("openid"),
+					OnUnauthenticatedRequest: aws.String("authenticate"),
+				},
+			},
+		},
+	}
+
+	expected := &listenerRule{
+		Rule:             rule,
+		ListenerArn:      "arn:aws:elasticloadbalancing:us-east-1:123456789012:listener/app/my-lb/50dc6c495c0c9188/f2f7dc8efc522ab2",
+		LoadBalancerArn:  "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/my-lb/50dc6c495c0c9188",
+		HostHeaders:      []string{"old.example.com"},
+		PathPatterns:     []string{"/api/*"},
+		HttpHeaders:      []*listenerRuleHttpHeader{{Name: aws.String("X-Env"), Values: []string{"beta"}}},
+		SourceIps:        []string{"10.0.0.0/8"},
+		ActionTypes:      []string{"authenticate-oidc", "forward"},
+		AuthenticateType: aws.String("authenticate-oidc"),
+		ForwardTargetGroups: []*elbv2.TargetGroupTuple{
+			{TargetGroupArn: aws.String("blue"), Weight: aws.Int64(90)},
+			{TargetGroupArn: aws.String("green"), Weight: aws.Int64(10)},
+		},
+		ForwardStickinessEnabled:             aws.Bool(true),
+		ForwardStickinessDurationSeconds:     aws.Int64(300),
+		AuthenticateOidcIssuer:               aws.String("https://idp.example.com"),
+		AuthenticateOidcClientId:             aws.String("client"),
+		AuthenticateScope:                    aws.String("openid"),
+		AuthenticateOnUnauthenticatedRequest: aws.String("authenticate"),
+	}
+
+	output := flattenListenerRule(rule)
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("FAILED: expected %+v, got %+v", expected, output)
+	}
+}
+
+func TestFlattenListenerRuleDefaultActions(t *testing.T) {
+	type flattenListenerRuleTest struct {
+		Name     string
+		Action   *elbv2.Action
+		Expected *listenerRule
+	}
+
+	ruleArn := "arn:aws:elasticloadbalancing:us-east-1:123456789012:listener-rule/app/my-lb/50dc6c495c0c9188/f2f7dc8efc522ab2/default"
+	tests := []flattenListenerRuleTest{
+		{
+			"forward without config",
+			&elbv2.Action{Type: aws.String("forward"), TargetGroupArn: aws.String("blue")},
+			&listenerRule{
+				ActionTypes:         []string{"forward"},
+				ForwardTargetGroups: []*elbv2.TargetGroupTuple{{TargetGroupArn: aws.String("blue")}},
+			},
+		},
+		{
+			"redirect",
+			&elbv2.Action{Type: aws.String("redirect"), RedirectConfig: &elbv2.RedirectActionConfig{Protocol: aws.String("HTTPS"), Port: aws.String("443"), StatusCode: aws.String("HTTP_301")}},
+			&listenerRule{
+				ActionTypes:        []string{"redirect"},
+				RedirectProtocol:   aws.String("HTTPS"),
+				RedirectPort:       aws.String("443"),
+				RedirectStatusCode: aws.String("HTTP_301"),
+			},
+		},
+		{
+			"fixed response",
+			&elbv2.Action{Type: aws.String("fixed-response"), FixedResponseConfig: &elbv2.FixedResponseActionConfig{StatusCode: aws.String("404"), ContentType: aws.String("text/plain")}},
+			&listenerRule{
+				ActionTypes:              []string{"fixed-response"},
+				FixedResponseStatusCode:  aws.String("404"),
+				FixedResponseContentType: aws.String("text/plain"),
+			},
+		},
+	}
+
+	for _, test := range tests {
+		rule := &elbv2.Rule{RuleArn: aws.String(ruleArn), Actions: []*elbv2.Action{test.Action}}
+		test.Expected.Rule = rule
+		test.Expected.ListenerArn = "arn:aws:elasticloadbalancing:us-east-1:123456789012:listener/app/my-lb/50dc6c495c0c9188/f2f7dc8efc522ab2"
+		test.Expected.LoadBalancerArn = "arn:aws:elasticloadbalancing:us-east-1:123456789012:loadbalancer/app/my-lb/50dc6c495c0c9188"
+
+		output := flattenListenerRule(rule)
+		if !reflect.DeepEqual(output, test.Expected) {
+			t.Errorf("Case '%s': expected %+v, got %+v", test.Name, test.Expected, output)
+		}
+	}
+}
@@ -0,0 +1,322 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+// Types of load balancer a policy is for
+const (
+	sslPolicyLoadBalancerTypeElbv2   = "elbv2"
+	sslPolicyLoadBalancerTypeClassic = "classic"
+)
+
+// Classic load balancer policy attributes which are not protocols or ciphers
+const (
+	classicSslPolicyTypeName               = "SSLNegotiationPolicyType"
+	classicSslPolicyProtocolPrefix         = "Protocol-"
+	classicSslPolicyReferenceAttribute     = "Reference-Security-Policy"
+	classicSslPolicyServerCipherOrderField = "Server-Defined-Cipher-Order"
+)
+
+// weakCipherMarkers are parts of the OpenSSL names of ciphers with broken or
+// export grade encryption, no authentication, or MD5 message authentication
+var weakCipherMarkers = []string{"RC4", "DES", "NULL", "EXP", "MD5", "ADH", "AECDH", "anon"}
+
+//// TABLE DEFINITION
+
+func tableAwsEc2LoadBalancerSslPolicy(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_ec2_load_balancer_ssl_policy",
+		Description: "AWS EC2 Load Balancer SSL Policy",
+		List: &plugin.ListConfig{
+			Hydrate: listEc2LoadBalancerSslPolicies,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "name",
+				Description: "The name of the policy.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "load_balancer_type",
+				Description: "The kind of load balancer the policy is for: elbv2 for application and network load balancers, or classic.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "load_balancer_name",
+				Description: "The name of the classic load balancer a custom policy belongs to. Null for predefined policies.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "reference_security_policy",
+				Description: "The predefined policy a classic load balancer policy was created from.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "ssl_protocols",
+				Description: "The protocols the policy supports, e.g. TLSv1.2.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "ciphers",
+				Description: "The ciphers the policy supports, by OpenSSL name, in order of preference for elbv2 policies.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "supports_tls10",
+				Description: "True if the policy supports TLS 1.0.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("SupportsTLS10"),
+			},
+			{
+				Name:        "weak_ciphers",
+				Description: "The ciphers of the policy which are weak: CBC ciphers with SHA-1 message authentication, RC4, DES and 3DES, NULL, export, anonymous and MD5 ciphers.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "forward_secrecy",
+				Description: "True if every cipher of the policy uses an ephemeral key exchange, so a compromised private key cannot decrypt recorded traffic.",
+				Type:        proto.ColumnType_BOOL,
+			},
+
+			// Standard columns
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(loadBalancerSslPolicyTitle),
+			},
+		}),
+		DefaultTransform: transform.FromGo(),
+	}
+}
+
+type loadBalancerSslPolicy struct {
+	Name                    string
+	LoadBalancerType        string
+	LoadBalancerName        *string
+	ReferenceSecurityPolicy *string
+	SslProtocols            []string
+	Ciphers                 []string
+	SupportsTLS10           bool
+	WeakCiphers             []string
+	ForwardSecrecy          bool
+}
+
+//// LIST FUNCTION
+
+func listEc2LoadBalancerSslPolicies(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	plugin.Logger(ctx).Trace("listEc2LoadBalancerSslPolicies", "AWS_REGION", region)
+
+	elbv2Policies, err := getElbv2SslPolicies(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(elbv2Policies))
+	for name := range elbv2Policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		d.StreamListItem(ctx, elbv2Policies[name])
+	}
+
+	// Create Session
+	svc, err := ELBService(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	// Predefined policies of classic load balancers
+	op, err := svc.DescribeLoadBalancerPolicies(&elb.DescribeLoadBalancerPoliciesInput{})
+	if err != nil {
+		return nil, err
+	}
+	for _, description := range op.PolicyDescriptions {
+		if types.SafeString(description.PolicyTypeName) == classicSslPolicyTypeName {
+			d.StreamListItem(ctx, classicSslPolicy(description, nil))
+		}
+	}
+
+	// Custom policies, which belong to a single classic load balancer
+	var loadBalancerNames []*string
+	err = svc.DescribeLoadBalancersPages(
+		&elb.DescribeLoadBalancersInput{},
+		func(page *elb.DescribeLoadBalancersOutput, isLast bool) bool {
+			for _, loadBalancer := range page.LoadBalancerDescriptions {
+				loadBalancerNames = append(loadBalancerNames, loadBalancer.LoadBalancerName)
+			}
+			return !isLast
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	for _, loadBalancerName := range loadBalancerNames {
+		op, err := svc.DescribeLoadBalancerPolicies(&elb.DescribeLoadBalancerPoliciesInput{
+			LoadBalancerName: loadBalancerName,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, description := range op.PolicyDescriptions {
+			if types.SafeString(description.PolicyTypeName) == classicSslPolicyTypeName {
+				d.StreamListItem(ctx, classicSslPolicy(description, loadBalancerName))
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+// getElbv2SslPolicies returns the predefined policies of application and
+// network load balancers in a region, by name
+func getElbv2SslPolicies(ctx context.Context, d *plugin.QueryData, region string) (map[string]*loadBalancerSslPolicy, error) {
+	cacheKey := fmt.Sprintf("elbv2-ssl-policies-%s", region)
+	if cachedData, ok := d.ConnectionManager.Cache.Get(cacheKey); ok {
+		return cachedData.(map[string]*loadBalancerSslPolicy), nil
+	}
+
+	// Create Session
+	svc, err := ELBv2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	policies := map[string]*loadBalancerSslPolicy{}
+	params := &elbv2.DescribeSSLPoliciesInput{}
+	for {
+		op, err := svc.DescribeSSLPolicies(params)
+		if err != nil {
+			return nil, err
+		}
+		for _, policy := range op.SslPolicies {
+			policies[types.SafeString(policy.Name)] = elbv2SslPolicy(policy)
+		}
+		if op.NextMarker == nil {
+			break
+		}
+		params.Marker = op.NextMarker
+	}
+
+	d.ConnectionManager.Cache.Set(cacheKey, policies)
+	return policies, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+func loadBalancerSslPolicyTitle(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	policy := d.HydrateItem.(*loadBalancerSslPolicy)
+	if policy.LoadBalancerName != nil {
+		return *policy.LoadBalancerName + "/" + policy.Name, nil
+	}
+	return policy.Name, nil
+}
+
+//// UTILITY FUNCTIONS
+
+// elbv2SslPolicy returns the protocols and ciphers of an application or
+// network load balancer policy
+func elbv2SslPolicy(policy *elbv2.SslPolicy) *loadBalancerSslPolicy {
+	ciphers := make([]*elbv2.Cipher, len(policy.Ciphers))
+	copy(ciphers, policy.Ciphers)
+	sort.SliceStable(ciphers, func(i, j int) bool {
+		return types.Int64Value(ciphers[i].Priority) < types.Int64Value(ciphers[j].Priority)
+	})
+
+	names := []string{}
+	for _, cipher := range ciphers {
+		names = append(names, types.SafeString(cipher.Name))
+	}
+	return newLoadBalancerSslPolicy(types.SafeString(policy.Name), sslPolicyLoadBalancerTypeElbv2, aws.StringValueSlice(policy.SslProtocols), names)
+}
+
+// classicSslPolicy returns the protocols and ciphers of a classic load
+// balancer policy. Protocols and ciphers are attributes of the policy, enabled
+// when their value is true.
+func classicSslPolicy(description *elb.PolicyDescription, loadBalancerName *string) *loadBalancerSslPolicy {
+	protocols := []string{}
+	ciphers := []string{}
+	var reference *string
+	for _, attribute := range description.PolicyAttributeDescriptions {
+		name := types.SafeString(attribute.AttributeName)
+		value := types.SafeString(attribute.AttributeValue)
+		switch {
+		case name == classicSslPolicyReferenceAttribute:
+			reference = attribute.AttributeValue
+		case name == classicSslPolicyServerCipherOrderField:
+		case value != "true":
+		case strings.HasPrefix(name, classicSslPolicyProtocolPrefix):
+			protocols = append(protocols, strings.TrimPrefix(name, classicSslPolicyProtocolPrefix))
+		default:
+			ciphers = append(ciphers, name)
+		}
+	}
+
+	policy := newLoadBalancerSslPolicy(types.SafeString(description.PolicyName), sslPolicyLoadBalancerTypeClassic, protocols, ciphers)
+	policy.LoadBalancerName = loadBalancerName
+	policy.ReferenceSecurityPolicy = reference
+	return policy
+}
+
+func newLoadBalancerSslPolicy(name string, loadBalancerType string, protocols []string, ciphers []string) *loadBalancerSslPolicy {
+	policy := &loadBalancerSslPolicy{
+		Name:             name,
+		LoadBalancerType: loadBalancerType,
+		SslProtocols:     protocols,
+		Ciphers:          ciphers,
+		SupportsTLS10:    helpers.StringSliceContains(protocols, "TLSv1"),
+		WeakCiphers:      []string{},
+		ForwardSecrecy:   len(ciphers) > 0,
+	}
+	for _, cipher := range ciphers {
+		if isWeakCipher(cipher) {
+			policy.WeakCiphers = append(policy.WeakCiphers, cipher)
+		}
+		if !hasForwardSecrecy(cipher) {
+			policy.ForwardSecrecy = false
+		}
+	}
+	return policy
+}
+
+// isWeakCipher returns true for ciphers with SHA-1 message authentication, which
+// in TLS 1.2 and earlier are the CBC ciphers, and for ciphers with a weak
+// marker in their OpenSSL name
+func isWeakCipher(cipher string) bool {
+	if strings.HasSuffix(cipher, "-SHA") {
+		return true
+	}
+	for _, marker := range weakCipherMarkers {
+		if strings.Contains(cipher, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasForwardSecrecy returns true for ciphers with an ephemeral Diffie-Hellman
+// key exchange. TLS 1.3 ciphers, named TLS_*, always have one.
+func hasForwardSecrecy(cipher string) bool {
+	return strings.HasPrefix(cipher, "ECDHE-") || strings.HasPrefix(cipher, "DHE-") || strings.HasPrefix(cipher, "TLS_")
+}
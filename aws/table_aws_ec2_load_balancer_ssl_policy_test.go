@@ -0,0 +1,117 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/elb"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+)
+
+func TestElbv2SslPolicy(t *testing.T) {
+	policy := &elbv2.SslPolicy{
+		Name:         aws.String("ELBSecurityPolicy-2016-08"),
+		SslProtocols: aws.StringSlice([]string{"TLSv1", "TLSv1.1", "TLSv1.2"}),
+		Ciphers: []*elbv2.Cipher{
+			{Name: aws.String("AES128-SHA"), Priority: aws.Int64(3)},
+			{Name: aws.String("ECDHE-RSA-AES128-GCM-SHA256"), Priority: aws.Int64(1)},
+			{Name: aws.String("ECDHE-RSA-AES128-SHA"), Priority: aws.Int64(2)},
+		},
+	}
+
+	expected := &loadBalancerSslPolicy{
+		Name:             "ELBSecurityPolicy-2016-08",
+		LoadBalancerType: "elbv2",
+		SslProtocols:     []string{"TLSv1", "TLSv1.1", "TLSv1.2"},
+		Ciphers:          []string{"ECDHE-RSA-AES128-GCM-SHA256", "ECDHE-RSA-AES128-SHA", "AES128-SHA"},
+		SupportsTLS10:    true,
+		WeakCiphers:      []string{"ECDHE-RSA-AES128-SHA", "AES128-SHA"},
+		ForwardSecrecy:   false,
+	}
+
+	output := elbv2SslPolicy(policy)
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("FAILED: expected %+v, got %+v", expected, output)
+	}
+}
+
+func TestClassicSslPolicy(t *testing.T) {
+	description := &elb.PolicyDescription{
+		PolicyName:     aws.String("custom-tls12"),
+		PolicyTypeName: aws.String("SSLNegotiationPolicyType"),
+		PolicyAttributeDescriptions: []*elb.PolicyAttributeDescription{
+			{AttributeName: aws.String("Reference-Security-Policy"), AttributeValue: aws.String("ELBSecurityPolicy-TLS-1-2-2017-01")},
+			{AttributeName: aws.String("Protocol-TLSv1"), AttributeValue: aws.String("false")},
+			{AttributeName: aws.String("Protocol-TLSv1.2"), AttributeValue: aws.String("true")},
+			{AttributeName: aws.String("Server-Defined-Cipher-Order"), AttributeValue: aws.String("true")},
+			{AttributeName: aws.String("ECDHE-ECDSA-AES128-GCM-SHA256"), AttributeValue: aws.String("true")},
+			{AttributeName: aws.String("DHE-RSA-AES256-GCM-SHA384"), AttributeValue: aws.String("true")},
+			{AttributeName: aws.String("DES-CBC3-SHA"), AttributeValue: aws.String("false")},
+		},
+	}
+
+	expected := &loadBalancerSslPolicy{
+		Name:                    "custom-tls12",
+		LoadBalancerType:        "classic",
+		LoadBalancerName:        aws.String("my-elb"),
+		ReferenceSecurityPolicy: aws.String("ELBSecurityPolicy-TLS-1-2-2017-01"),
+		SslProtocols:            []string{"TLSv1.2"},
+		Ciphers:                 []string{"ECDHE-ECDSA-AES128-GCM-SHA256", "DHE-RSA-AES256-GCM-SHA384"},
+		SupportsTLS10:           false,
+		WeakCiphers:             []string{},
+		ForwardSecrecy:          true,
+	}
+
+	output := classicSslPolicy(description, aws.String("my-elb"))
+	if !reflect.DeepEqual(output, expected) {
+		t.Errorf("FAILED: expected %+v, got %+v", expected, output)
+	}
+}
+
+func TestIsWeakCipher(t *testing.T) {
+	type isWeakCipherTest struct {
+		Cipher   string
+		Expected bool
+	}
+
+	tests := []isWeakCipherTest{
+		{"ECDHE-ECDSA-AES128-GCM-SHA256", false},
+		{"ECDHE-RSA-AES256-SHA384", false},
+		{"TLS_AES_128_GCM_SHA256", false},
+		{"ECDHE-RSA-AES128-SHA", true},
+		{"DES-CBC3-SHA", true},
+		{"RC4-MD5", true},
+		{"EXP-RC2-CBC-MD5", true},
+		{"ADH-AES128-GCM-SHA256", true},
+	}
+
+	for _, test := range tests {
+		output := isWeakCipher(test.Cipher)
+		if output != test.Expected {
+			t.Errorf("Case '%s': expected %t, got %t", test.Cipher, test.Expected, output)
+		}
+	}
+}
+
+func TestHasForwardSecrecy(t *testing.T) {
+	type hasForwardSecrecyTest struct {
+		Cipher   string
+		Expected bool
+	}
+
+	tests := []hasForwardSecrecyTest{
+		{"ECDHE-ECDSA-AES128-GCM-SHA256", true},
+		{"DHE-RSA-AES256-SHA", true},
+		{"TLS_CHACHA20_POLY1305_SHA256", true},
+		{"AES128-GCM-SHA256", false},
+		{"ADH-AES128-SHA", false},
+	}
+
+	for _, test := range tests {
+		output := hasForwardSecrecy(test.Cipher)
+		if output != test.Expected {
+			t.Errorf("Case '%s': expected %t, got %t", test.Cipher, test.Expected, output)
+		}
+	}
+}
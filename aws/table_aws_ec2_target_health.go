@@ -0,0 +1,152 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableAwsEc2TargetHealth(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_ec2_target_health",
+		Description: "AWS EC2 Target Health",
+		List: &plugin.ListConfig{
+			ParentHydrate: listEc2TargetGroups,
+			Hydrate:       listEc2TargetHealth,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "target_group_arn",
+				Description: "The Amazon Resource Name (ARN) of the target group.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("TargetGroup.TargetGroupArn"),
+			},
+			{
+				Name:        "target_group_name",
+				Description: "The name of the target group.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("TargetGroup.TargetGroupName"),
+			},
+			{
+				Name:        "target_type",
+				Description: "The type of the targets of the target group (instance | ip | lambda).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("TargetGroup.TargetType"),
+			},
+			{
+				Name:        "target_id",
+				Description: "The ID of the target: an instance ID, an IP address or the ARN of a Lambda function.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Description.Target.Id"),
+			},
+			{
+				Name:        "port",
+				Description: "The port on which the target is listening.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("Description.Target.Port"),
+			},
+			{
+				Name:        "availability_zone",
+				Description: "The Availability Zone of an IP target, or all if the target is outside the VPC of the target group.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Description.Target.AvailabilityZone"),
+			},
+			{
+				Name:        "health_check_port",
+				Description: "The port used for health checks of the target.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Description.HealthCheckPort"),
+			},
+			{
+				Name:        "state",
+				Description: "The health of the target (initial | healthy | unhealthy | unused | draining | unavailable).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Description.TargetHealth.State"),
+			},
+			{
+				Name:        "reason",
+				Description: "The reason code for a target which is not healthy, e.g. Target.ResponseCodeMismatch.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Description.TargetHealth.Reason"),
+			},
+			{
+				Name:        "description",
+				Description: "A description of the health of the target.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Description.TargetHealth.Description"),
+			},
+
+			// Standard columns
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(targetHealthTitle),
+			},
+		}),
+	}
+}
+
+type targetHealth struct {
+	TargetGroup *elbv2.TargetGroup
+	Description *elbv2.TargetHealthDescription
+}
+
+//// LIST FUNCTION
+
+func listEc2TargetHealth(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	targetGroup := h.Item.(*elbv2.TargetGroup)
+	plugin.Logger(ctx).Trace("listEc2TargetHealth", "AWS_REGION", region, "targetGroup", targetGroup.TargetGroupArn)
+
+	// Describing target health is one call per target group, so skip the
+	// groups which are not queried
+	if targetGroupArn := getOptionalStringQual(d, "target_group_arn"); targetGroupArn != "" && targetGroupArn != types.SafeString(targetGroup.TargetGroupArn) {
+		return nil, nil
+	}
+	if targetGroupName := getOptionalStringQual(d, "target_group_name"); targetGroupName != "" && targetGroupName != types.SafeString(targetGroup.TargetGroupName) {
+		return nil, nil
+	}
+
+	// Create Session
+	svc, err := ELBv2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := svc.DescribeTargetHealth(&elbv2.DescribeTargetHealthInput{
+		TargetGroupArn: targetGroup.TargetGroupArn,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, description := range op.TargetHealthDescriptions {
+		d.StreamLeafListItem(ctx, &targetHealth{targetGroup, description})
+	}
+
+	return nil, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+func targetHealthTitle(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	health := d.HydrateItem.(*targetHealth)
+	title := types.SafeString(health.TargetGroup.TargetGroupName) + "/" + types.SafeString(health.Description.Target.Id)
+	if health.Description.Target.Port != nil {
+		title = fmt.Sprintf("%s:%d", title, *health.Description.Target.Port)
+	}
+	return title, nil
+}
@@ -0,0 +1,352 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableAwsEc2TransitGatewayAttachment(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_ec2_transit_gateway_attachment",
+		Description: "AWS EC2 Transit Gateway Attachment",
+		Get: &plugin.GetConfig{
+			KeyColumns:        plugin.SingleColumn("transit_gateway_attachment_id"),
+			ShouldIgnoreError: isNotFoundError([]string{"InvalidTransitGatewayAttachmentID.NotFound", "InvalidTransitGatewayAttachmentID.Unavailable", "InvalidTransitGatewayAttachmentID.Malformed"}),
+			Hydrate:           getEc2TransitGatewayAttachment,
+		},
+		List: &plugin.ListConfig{
+			Hydrate: listEc2TransitGatewayAttachments,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "transit_gateway_attachment_id",
+				Description: "The ID of the transit gateway attachment.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "transit_gateway_id",
+				Description: "The ID of the transit gateway.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "transit_gateway_owner_id",
+				Description: "The ID of the AWS account that owns the transit gateway.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "resource_type",
+				Description: "The type of the attached resource (vpc | vpn | direct-connect-gateway | connect | peering | tgw-peering).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "resource_id",
+				Description: "The ID of the attached resource: a VPC, VPN connection, Direct Connect gateway, transport attachment for Connect attachments, or peer transit gateway.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "resource_owner_id",
+				Description: "The ID of the AWS account that owns the resource.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "state",
+				Description: "The state of the attachment.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "creation_time",
+				Description: "The creation time of the attachment.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "association_state",
+				Description: "The state of the association with a route table.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Association.State"),
+			},
+			{
+				Name:        "association_transit_gateway_route_table_id",
+				Description: "The ID of the route table the attachment is associated with, which decides where traffic from the attachment goes.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Association.TransitGatewayRouteTableId"),
+			},
+			{
+				Name:        "propagations",
+				Description: "The route tables the attachment propagates its routes to.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getEc2TransitGatewayAttachmentPropagations,
+				Transform:   transform.FromValue(),
+			},
+			{
+				Name:        "peer_transit_gateway_id",
+				Description: "The ID of the transit gateway on the other side of a peering attachment.",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     getEc2TransitGatewayPeeringAttachment,
+				Transform:   transform.FromField("Peer.TransitGatewayId"),
+			},
+			{
+				Name:        "peer_owner_id",
+				Description: "The ID of the AWS account that owns the peer transit gateway of a peering attachment.",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     getEc2TransitGatewayPeeringAttachment,
+				Transform:   transform.FromField("Peer.OwnerId"),
+			},
+			{
+				Name:        "peer_region",
+				Description: "The region of the peer transit gateway of a peering attachment.",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     getEc2TransitGatewayPeeringAttachment,
+				Transform:   transform.FromField("Peer.Region"),
+			},
+			{
+				Name:        "peering_status_code",
+				Description: "The status of a peering attachment.",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     getEc2TransitGatewayPeeringAttachment,
+				Transform:   transform.FromField("Peering.Status.Code"),
+			},
+			{
+				Name:        "connect_transport_attachment_id",
+				Description: "The ID of the VPC or Direct Connect attachment a Connect attachment runs over.",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     getEc2TransitGatewayConnect,
+				Transform:   transform.FromField("TransportTransitGatewayAttachmentId"),
+			},
+			{
+				Name:        "connect_protocol",
+				Description: "The tunnel protocol of a Connect attachment.",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     getEc2TransitGatewayConnect,
+				Transform:   transform.FromField("Options.Protocol"),
+			},
+			{
+				Name:        "tags_src",
+				Description: "A list of tags assigned.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Tags"),
+			},
+
+			/// Standard columns
+			{
+				Name:        "tags",
+				Description: resourceInterfaceDescription("tags"),
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.From(transitGatewayAttachmentRawTagsToTurbotTags),
+			},
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(getEc2TransitGatewayAttachmentTitle),
+			},
+			{
+				Name:        "akas",
+				Description: resourceInterfaceDescription("akas"),
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getAwsEc2TransitGatewayVpcAttachmentAkas,
+				Transform:   transform.FromValue(),
+			},
+		}),
+	}
+}
+
+// transitGatewayPeering is a peering attachment and the transit gateway on its
+// other side
+type transitGatewayPeering struct {
+	Peering *ec2.TransitGatewayPeeringAttachment
+	Peer    *ec2.PeeringTgwInfo
+}
+
+//// LIST FUNCTION
+
+func listEc2TransitGatewayAttachments(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	plugin.Logger(ctx).Trace("listEc2TransitGatewayAttachments", "AWS_REGION", region)
+
+	// Create Session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	// Filter on the server when the query is for one transit gateway or type
+	params := &ec2.DescribeTransitGatewayAttachmentsInput{}
+	if transitGatewayID := getOptionalStringQual(d, "transit_gateway_id"); transitGatewayID != "" {
+		params.Filters = append(params.Filters, &ec2.Filter{Name: aws.String("transit-gateway-id"), Values: []*string{aws.String(transitGatewayID)}})
+	}
+	if resourceType := getOptionalStringQual(d, "resource_type"); resourceType != "" {
+		params.Filters = append(params.Filters, &ec2.Filter{Name: aws.String("resource-type"), Values: []*string{aws.String(resourceType)}})
+	}
+
+	// List call
+	err = svc.DescribeTransitGatewayAttachmentsPages(
+		params,
+		func(page *ec2.DescribeTransitGatewayAttachmentsOutput, isLast bool) bool {
+			for _, transitGatewayAttachment := range page.TransitGatewayAttachments {
+				d.StreamListItem(ctx, transitGatewayAttachment)
+			}
+			return !isLast
+		},
+	)
+
+	return nil, err
+}
+
+//// HYDRATE FUNCTIONS
+
+func getEc2TransitGatewayAttachment(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getEc2TransitGatewayAttachment")
+
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	transitGatewayAttachmentID := d.KeyColumnQuals["transit_gateway_attachment_id"].GetStringValue()
+
+	// Create Session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build params
+	params := &ec2.DescribeTransitGatewayAttachmentsInput{
+		TransitGatewayAttachmentIds: []*string{aws.String(transitGatewayAttachmentID)},
+	}
+
+	op, err := svc.DescribeTransitGatewayAttachments(params)
+	if err != nil {
+		plugin.Logger(ctx).Debug("getEc2TransitGatewayAttachment__", "ERROR", err)
+		return nil, err
+	}
+
+	if op.TransitGatewayAttachments != nil && len(op.TransitGatewayAttachments) > 0 {
+		return op.TransitGatewayAttachments[0], nil
+	}
+	return nil, nil
+}
+
+func getEc2TransitGatewayAttachmentPropagations(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getEc2TransitGatewayAttachmentPropagations")
+
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	attachment := h.Item.(*ec2.TransitGatewayAttachment)
+
+	// Attachments being created or deleted have no propagations
+	if types.SafeString(attachment.State) != ec2.TransitGatewayAttachmentStateAvailable {
+		return nil, nil
+	}
+
+	// Create Session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	propagations := []*ec2.TransitGatewayAttachmentPropagation{}
+	err = svc.GetTransitGatewayAttachmentPropagationsPages(
+		&ec2.GetTransitGatewayAttachmentPropagationsInput{
+			TransitGatewayAttachmentId: attachment.TransitGatewayAttachmentId,
+		},
+		func(page *ec2.GetTransitGatewayAttachmentPropagationsOutput, isLast bool) bool {
+			propagations = append(propagations, page.TransitGatewayAttachmentPropagations...)
+			return !isLast
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return propagations, nil
+}
+
+func getEc2TransitGatewayPeeringAttachment(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getEc2TransitGatewayPeeringAttachment")
+
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	attachment := h.Item.(*ec2.TransitGatewayAttachment)
+
+	if types.SafeString(attachment.ResourceType) != ec2.TransitGatewayAttachmentResourceTypePeering {
+		return nil, nil
+	}
+
+	// Create Session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := svc.DescribeTransitGatewayPeeringAttachments(&ec2.DescribeTransitGatewayPeeringAttachmentsInput{
+		TransitGatewayAttachmentIds: []*string{attachment.TransitGatewayAttachmentId},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(op.TransitGatewayPeeringAttachments) == 0 {
+		return nil, nil
+	}
+
+	// The peer is whichever side is not the transit gateway of the attachment
+	peering := op.TransitGatewayPeeringAttachments[0]
+	peer := peering.AccepterTgwInfo
+	if peer != nil && types.SafeString(peer.TransitGatewayId) == types.SafeString(attachment.TransitGatewayId) {
+		peer = peering.RequesterTgwInfo
+	}
+	return &transitGatewayPeering{peering, peer}, nil
+}
+
+func getEc2TransitGatewayConnect(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getEc2TransitGatewayConnect")
+
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	attachment := h.Item.(*ec2.TransitGatewayAttachment)
+
+	if types.SafeString(attachment.ResourceType) != ec2.TransitGatewayAttachmentResourceTypeConnect {
+		return nil, nil
+	}
+
+	// Create Session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := svc.DescribeTransitGatewayConnects(&ec2.DescribeTransitGatewayConnectsInput{
+		TransitGatewayAttachmentIds: []*string{attachment.TransitGatewayAttachmentId},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(op.TransitGatewayConnects) > 0 {
+		return op.TransitGatewayConnects[0], nil
+	}
+	return nil, nil
+}
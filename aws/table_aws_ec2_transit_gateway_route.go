@@ -0,0 +1,323 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+// transitGatewayRouteSearchLimit is the most routes a search of a transit
+// gateway route table returns
+const transitGatewayRouteSearchLimit = 1000
+
+//// TABLE DEFINITION
+
+func tableAwsEc2TransitGatewayRoute(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_ec2_transit_gateway_route",
+		Description: "AWS EC2 Transit Gateway Route",
+		List: &plugin.ListConfig{
+			ParentHydrate: listEc2TransitGatewayRouteTable,
+			Hydrate:       listEc2TransitGatewayRoutes,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "transit_gateway_route_table_id",
+				Description: "The ID of the transit gateway route table.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("RouteTable.TransitGatewayRouteTableId"),
+			},
+			{
+				Name:        "transit_gateway_id",
+				Description: "The ID of the transit gateway.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("RouteTable.TransitGatewayId"),
+			},
+			{
+				Name:        "destination_cidr_block",
+				Description: "The CIDR block used for destination matches.",
+				Type:        proto.ColumnType_CIDR,
+				Transform:   transform.FromField("Route.DestinationCidrBlock"),
+			},
+			{
+				Name:        "prefix_list_id",
+				Description: "The ID of the prefix list used for destination matches.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Route.PrefixListId"),
+			},
+			{
+				Name:        "is_prefix_list",
+				Description: "True if the route matches the destinations of a prefix list rather than a CIDR block.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.From(isTransitGatewayPrefixListRoute),
+			},
+			{
+				Name:        "type",
+				Description: "The type of the route (static | propagated).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Route.Type"),
+			},
+			{
+				Name:        "state",
+				Description: "The state of the route (pending | active | blackhole | deleting | deleted).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Route.State"),
+			},
+			{
+				Name:        "transit_gateway_attachment_id",
+				Description: "The ID of the attachment the route sends traffic to. Equal-cost routes over several attachments show the first, and list all of them in transit_gateway_attachments.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromP(transitGatewayRouteAttachmentField, "TransitGatewayAttachmentId"),
+			},
+			{
+				Name:        "resource_id",
+				Description: "The ID of the resource of the attachment, such as a VPC or VPN connection.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromP(transitGatewayRouteAttachmentField, "ResourceId"),
+			},
+			{
+				Name:        "resource_type",
+				Description: "The type of the resource of the attachment (vpc | vpn | direct-connect-gateway | connect | peering | tgw-peering).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromP(transitGatewayRouteAttachmentField, "ResourceType"),
+			},
+			{
+				Name:        "transit_gateway_attachments",
+				Description: "The attachments the route sends traffic to.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Route.TransitGatewayAttachments"),
+			},
+
+			// Standard columns
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(transitGatewayRouteTitle),
+			},
+		}),
+	}
+}
+
+type transitGatewayRoute struct {
+	RouteTable *ec2.TransitGatewayRouteTable
+	Route      *ec2.TransitGatewayRoute
+}
+
+//// LIST FUNCTION
+
+func listEc2TransitGatewayRoutes(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	routeTable := h.Item.(*ec2.TransitGatewayRouteTable)
+	plugin.Logger(ctx).Trace("listEc2TransitGatewayRoutes", "AWS_REGION", region, "routeTable", routeTable.TransitGatewayRouteTableId)
+
+	// Searching a route table is one call per table, so skip the tables which
+	// are not queried
+	if routeTableID := getOptionalStringQual(d, "transit_gateway_route_table_id"); routeTableID != "" && routeTableID != types.SafeString(routeTable.TransitGatewayRouteTableId) {
+		return nil, nil
+	}
+	if types.SafeString(routeTable.State) != ec2.TransitGatewayRouteTableStateAvailable {
+		return nil, nil
+	}
+
+	// Create Session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	routes, err := searchTransitGatewayRoutes(svc, routeTable.TransitGatewayRouteTableId)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, route := range routes {
+		d.StreamLeafListItem(ctx, &transitGatewayRoute{routeTable, route})
+	}
+
+	return nil, nil
+}
+
+//// UTILITY FUNCTIONS
+
+// transitGatewayRouteSearch collects the routes of a route table from several
+// searches, keyed by destination
+type transitGatewayRouteSearch struct {
+	svc          *ec2.EC2
+	routeTableID *string
+	routes       map[string]*ec2.TransitGatewayRoute
+}
+
+// searchTransitGatewayRoutes returns every route of a route table. A search
+// returns at most 1000 routes, so a truncated search is split by route type,
+// and then into smaller and smaller CIDR blocks, until no search is truncated.
+func searchTransitGatewayRoutes(svc *ec2.EC2, routeTableID *string) ([]*ec2.TransitGatewayRoute, error) {
+	search := &transitGatewayRouteSearch{svc, routeTableID, map[string]*ec2.TransitGatewayRoute{}}
+
+	truncated, err := search.run()
+	if err != nil || !truncated {
+		return search.sortedRoutes(), err
+	}
+
+	for _, routeType := range []string{ec2.TransitGatewayRouteTypeStatic, ec2.TransitGatewayRouteTypePropagated} {
+		truncated, err := search.run(newEc2Filter("type", routeType))
+		if err != nil {
+			return nil, err
+		}
+		if !truncated {
+			continue
+		}
+		for _, block := range []string{"0.0.0.0/0", "::/0"} {
+			_, cidr, _ := net.ParseCIDR(block)
+			if err := search.runCidr(routeType, cidr); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// Routes to prefix lists have no CIDR block, so are searched for by the
+	// prefix lists the route table references
+	var searchErr error
+	err = svc.GetTransitGatewayPrefixListReferencesPages(
+		&ec2.GetTransitGatewayPrefixListReferencesInput{TransitGatewayRouteTableId: routeTableID},
+		func(page *ec2.GetTransitGatewayPrefixListReferencesOutput, lastPage bool) bool {
+			for _, reference := range page.TransitGatewayPrefixListReferences {
+				if _, searchErr = search.run(newEc2Filter("prefix-list-id", types.SafeString(reference.PrefixListId))); searchErr != nil {
+					return false
+				}
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	if searchErr != nil {
+		return nil, searchErr
+	}
+
+	return search.sortedRoutes(), nil
+}
+
+// run searches for the active and blackhole routes which match the filters,
+// and returns true if there are more routes than the search returned
+func (search *transitGatewayRouteSearch) run(filters ...*ec2.Filter) (bool, error) {
+	// The search requires a filter, and routes are either active or blackhole
+	// once created
+	params := &ec2.SearchTransitGatewayRoutesInput{
+		TransitGatewayRouteTableId: search.routeTableID,
+		Filters:                    append([]*ec2.Filter{newEc2Filter("state", ec2.TransitGatewayRouteStateActive, ec2.TransitGatewayRouteStateBlackhole)}, filters...),
+		MaxResults:                 aws.Int64(transitGatewayRouteSearchLimit),
+	}
+
+	op, err := search.svc.SearchTransitGatewayRoutes(params)
+	if err != nil {
+		return false, err
+	}
+	for _, route := range op.Routes {
+		search.routes[types.SafeString(route.DestinationCidrBlock)+types.SafeString(route.PrefixListId)] = route
+	}
+	return types.BoolValue(op.AdditionalRoutesAvailable), nil
+}
+
+// runCidr searches for the routes of a type to a CIDR block and its subnets,
+// splitting the block in two while the searches are truncated
+func (search *transitGatewayRouteSearch) runCidr(routeType string, cidr *net.IPNet) error {
+	if _, err := search.run(newEc2Filter("type", routeType), newEc2Filter("route-search.exact-match", cidr.String())); err != nil {
+		return err
+	}
+	for _, half := range splitCidr(cidr) {
+		truncated, err := search.run(newEc2Filter("type", routeType), newEc2Filter("route-search.subnet-of-match", half.String()))
+		if err != nil {
+			return err
+		}
+		if truncated {
+			if err := search.runCidr(routeType, half); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (search *transitGatewayRouteSearch) sortedRoutes() []*ec2.TransitGatewayRoute {
+	keys := make([]string, 0, len(search.routes))
+	for key := range search.routes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	routes := make([]*ec2.TransitGatewayRoute, 0, len(keys))
+	for _, key := range keys {
+		routes = append(routes, search.routes[key])
+	}
+	return routes
+}
+
+// splitCidr returns the two halves of a CIDR block, or none for a single address
+func splitCidr(cidr *net.IPNet) []*net.IPNet {
+	ones, bits := cidr.Mask.Size()
+	if ones >= bits {
+		return nil
+	}
+	mask := net.CIDRMask(ones+1, bits)
+	lower := &net.IPNet{IP: cidr.IP.Mask(mask), Mask: mask}
+	upper := &net.IPNet{IP: append(net.IP{}, lower.IP...), Mask: mask}
+	upper.IP[ones/8] |= 0x80 >> uint(ones%8)
+	return []*net.IPNet{lower, upper}
+}
+
+func newEc2Filter(name string, values ...string) *ec2.Filter {
+	return &ec2.Filter{
+		Name:   aws.String(name),
+		Values: aws.StringSlice(values),
+	}
+}
+
+//// TRANSFORM FUNCTIONS
+
+// transitGatewayRouteAttachmentField returns a field of the first attachment of
+// the route
+func transitGatewayRouteAttachmentField(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	route := d.HydrateItem.(*transitGatewayRoute)
+	if len(route.Route.TransitGatewayAttachments) == 0 {
+		return nil, nil
+	}
+	attachment := route.Route.TransitGatewayAttachments[0]
+	switch d.Param.(string) {
+	case "TransitGatewayAttachmentId":
+		return attachment.TransitGatewayAttachmentId, nil
+	case "ResourceId":
+		return attachment.ResourceId, nil
+	case "ResourceType":
+		return attachment.ResourceType, nil
+	}
+	return nil, nil
+}
+
+func isTransitGatewayPrefixListRoute(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	route := d.HydrateItem.(*transitGatewayRoute)
+	return route.Route.PrefixListId != nil, nil
+}
+
+func transitGatewayRouteTitle(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	route := d.HydrateItem.(*transitGatewayRoute)
+	destination := types.SafeString(route.Route.DestinationCidrBlock)
+	if destination == "" {
+		destination = types.SafeString(route.Route.PrefixListId)
+	}
+	return fmt.Sprintf("%s_%s", types.SafeString(route.RouteTable.TransitGatewayRouteTableId), destination), nil
+}
@@ -0,0 +1,36 @@
+package aws
+
+import (
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestSplitCidr(t *testing.T) {
+	cases := []struct {
+		cidr     string
+		expected []string
+	}{
+		{"0.0.0.0/0", []string{"0.0.0.0/1", "128.0.0.0/1"}},
+		{"10.0.0.0/8", []string{"10.0.0.0/9", "10.128.0.0/9"}},
+		{"10.0.0.0/15", []string{"10.0.0.0/16", "10.1.0.0/16"}},
+		{"192.168.1.4/31", []string{"192.168.1.4/32", "192.168.1.5/32"}},
+		{"192.168.1.4/32", []string{}},
+		{"::/0", []string{"::/1", "8000::/1"}},
+		{"2001:db8::/32", []string{"2001:db8::/33", "2001:db8:8000::/33"}},
+	}
+
+	for _, c := range cases {
+		_, cidr, err := net.ParseCIDR(c.cidr)
+		if err != nil {
+			t.Fatalf("Case '%s': %v", c.cidr, err)
+		}
+		halves := []string{}
+		for _, half := range splitCidr(cidr) {
+			halves = append(halves, half.String())
+		}
+		if !reflect.DeepEqual(halves, c.expected) {
+			t.Errorf("Case '%s': expected %v, got %v", c.cidr, c.expected, halves)
+		}
+	}
+}
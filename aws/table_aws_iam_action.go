@@ -2,6 +2,9 @@ package aws
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
 	"strings"
 
 	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
@@ -58,30 +61,51 @@ func tableAwsIamAction(_ context.Context) *plugin.Table {
 				Description: "The description for this action.",
 				Transform:   transform.FromGo(),
 			},
+			{
+				Name:        "resource_types",
+				Type:        proto.ColumnType_JSON,
+				Description: "The resource types the action can be applied to, with their ARN formats, condition keys and dependent actions.",
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "condition_keys",
+				Type:        proto.ColumnType_JSON,
+				Description: "The service specific condition keys supported by this action. Global condition keys such as aws:SourceIp are not included.",
+				Transform:   transform.FromGo(),
+			},
 		},
 	}
 }
 
 type awsIamPermissionData struct {
-	Action      string
-	Prefix      string
-	Privilege   string
-	AccessLevel string
-	Description string
+	Action        string
+	Prefix        string
+	Privilege     string
+	AccessLevel   string
+	Description   string
+	ResourceTypes []awsIamActionResourceType
+	ConditionKeys []string
+}
+
+type awsIamActionResourceType struct {
+	ResourceType     string
+	Required         bool
+	Arn              string
+	ConditionKeys    []string
+	DependentActions []string
 }
 
 //// LIST FUNCTION
 
 func listIamActions(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
-	for _, service := range permissionsData {
+	permissions, err := getIamPermissions(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, service := range permissions {
 		for _, privilege := range service.Privileges {
-			d.StreamListItem(ctx, awsIamPermissionData{
-				AccessLevel: privilege.AccessLevel,
-				Action:      strings.ToLower(service.Prefix + ":" + privilege.Privilege),
-				Description: privilege.Description,
-				Prefix:      service.Prefix,
-				Privilege:   privilege.Privilege,
-			})
+			d.StreamListItem(ctx, newIamPermissionData(service, privilege))
 		}
 	}
 	return nil, nil
@@ -93,19 +117,91 @@ func getIamAction(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateDat
 	plugin.Logger(ctx).Info("Item", h.Item)
 	action := d.KeyColumnQuals["action"].GetStringValue()
 
-	for _, service := range permissionsData {
+	permissions, err := getIamPermissions(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, service := range permissions {
 		for _, privilege := range service.Privileges {
 			a := strings.ToLower(service.Prefix + ":" + privilege.Privilege)
 			if a == strings.ToLower(action) {
-				return awsIamPermissionData{
-					AccessLevel: privilege.AccessLevel,
-					Action:      a,
-					Description: privilege.Description,
-					Prefix:      service.Prefix,
-					Privilege:   privilege.Privilege,
-				}, nil
+				return newIamPermissionData(service, privilege), nil
 			}
 		}
 	}
 	return nil, nil
 }
+
+//// UTILITY FUNCTIONS
+
+// getIamPermissions returns the IAM permissions catalog. The catalog built into
+// the plugin may be replaced by a newer Parliament catalog, read from the file
+// set in the iam_permissions_catalog_path connection config argument.
+func getIamPermissions(ctx context.Context, d *plugin.QueryData) (ParliamentPermissions, error) {
+	config := GetConfig(d.Connection)
+	if config.IamPermissionsCatalogPath == nil || *config.IamPermissionsCatalogPath == "" {
+		return permissionsData, nil
+	}
+	path := *config.IamPermissionsCatalogPath
+
+	// have we already loaded and cached the catalog?
+	cacheKey := "iam-permissions-" + path
+	if cachedData, ok := d.ConnectionManager.Cache.Get(cacheKey); ok {
+		return cachedData.(ParliamentPermissions), nil
+	}
+
+	plugin.Logger(ctx).Trace("getIamPermissions", "path", path)
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read IAM permissions catalog %s: %v", path, err)
+	}
+
+	var permissions ParliamentPermissions
+	if err := json.Unmarshal(data, &permissions); err != nil {
+		return nil, fmt.Errorf("failed to parse IAM permissions catalog %s: %v", path, err)
+	}
+	d.ConnectionManager.Cache.Set(cacheKey, permissions)
+
+	return permissions, nil
+}
+
+func newIamPermissionData(service ParliamentService, privilege ParliamentPrivilege) awsIamPermissionData {
+	// ARN formats of the resource types of the service
+	arns := map[string]string{}
+	for _, resource := range service.Resources {
+		arns[resource.Resource] = resource.Arn
+	}
+
+	resourceTypes := []awsIamActionResourceType{}
+	conditionKeys := []string{}
+	for _, resourceType := range privilege.ResourceTypes {
+		conditionKeys = append(conditionKeys, resourceType.ConditionKeys...)
+
+		// Condition keys which do not depend on a resource type are listed
+		// against an empty resource type
+		if resourceType.ResourceType == "" {
+			continue
+		}
+
+		// Required resource types are marked with an asterisk
+		name := strings.TrimSuffix(resourceType.ResourceType, "*")
+		resourceTypes = append(resourceTypes, awsIamActionResourceType{
+			ResourceType:     name,
+			Required:         strings.HasSuffix(resourceType.ResourceType, "*"),
+			Arn:              arns[name],
+			ConditionKeys:    resourceType.ConditionKeys,
+			DependentActions: resourceType.DependentActions,
+		})
+	}
+
+	return awsIamPermissionData{
+		AccessLevel:   privilege.AccessLevel,
+		Action:        strings.ToLower(service.Prefix + ":" + privilege.Privilege),
+		ConditionKeys: uniqueStrings(conditionKeys),
+		Description:   privilege.Description,
+		Prefix:        service.Prefix,
+		Privilege:     privilege.Privilege,
+		ResourceTypes: resourceTypes,
+	}
+}
@@ -0,0 +1,104 @@
+package aws
+
+import (
+	"context"
+	"strings"
+
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableAwsIamConditionKey(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_iam_condition_key",
+		Description: "AWS IAM Condition Key",
+		List: &plugin.ListConfig{
+			Hydrate: listIamConditionKeys,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "condition_key",
+				Type:        proto.ColumnType_STRING,
+				Description: "The condition key, e.g. s3:x-amz-acl.",
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "prefix",
+				Type:        proto.ColumnType_STRING,
+				Description: "The service prefix of the condition key, e.g. s3.",
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "service_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the service.",
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "type",
+				Type:        proto.ColumnType_STRING,
+				Description: "The type of the condition key value, e.g. String, ArrayOfString, Bool or Numeric.",
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "description",
+				Type:        proto.ColumnType_STRING,
+				Description: "The description of the condition key.",
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "actions",
+				Type:        proto.ColumnType_JSON,
+				Description: "The actions which support the condition key, in lower case.",
+				Transform:   transform.FromGo(),
+			},
+		},
+	}
+}
+
+type awsIamConditionKeyData struct {
+	ConditionKey string
+	Prefix       string
+	ServiceName  string
+	Type         string
+	Description  string
+	Actions      []string
+}
+
+//// LIST FUNCTION
+
+func listIamConditionKeys(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	permissions, err := getIamPermissions(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, service := range permissions {
+		// Actions by condition key. Condition keys are case insensitive.
+		actions := map[string][]string{}
+		for _, privilege := range service.Privileges {
+			action := strings.ToLower(service.Prefix + ":" + privilege.Privilege)
+			for _, resourceType := range privilege.ResourceTypes {
+				for _, conditionKey := range resourceType.ConditionKeys {
+					key := strings.ToLower(conditionKey)
+					actions[key] = append(actions[key], action)
+				}
+			}
+		}
+
+		for _, condition := range service.Conditions {
+			d.StreamListItem(ctx, awsIamConditionKeyData{
+				Actions:      uniqueStrings(actions[strings.ToLower(condition.Condition)]),
+				ConditionKey: condition.Condition,
+				Description:  condition.Description,
+				Prefix:       service.Prefix,
+				ServiceName:  service.ServiceName,
+				Type:         condition.Type,
+			})
+		}
+	}
+	return nil, nil
+}
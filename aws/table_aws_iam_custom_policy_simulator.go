@@ -0,0 +1,96 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+func tableAwsIamCustomPolicySimulator(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_iam_custom_policy_simulator",
+		Description: "AWS IAM Custom Policy Simulator",
+		List: &plugin.ListConfig{
+			// action and resource_arn are also required, but may be lists of
+			// values, which key columns only support for a single column
+			KeyColumns: plugin.SingleColumn("policy"),
+			Hydrate:    listIamCustomPolicySimulation,
+		},
+		Columns: append([]*plugin.Column{
+			// "Key" Columns
+			{
+				Name:        "policy",
+				Description: "The identity-based policy document to simulate, as JSON text.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "permissions_boundary",
+				Description: "The permissions boundary policy document to apply, as JSON text.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "resource_policy",
+				Description: "The resource-based policy document to include in the simulation, as JSON text.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromGo(),
+			},
+		}, iamPolicySimulationColumns...),
+	}
+}
+
+func listIamCustomPolicySimulation(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("listIamCustomPolicySimulation")
+	policy := d.KeyColumnQuals["policy"].GetStringValue()
+
+	input, err := getIamPolicySimulationInput(d)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create Session
+	svc, err := IAMService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &iam.SimulateCustomPolicyInput{
+		PolicyInputList: []*string{aws.String(policy)},
+		ActionNames:     aws.StringSlice(input.Actions),
+		ResourceArns:    aws.StringSlice(input.Resources),
+		CallerArn:       input.CallerArn,
+		ContextEntries:  input.Entries,
+	}
+
+	var permissionsBoundary, resourcePolicy *string
+	if boundary := getOptionalStringQual(d, "permissions_boundary"); boundary != "" {
+		permissionsBoundary = aws.String(boundary)
+		params.PermissionsBoundaryPolicyInputList = []*string{permissionsBoundary}
+	}
+	if resource := getOptionalStringQual(d, "resource_policy"); resource != "" {
+		resourcePolicy = aws.String(resource)
+		params.ResourcePolicy = resourcePolicy
+	}
+
+	err = svc.SimulateCustomPolicyPages(
+		params,
+		func(page *iam.SimulatePolicyResponse, isLast bool) bool {
+			for _, result := range page.EvaluationResults {
+				for _, row := range input.rows(result) {
+					row.Policy = policy
+					row.PermissionsBoundary = permissionsBoundary
+					row.ResourcePolicy = resourcePolicy
+					d.StreamListItem(ctx, row)
+				}
+			}
+			return !isLast
+		},
+	)
+
+	return nil, err
+}
@@ -0,0 +1,222 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableAwsIamPolicyAction(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_iam_policy_action",
+		Description: "AWS IAM Policy Action",
+		List: &plugin.ListConfig{
+			KeyColumns: plugin.SingleColumn("policy"),
+			Hydrate:    listIamPolicyActions,
+		},
+		Columns: []*plugin.Column{
+			// "Key" Columns
+			{
+				Name:        "policy",
+				Description: "The IAM policy document to expand, as JSON text.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "statement_index",
+				Description: "The position of the statement in the policy, starting at 0.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "sid",
+				Description: "The statement ID, if any.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "effect",
+				Description: "The effect of the statement (Allow | Deny).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "is_not_action",
+				Description: "True if the action is granted or denied through NotAction, i.e. it is not matched by any of the NotAction patterns of the statement.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "action_pattern",
+				Description: "The Action pattern of the statement which matched the action, e.g. s3:get*. Null for NotAction statements.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "action",
+				Description: "The concrete action, in lower case.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "is_known_action",
+				Description: "False if the action pattern does not match any action in the IAM action catalog, in which case action holds the pattern as written.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "prefix",
+				Description: "The service prefix of the action.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "privilege",
+				Description: "The privilege of the action.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "access_level",
+				Description: "The access level of the action (List | Read | Write | Permissions management | Tagging).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "description",
+				Description: "The description of the action.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromGo(),
+			},
+		},
+	}
+}
+
+type awsIamPolicyActionData struct {
+	AccessLevel    string
+	Action         string
+	ActionPattern  *string
+	Description    string
+	Effect         string
+	IsKnownAction  bool
+	IsNotAction    bool
+	Policy         string
+	Prefix         string
+	Privilege      string
+	Sid            string
+	StatementIndex int
+}
+
+//// LIST FUNCTION
+
+func listIamPolicyActions(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("listIamPolicyActions")
+	policyDocument := d.KeyColumnQuals["policy"].GetStringValue()
+
+	var policy Policy
+	if err := json.Unmarshal([]byte(policyDocument), &policy); err != nil {
+		return nil, fmt.Errorf("invalid policy document: %s", err)
+	}
+
+	permissions, err := getIamPermissions(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, statement := range policy.Statements {
+		row := awsIamPolicyActionData{
+			Effect:         statement.Effect,
+			Policy:         policyDocument,
+			Sid:            statement.Sid,
+			StatementIndex: i,
+		}
+
+		if len(statement.NotAction) > 0 {
+			row.IsNotAction = true
+			for _, permission := range expandIamNotActions(permissions, statement.NotAction) {
+				d.StreamListItem(ctx, row.withPermission(permission))
+			}
+			continue
+		}
+
+		for _, pattern := range statement.Action {
+			pattern := pattern
+			row.ActionPattern = &pattern
+
+			matched := expandIamActions(permissions, []string{pattern})
+			if len(matched) == 0 {
+				// Keep actions missing from the catalog visible
+				unknown := row
+				unknown.Action = pattern
+				if parts := strings.SplitN(pattern, ":", 2); len(parts) == 2 {
+					unknown.Prefix = parts[0]
+				}
+				d.StreamListItem(ctx, unknown)
+				continue
+			}
+			for _, permission := range matched {
+				d.StreamListItem(ctx, row.withPermission(permission))
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+//// UTILITY FUNCTIONS
+
+func (row awsIamPolicyActionData) withPermission(permission awsIamPermissionData) awsIamPolicyActionData {
+	row.AccessLevel = permission.AccessLevel
+	row.Action = permission.Action
+	row.Description = permission.Description
+	row.IsKnownAction = true
+	row.Prefix = permission.Prefix
+	row.Privilege = permission.Privilege
+	return row
+}
+
+// expandIamActions returns the actions in the IAM action catalog which match
+// any of the given action patterns, e.g. s3:get*
+func expandIamActions(permissions ParliamentPermissions, patterns []string) []awsIamPermissionData {
+	return filterIamActions(permissions, func(action string) bool {
+		for _, pattern := range patterns {
+			if wildcardMatch(strings.ToLower(pattern), action) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// expandIamNotActions returns the actions in the IAM action catalog which are
+// not matched by any of the given NotAction patterns
+func expandIamNotActions(permissions ParliamentPermissions, patterns []string) []awsIamPermissionData {
+	return filterIamActions(permissions, func(action string) bool {
+		for _, pattern := range patterns {
+			if wildcardMatch(strings.ToLower(pattern), action) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+func filterIamActions(permissions ParliamentPermissions, include func(action string) bool) []awsIamPermissionData {
+	var matched []awsIamPermissionData
+	for _, service := range permissions {
+		for _, privilege := range service.Privileges {
+			action := strings.ToLower(service.Prefix + ":" + privilege.Privilege)
+			if include(action) {
+				matched = append(matched, newIamPermissionData(service, privilege))
+			}
+		}
+	}
+	return matched
+}
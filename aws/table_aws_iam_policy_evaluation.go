@@ -0,0 +1,137 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+func tableAwsIamPolicyEvaluation(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_iam_policy_evaluation",
+		Description: "AWS IAM Policy Evaluation",
+		List: &plugin.ListConfig{
+			KeyColumns: plugin.AllColumns([]string{"policy", "action", "resource"}),
+			Hydrate:    listIamPolicyEvaluation,
+		},
+		Columns: []*plugin.Column{
+			// "Key" Columns
+			{
+				Name:        "policy",
+				Description: "The IAM policy document to evaluate, as JSON text.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "action",
+				Description: "The action for this policy evaluation, e.g. s3:GetObject.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "resource",
+				Description: "The resource ARN for this policy evaluation.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "principal_arn",
+				Description: "The principal making the request. Only used to match the Principal and NotPrincipal elements of resource-based policies.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "context",
+				Description: "The request context as a JSON object of condition keys to values, e.g. {\"aws:username\": \"bob\", \"aws:SourceIp\": \"10.0.0.1\"}.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "decision",
+				Description: "The result of the evaluation (allowed | explicitDeny | implicitDeny).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "allowed",
+				Description: "True if the policy allows the request.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "matched_statements",
+				Description: "The statements of the policy which apply to the request, in canonical form.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "policy_std",
+				Description: "Contains the policy in a canonical form for easier searching.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromGo(),
+			},
+		},
+	}
+}
+
+type awsIamPolicyEvaluationResult struct {
+	Action            string
+	Allowed           bool
+	Context           *string
+	Decision          string
+	MatchedStatements []Statement
+	Policy            string
+	PolicyStd         Policy
+	PrincipalArn      *string
+	Resource          string
+}
+
+func listIamPolicyEvaluation(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("listIamPolicyEvaluation")
+	policyDocument := d.KeyColumnQuals["policy"].GetStringValue()
+	action := d.KeyColumnQuals["action"].GetStringValue()
+	resource := d.KeyColumnQuals["resource"].GetStringValue()
+
+	row := awsIamPolicyEvaluationResult{
+		Action:   action,
+		Policy:   policyDocument,
+		Resource: resource,
+	}
+
+	// The principal and request context are optional
+	principal := getOptionalStringQual(d, "principal_arn")
+	if principal != "" {
+		row.PrincipalArn = &principal
+	}
+	requestContext := getOptionalStringQual(d, "context")
+	if requestContext != "" {
+		row.Context = &requestContext
+	}
+
+	var policy Policy
+	if err := json.Unmarshal([]byte(policyDocument), &policy); err != nil {
+		return nil, fmt.Errorf("invalid policy document: %s", err)
+	}
+	row.PolicyStd = policy
+
+	request, err := newPolicyEvaluationRequest(action, resource, principal, requestContext)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := evaluatePolicies([]Policy{policy}, request)
+	if err != nil {
+		return nil, err
+	}
+	row.Decision = result.Decision
+	row.Allowed = result.Decision == policyDecisionAllowed
+	row.MatchedStatements = result.MatchedStatements
+
+	d.StreamListItem(ctx, row)
+
+	return nil, nil
+}
@@ -0,0 +1,303 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+// Rules checked by the policy linter
+const (
+	policyRuleInvalidDocument       = "invalid_document"
+	policyRuleFullAdmin             = "full_admin"
+	policyRuleAllowNotAction        = "allow_not_action"
+	policyRuleWildcardResourceWrite = "wildcard_resource_write"
+	policyRulePrivilegeEscalation   = "privilege_escalation"
+	policyRulePublicPrincipal       = "public_principal"
+	policyRuleUnknownAction         = "unknown_action"
+)
+
+// iamPrivilegeEscalationActions are actions which let a principal grant itself,
+// or a principal it controls, more permissions
+var iamPrivilegeEscalationActions = []string{
+	"iam:addusertogroup",
+	"iam:attachgrouppolicy",
+	"iam:attachrolepolicy",
+	"iam:attachuserpolicy",
+	"iam:createaccesskey",
+	"iam:createloginprofile",
+	"iam:createpolicyversion",
+	"iam:passrole",
+	"iam:putgrouppolicy",
+	"iam:putrolepolicy",
+	"iam:putuserpolicy",
+	"iam:setdefaultpolicyversion",
+	"iam:updateassumerolepolicy",
+	"iam:updateloginprofile",
+}
+
+//// TABLE DEFINITION
+
+func tableAwsIamPolicyFinding(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_iam_policy_finding",
+		Description: "AWS IAM Policy Finding",
+		List: &plugin.ListConfig{
+			Hydrate: listIamPolicyFindings,
+		},
+		Columns: awsMultiRegionColumns([]*plugin.Column{
+			{
+				Name:        "rule",
+				Description: "The rule which raised the finding (full_admin | allow_not_action | wildcard_resource_write | privilege_escalation | public_principal | unknown_action | invalid_document).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "severity",
+				Description: "The severity of the finding (critical | high | medium | low).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "description",
+				Description: "A description of the finding.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "source_type",
+				Description: "The kind of policy the finding is in (managed_policy | user_inline_policy | group_inline_policy | role_inline_policy | role_trust_policy | s3_bucket_policy | kms_key_policy | lambda_function_policy | sns_topic_policy | sqs_queue_policy).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Source.SourceType"),
+			},
+			{
+				Name:        "source_arn",
+				Description: "The ARN of the managed policy, or of the user, group, role or resource the policy is attached to.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Source.SourceArn"),
+			},
+			{
+				Name:        "source_name",
+				Description: "The name of the managed or inline policy, or of the resource for resource-based policies.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Source.SourceName"),
+			},
+			{
+				Name:        "sid",
+				Description: "The ID of the statement with the finding, if any.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Statement.Sid"),
+			},
+			{
+				Name:        "statement_index",
+				Description: "The position of the statement in the policy, starting at 0. Null for invalid policy documents.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "actions",
+				Description: "The actions which caused the finding, for the wildcard_resource_write, privilege_escalation and unknown_action rules.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "statement",
+				Description: "The statement with the finding, in canonical form.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(policyFindingTitle),
+			},
+		}, "Source.Region"),
+	}
+}
+
+type policyFinding struct {
+	Rule           string
+	Severity       string
+	Description    string
+	StatementIndex *int
+	Statement      *Statement
+	Actions        []string
+	Source         *policySourceInfo
+}
+
+//// LIST FUNCTION
+
+func listIamPolicyFindings(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("listIamPolicyFindings")
+
+	permissions, err := getIamPermissions(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only collect the kind of policy requested in the where clause. VPC endpoint
+	// policies are skipped, as the default endpoint policy allows everyone.
+	sourceType := getOptionalStringQual(d, "source_type")
+	include := func(t string) bool {
+		return t != policySourceVpcEndpointPolicy && (sourceType == "" || sourceType == t)
+	}
+
+	lint := func(source *policySourceInfo) {
+		if !include(source.SourceType) {
+			return
+		}
+		for _, finding := range lintPolicySource(source, permissions) {
+			d.StreamListItem(ctx, finding)
+		}
+	}
+
+	// IAM policies are global, and resource policies are listed from each
+	// queried region
+	if isGlobalQueried(d) {
+		if err := listIamPolicySources(ctx, d, lint); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, region := range getQueriedRegions(ctx, d) {
+		if err := listResourcePolicySources(ctx, d, region, include, lint); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+//// UTILITY FUNCTIONS
+
+// lintPolicySource checks each statement of a policy against the lint rules
+func lintPolicySource(source *policySourceInfo, permissions ParliamentPermissions) []*policyFinding {
+	var policy Policy
+	if err := json.Unmarshal([]byte(source.Policy), &policy); err != nil {
+		return []*policyFinding{{
+			Rule:        policyRuleInvalidDocument,
+			Severity:    "medium",
+			Description: "The policy document could not be parsed: " + err.Error(),
+			Source:      source,
+		}}
+	}
+
+	var findings []*policyFinding
+	for i := range policy.Statements {
+		index := i
+		for _, finding := range lintStatement(&policy.Statements[i], source.isResourcePolicy(), permissions) {
+			finding.StatementIndex = &index
+			finding.Source = source
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// lintStatement returns the findings for a single statement. Identity policies
+// and resource-based policies are checked by different rules.
+func lintStatement(statement *Statement, isResourcePolicy bool, permissions ParliamentPermissions) []*policyFinding {
+	var findings []*policyFinding
+	add := func(rule string, severity string, description string, actions []string) {
+		findings = append(findings, &policyFinding{
+			Rule:        rule,
+			Severity:    severity,
+			Description: description,
+			Statement:   statement,
+			Actions:     actions,
+		})
+	}
+
+	// Actions which are not in the catalog are usually typos, and grant nothing
+	var unknown []string
+	for _, pattern := range append(append([]string{}, statement.Action...), statement.NotAction...) {
+		if pattern == "*" || pattern == "*:*" || len(permissions) == 0 {
+			continue
+		}
+		if len(expandIamActions(permissions, []string{pattern})) == 0 {
+			unknown = append(unknown, pattern)
+		}
+	}
+	if len(unknown) > 0 {
+		add(policyRuleUnknownAction, "low", "The statement names actions which are not in the IAM action catalog.", unknown)
+	}
+
+	if statement.Effect != "Allow" {
+		return findings
+	}
+
+	if len(statement.NotAction) > 0 {
+		add(policyRuleAllowNotAction, "high", "The statement allows every action except those listed in NotAction, including actions added to AWS in the future.", nil)
+	}
+
+	if isResourcePolicy {
+		if hasPublicPrincipal(statement.Principal) && len(statement.Condition) == 0 {
+			add(policyRulePublicPrincipal, "critical", "The statement allows any principal, with no conditions to restrict access.", nil)
+		}
+		return findings
+	}
+
+	wildcardResource := false
+	for _, resource := range statement.Resource {
+		if resource == "*" {
+			wildcardResource = true
+		}
+	}
+
+	isAdmin := false
+	for _, action := range statement.Action {
+		if action == "*" || action == "*:*" {
+			isAdmin = true
+		}
+	}
+	if isAdmin && wildcardResource {
+		add(policyRuleFullAdmin, "critical", "The statement allows all actions on all resources.", nil)
+		return findings
+	}
+
+	var escalation []string
+	for _, action := range iamPrivilegeEscalationActions {
+		if statementActionMatches(*statement, action) {
+			escalation = append(escalation, action)
+		}
+	}
+	if len(escalation) > 0 {
+		add(policyRulePrivilegeEscalation, "high", "The statement allows actions which can be used to escalate privileges.", escalation)
+	}
+
+	if wildcardResource && len(statement.Action) > 0 {
+		var write []string
+		for _, permission := range expandIamActions(permissions, statement.Action) {
+			if permission.AccessLevel == "Write" || permission.AccessLevel == "Permissions management" {
+				write = append(write, permission.Action)
+			}
+		}
+		if len(write) > 0 {
+			add(policyRuleWildcardResourceWrite, "medium", "The statement allows write or permissions management actions on all resources.", write)
+		}
+	}
+
+	return findings
+}
+
+// hasPublicPrincipal returns true if the principal includes everyone
+func hasPublicPrincipal(principal Principal) bool {
+	for _, values := range principal {
+		ids, ok := values.([]string)
+		if !ok {
+			continue
+		}
+		for _, id := range ids {
+			if id == "*" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//// TRANSFORM FUNCTIONS
+
+func policyFindingTitle(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	finding := d.HydrateItem.(*policyFinding)
+	return strings.Join([]string{finding.Source.SourceName, finding.Rule}, " - "), nil
+}
@@ -0,0 +1,117 @@
+package aws
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+var lintTestPermissions = ParliamentPermissions{
+	{
+		Prefix: "iam",
+		Privileges: []ParliamentPrivilege{
+			{Privilege: "GetUser", AccessLevel: "Read"},
+			{Privilege: "PassRole", AccessLevel: "Write"},
+			{Privilege: "PutUserPolicy", AccessLevel: "Permissions management"},
+		},
+	},
+	{
+		Prefix: "s3",
+		Privileges: []ParliamentPrivilege{
+			{Privilege: "GetObject", AccessLevel: "Read"},
+			{Privilege: "PutObject", AccessLevel: "Write"},
+		},
+	},
+}
+
+func TestLintStatement(t *testing.T) {
+	type finding struct {
+		Rule    string
+		Actions []string
+	}
+
+	type testCase struct {
+		name             string
+		statement        string
+		isResourcePolicy bool
+		expected         []finding
+	}
+
+	cases := []testCase{
+		{
+			name:      "read only",
+			statement: `{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}`,
+			expected:  []finding{},
+		},
+		{
+			name:      "unknown action",
+			statement: `{"Effect": "Allow", "Action": ["s3:GetObjekt", "s3:GetObject"], "Resource": "arn:aws:s3:::bucket/*"}`,
+			expected:  []finding{{policyRuleUnknownAction, []string{"s3:getobjekt"}}},
+		},
+		{
+			name:      "unknown action in a deny",
+			statement: `{"Effect": "Deny", "Action": "s3:PutObjekt", "Resource": "*"}`,
+			expected:  []finding{{policyRuleUnknownAction, []string{"s3:putobjekt"}}},
+		},
+		{
+			name:      "deny all",
+			statement: `{"Effect": "Deny", "Action": "*", "Resource": "*"}`,
+			expected:  []finding{},
+		},
+		{
+			name:      "allow not action",
+			statement: `{"Effect": "Allow", "NotAction": "iam:*", "Resource": "arn:aws:s3:::bucket/*"}`,
+			expected:  []finding{{policyRuleAllowNotAction, nil}},
+		},
+		{
+			name:      "full admin",
+			statement: `{"Effect": "Allow", "Action": "*", "Resource": "*"}`,
+			expected:  []finding{{policyRuleFullAdmin, nil}},
+		},
+		{
+			name:      "all actions on one resource",
+			statement: `{"Effect": "Allow", "Action": "*", "Resource": "arn:aws:s3:::bucket/*"}`,
+			expected:  []finding{{policyRulePrivilegeEscalation, iamPrivilegeEscalationActions}},
+		},
+		{
+			name:      "privilege escalation and wildcard resource write",
+			statement: `{"Effect": "Allow", "Action": ["iam:PassRole", "iam:GetUser"], "Resource": "*"}`,
+			expected: []finding{
+				{policyRulePrivilegeEscalation, []string{"iam:passrole"}},
+				{policyRuleWildcardResourceWrite, []string{"iam:passrole"}},
+			},
+		},
+		{
+			name:      "wildcard resource write",
+			statement: `{"Effect": "Allow", "Action": "s3:*", "Resource": "*"}`,
+			expected:  []finding{{policyRuleWildcardResourceWrite, []string{"s3:putobject"}}},
+		},
+		{
+			name:             "public principal",
+			statement:        `{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/*"}`,
+			isResourcePolicy: true,
+			expected:         []finding{{policyRulePublicPrincipal, nil}},
+		},
+		{
+			name:             "public principal with a condition",
+			statement:        `{"Effect": "Allow", "Principal": {"AWS": "*"}, "Action": "s3:*", "Resource": "*", "Condition": {"StringEquals": {"aws:PrincipalOrgID": "o-123456"}}}`,
+			isResourcePolicy: true,
+			expected:         []finding{},
+		},
+	}
+
+	for _, c := range cases {
+		var policy Policy
+		if err := json.Unmarshal([]byte(`{"Version": "2012-10-17", "Statement": `+c.statement+`}`), &policy); err != nil {
+			t.Errorf("Case '%s': unmarshal failed: %v", c.name, err)
+			continue
+		}
+		result := []finding{}
+		for _, f := range lintStatement(&policy.Statements[0], c.isResourcePolicy, lintTestPermissions) {
+			result = append(result, finding{f.Rule, f.Actions})
+		}
+		if !reflect.DeepEqual(result, c.expected) {
+			t.Errorf("Case '%s': expected %v, got %v", c.name, c.expected, result)
+		}
+	}
+}
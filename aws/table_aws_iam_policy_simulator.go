@@ -2,7 +2,11 @@ package aws
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
 
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/iam"
 	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/plugin"
@@ -14,10 +18,12 @@ func tableAwsIamPolicySimulator(_ context.Context) *plugin.Table {
 		Name:        "aws_iam_policy_simulator",
 		Description: "AWS IAM Policy Simulator",
 		List: &plugin.ListConfig{
-			KeyColumns: plugin.AllColumns([]string{"principal_arn", "action", "resource_arn"}),
+			// action and resource_arn are also required, but may be lists of
+			// values, which key columns only support for a single column
+			KeyColumns: plugin.SingleColumn("principal_arn"),
 			Hydrate:    listIamPolicySimulation,
 		},
-		Columns: []*plugin.Column{
+		Columns: append([]*plugin.Column{
 			// "Key" Columns
 			{
 				Name:        "principal_arn",
@@ -25,83 +31,117 @@ func tableAwsIamPolicySimulator(_ context.Context) *plugin.Table {
 				Type:        proto.ColumnType_STRING,
 				Transform:   transform.FromGo(),
 			},
-			{
-				Name:        "action",
-				Description: "The action for this policy simulation.",
-				Type:        proto.ColumnType_STRING,
-				Transform:   transform.FromGo(),
-			},
-			{
-				Name:        "resource_arn",
-				Type:        proto.ColumnType_STRING,
-				Description: "The resource for this policy simulation.",
-				Transform:   transform.FromGo(),
-			},
-			{
-				Name:        "decision",
-				Type:        proto.ColumnType_STRING,
-				Description: "The decision for this policy simulation.",
-				Transform:   transform.FromGo(),
-			},
-			{
-				Name:        "decision_details",
-				Type:        proto.ColumnType_JSON,
-				Description: "The decision details for this policy simulation.",
-				Transform:   transform.FromGo(),
-			},
-			{
-				Name:        "matched_statements",
-				Type:        proto.ColumnType_JSON,
-				Description: "The matched statements for this policy simulation.",
-				Transform:   transform.FromGo(),
-			},
-			{
-				Name:        "missing_context_values",
-				Type:        proto.ColumnType_JSON,
-				Description: "The missing content values for this policy simulation.",
-				Transform:   transform.FromGo(),
-			},
-			{
-				Name:        "resource_specific_results",
-				Type:        proto.ColumnType_JSON,
-				Description: "The resource specific results for this policy simulation.",
-				Transform:   transform.FromGo(),
-			},
-			{
-				Name:        "organizations_decision_detail",
-				Type:        proto.ColumnType_JSON,
-				Description: "The organizations decision detail for this policy simulation.",
-				Transform:   transform.FromGo(),
-			},
-			{
-				Name:        "permissions_boundary_decision_detail",
-				Type:        proto.ColumnType_JSON,
-				Description: "The permissions boundary decision detail for this policy simulation.",
-				Transform:   transform.FromGo(),
-			},
-		},
+		}, iamPolicySimulationColumns...),
 	}
 }
 
+// iamPolicySimulationColumns are the input and result columns shared by the
+// principal and custom policy simulator tables
+var iamPolicySimulationColumns = []*plugin.Column{
+	{
+		Name:        "action",
+		Description: "The action for this policy simulation. Several actions can be simulated at once with IN (...).",
+		Type:        proto.ColumnType_STRING,
+		Transform:   transform.FromGo(),
+	},
+	{
+		Name:        "resource_arn",
+		Type:        proto.ColumnType_STRING,
+		Description: "The resource for this policy simulation. Several resources can be simulated at once with IN (...).",
+		Transform:   transform.FromGo(),
+	},
+	{
+		Name:        "context_entries",
+		Type:        proto.ColumnType_STRING,
+		Description: "The context keys for this policy simulation, as a JSON object of key to value, e.g. {\"aws:MultiFactorAuthPresent\": true}, or a JSON array of objects with ContextKeyName, ContextKeyValues and ContextKeyType.",
+		Transform:   transform.FromGo(),
+	},
+	{
+		Name:        "caller_arn",
+		Type:        proto.ColumnType_STRING,
+		Description: "The ARN of the IAM user to use as the caller of the simulated requests, for policies which use the caller's name or ID.",
+		Transform:   transform.FromGo(),
+	},
+	{
+		Name:        "decision",
+		Type:        proto.ColumnType_STRING,
+		Description: "The decision for this policy simulation.",
+		Transform:   transform.FromGo(),
+	},
+	{
+		Name:        "decision_details",
+		Type:        proto.ColumnType_JSON,
+		Description: "The decision details for this policy simulation.",
+		Transform:   transform.FromGo(),
+	},
+	{
+		Name:        "matched_statements",
+		Type:        proto.ColumnType_JSON,
+		Description: "The matched statements for this policy simulation.",
+		Transform:   transform.FromGo(),
+	},
+	{
+		Name:        "missing_context_values",
+		Type:        proto.ColumnType_JSON,
+		Description: "The missing content values for this policy simulation.",
+		Transform:   transform.FromGo(),
+	},
+	{
+		Name:        "resource_specific_results",
+		Type:        proto.ColumnType_JSON,
+		Description: "The resource specific results for this policy simulation.",
+		Transform:   transform.FromGo(),
+	},
+	{
+		Name:        "organizations_decision_detail",
+		Type:        proto.ColumnType_JSON,
+		Description: "The organizations decision detail for this policy simulation.",
+		Transform:   transform.FromGo(),
+	},
+	{
+		Name:        "permissions_boundary_decision_detail",
+		Type:        proto.ColumnType_JSON,
+		Description: "The permissions boundary decision detail for this policy simulation.",
+		Transform:   transform.FromGo(),
+	},
+}
+
 type awsIamPolicySimulatorResult struct {
 	Action                            string
+	CallerArn                         *string
+	ContextEntries                    *string
 	Decision                          *string
 	DecisionDetails                   map[string]*string
 	MatchedStatements                 []*iam.Statement
 	MissingContextValues              []*string
 	OrganizationsDecisionDetail       *iam.OrganizationsDecisionDetail
+	PermissionsBoundary               *string
 	PermissionsBoundaryDecisionDetail *iam.PermissionsBoundaryDecisionDetail
+	Policy                            string
 	PrincipalArn                      string
 	ResourceArn                       string
+	ResourcePolicy                    *string
 	ResourceSpecificResults           []*iam.ResourceSpecificResult
 	Result                            *iam.EvaluationResult
 }
 
+// iamPolicySimulationInput holds the quals shared by both simulator tables
+type iamPolicySimulationInput struct {
+	Actions        []string
+	Resources      []string
+	CallerArn      *string
+	ContextEntries *string
+	Entries        []*iam.ContextEntry
+}
+
 func listIamPolicySimulation(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
 	plugin.Logger(ctx).Trace("listIamPolicySimulation")
 	principalArn := d.KeyColumnQuals["principal_arn"].GetStringValue()
-	action := d.KeyColumnQuals["action"].GetStringValue()
-	resourceArn := d.KeyColumnQuals["resource_arn"].GetStringValue()
+
+	input, err := getIamPolicySimulationInput(d)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create Session
 	svc, err := IAMService(ctx, d)
@@ -109,34 +149,159 @@ func listIamPolicySimulation(ctx context.Context, d *plugin.QueryData, _ *plugin
 		return nil, err
 	}
 
-	var params = &iam.SimulatePrincipalPolicyInput{
-		PolicySourceArn: &principalArn,
-		ActionNames:     []*string{&action},
-		ResourceArns:    []*string{&resourceArn},
+	params := &iam.SimulatePrincipalPolicyInput{
+		PolicySourceArn: aws.String(principalArn),
+		ActionNames:     aws.StringSlice(input.Actions),
+		ResourceArns:    aws.StringSlice(input.Resources),
+		CallerArn:       input.CallerArn,
+		ContextEntries:  input.Entries,
 	}
-	op, err := svc.SimulatePrincipalPolicy(params)
-	if err != nil {
-		return nil, err
+
+	err = svc.SimulatePrincipalPolicyPages(
+		params,
+		func(page *iam.SimulatePolicyResponse, isLast bool) bool {
+			for _, result := range page.EvaluationResults {
+				for _, row := range input.rows(result) {
+					row.PrincipalArn = principalArn
+					d.StreamListItem(ctx, row)
+				}
+			}
+			return !isLast
+		},
+	)
+
+	return nil, err
+}
+
+//// UTILITY FUNCTIONS
+
+// getIamPolicySimulationInput reads the actions, resources and request context
+// to simulate from the quals
+func getIamPolicySimulationInput(d *plugin.QueryData) (*iamPolicySimulationInput, error) {
+	input := &iamPolicySimulationInput{
+		Actions:   getStringQualValues(d, "action"),
+		Resources: getStringQualValues(d, "resource_arn"),
+	}
+	if len(input.Actions) == 0 || len(input.Resources) == 0 {
+		return nil, fmt.Errorf("policy simulation requires an '=' or 'in' qual for action and resource_arn")
 	}
 
-	evalResults := op.EvaluationResults
-	resultForAction := evalResults[0]
+	if callerArn := getOptionalStringQual(d, "caller_arn"); callerArn != "" {
+		input.CallerArn = aws.String(callerArn)
+	}
+	if contextEntries := getOptionalStringQual(d, "context_entries"); contextEntries != "" {
+		entries, err := parseSimulationContextEntries(contextEntries)
+		if err != nil {
+			return nil, err
+		}
+		input.ContextEntries = aws.String(contextEntries)
+		input.Entries = entries
+	}
+
+	return input, nil
+}
+
+// rows returns a row for each action and resource in an evaluation result. The
+// action and resource are given as they were requested, so postgres can match
+// them against the quals.
+func (input *iamPolicySimulationInput) rows(result *iam.EvaluationResult) []awsIamPolicySimulatorResult {
+	action := aws.StringValue(result.EvalActionName)
+	for _, requested := range input.Actions {
+		if strings.EqualFold(requested, action) {
+			action = requested
+		}
+	}
+	resourceArn := func(name *string) string {
+		if len(input.Resources) == 1 {
+			return input.Resources[0]
+		}
+		return aws.StringValue(name)
+	}
 
 	row := awsIamPolicySimulatorResult{
 		Action:                            action,
-		Decision:                          resultForAction.EvalDecision,
-		DecisionDetails:                   resultForAction.EvalDecisionDetails,
-		MatchedStatements:                 resultForAction.MatchedStatements,
-		MissingContextValues:              resultForAction.MissingContextValues,
-		OrganizationsDecisionDetail:       resultForAction.OrganizationsDecisionDetail,
-		PermissionsBoundaryDecisionDetail: resultForAction.PermissionsBoundaryDecisionDetail,
-		PrincipalArn:                      principalArn,
-		ResourceArn:                       resourceArn,
-		ResourceSpecificResults:           resultForAction.ResourceSpecificResults,
-		Result:                            resultForAction,
+		CallerArn:                         input.CallerArn,
+		ContextEntries:                    input.ContextEntries,
+		Decision:                          result.EvalDecision,
+		DecisionDetails:                   result.EvalDecisionDetails,
+		MatchedStatements:                 result.MatchedStatements,
+		MissingContextValues:              result.MissingContextValues,
+		OrganizationsDecisionDetail:       result.OrganizationsDecisionDetail,
+		PermissionsBoundaryDecisionDetail: result.PermissionsBoundaryDecisionDetail,
+		ResourceArn:                       resourceArn(result.EvalResourceName),
+		ResourceSpecificResults:           result.ResourceSpecificResults,
+		Result:                            result,
+	}
+
+	// When several resources are simulated, each has its own decision
+	if len(input.Resources) == 1 || len(result.ResourceSpecificResults) == 0 {
+		return []awsIamPolicySimulatorResult{row}
+	}
+
+	var rows []awsIamPolicySimulatorResult
+	for _, resourceResult := range result.ResourceSpecificResults {
+		resourceRow := row
+		resourceRow.Decision = resourceResult.EvalResourceDecision
+		resourceRow.DecisionDetails = resourceResult.EvalDecisionDetails
+		resourceRow.MatchedStatements = resourceResult.MatchedStatements
+		resourceRow.MissingContextValues = resourceResult.MissingContextValues
+		resourceRow.PermissionsBoundaryDecisionDetail = resourceResult.PermissionsBoundaryDecisionDetail
+		resourceRow.ResourceArn = aws.StringValue(resourceResult.EvalResourceName)
+		rows = append(rows, resourceRow)
+	}
+	return rows
+}
+
+// parseSimulationContextEntries parses context entries given either as a JSON
+// object of key to value, or as a JSON array of context entries
+func parseSimulationContextEntries(contextJSON string) ([]*iam.ContextEntry, error) {
+	if strings.HasPrefix(strings.TrimSpace(contextJSON), "[") {
+		var entries []*iam.ContextEntry
+		if err := json.Unmarshal([]byte(contextJSON), &entries); err != nil {
+			return nil, fmt.Errorf("invalid context_entries: %s", err)
+		}
+		return entries, nil
 	}
 
-	d.StreamListItem(ctx, row)
+	var raw map[string]interface{}
+	if err := json.Unmarshal([]byte(contextJSON), &raw); err != nil {
+		return nil, fmt.Errorf("context_entries must be a JSON object or array: %s", err)
+	}
+
+	var entries []*iam.ContextEntry
+	for key, value := range raw {
+		if value == nil {
+			continue
+		}
+		values, err := toSliceOfStrings(value)
+		if err != nil {
+			return nil, err
+		}
 
-	return nil, nil
+		// Infer the type from the JSON value
+		keyType := iam.ContextKeyTypeEnumString
+		switch v := value.(type) {
+		case bool:
+			keyType = iam.ContextKeyTypeEnumBoolean
+		case float64:
+			keyType = iam.ContextKeyTypeEnumNumeric
+		case []interface{}:
+			keyType = iam.ContextKeyTypeEnumStringList
+			if len(v) > 0 {
+				switch v[0].(type) {
+				case bool:
+					keyType = iam.ContextKeyTypeEnumBooleanList
+				case float64:
+					keyType = iam.ContextKeyTypeEnumNumericList
+				}
+			}
+		}
+
+		entries = append(entries, &iam.ContextEntry{
+			ContextKeyName:   aws.String(key),
+			ContextKeyType:   aws.String(keyType),
+			ContextKeyValues: aws.StringSlice(values),
+		})
+	}
+	return entries, nil
 }
@@ -0,0 +1,419 @@
+package aws
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableAwsIamPrincipalEffectivePolicy(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_iam_principal_effective_policy",
+		Description: "AWS IAM Principal Effective Policy",
+		List: &plugin.ListConfig{
+			Hydrate: listIamPrincipalEffectivePolicies,
+		},
+		Columns: awsColumns([]*plugin.Column{
+			{
+				Name:        "principal_arn",
+				Description: "The ARN of the user or role.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Principal.PrincipalArn"),
+			},
+			{
+				Name:        "principal_name",
+				Description: "The name of the user or role.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Principal.PrincipalName"),
+			},
+			{
+				Name:        "principal_type",
+				Description: "The type of the principal (user | role).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Principal.PrincipalType"),
+			},
+			{
+				Name:        "source_type",
+				Description: "Where the statement comes from (inline_policy | managed_policy | group_inline_policy | group_managed_policy | permissions_boundary | service_control_policy).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Source.SourceType"),
+			},
+			{
+				Name:        "source_arn",
+				Description: "The ARN of the managed policy or SCP, or of the user, group or role an inline policy is embedded in.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Source.SourceArn"),
+			},
+			{
+				Name:        "source_name",
+				Description: "The name of the policy.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Source.SourceName"),
+			},
+			{
+				Name:        "group_name",
+				Description: "The group the policy is inherited from, for group policies.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Source.GroupName").Transform(transform.NullIfZeroValue),
+			},
+			{
+				Name:        "target_id",
+				Description: "The ID of the root, organizational unit or account the SCP is attached to, for service control policies.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Source.TargetId").Transform(transform.NullIfZeroValue),
+			},
+			{
+				Name:        "statement_index",
+				Description: "The position of the statement in the policy, starting at 0.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "sid",
+				Description: "The statement ID, if any.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Statement.Sid").Transform(transform.NullIfZeroValue),
+			},
+			{
+				Name:        "effect",
+				Description: "The effect of the statement (Allow | Deny).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Statement.Effect"),
+			},
+			{
+				Name:        "statement",
+				Description: "The statement, in canonical form.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "effective_actions",
+				Description: "The action patterns of an identity Allow statement which are also allowed by the permissions boundary and SCPs, in lower case. A NotAction statement allows *, less effective_not_actions. Null for Deny statements, boundaries and SCPs.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "effective_not_actions",
+				Description: "The action patterns which are excluded from effective_actions, from the NotAction of the statement, or because the permissions boundary or SCPs deny them or leave them out with NotAction.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "is_restricted_by_boundary",
+				Description: "True if the permissions boundary removes some of the actions of an identity Allow statement. Null if the principal has no permissions boundary.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "is_restricted_by_scp",
+				Description: "True if service control policies remove some of the actions of an identity Allow statement. Null if SCPs do not apply to the account or cannot be read.",
+				Type:        proto.ColumnType_BOOL,
+			},
+
+			// Standard columns
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(iamPrincipalEffectivePolicyTitle),
+			},
+		}),
+	}
+}
+
+type iamPrincipalEffectiveStatement struct {
+	Principal              *iamPrincipalPolicies
+	Source                 *iamPrincipalPolicy
+	StatementIndex         int
+	Statement              Statement
+	EffectiveActions       []string
+	EffectiveNotActions    []string
+	IsRestrictedByBoundary *bool
+	IsRestrictedByScp      *bool
+}
+
+//// LIST FUNCTION
+
+func listIamPrincipalEffectivePolicies(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("listIamPrincipalEffectivePolicies")
+
+	principals, err := listIamPrincipalPolicies(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	scpLevels, scpsApply, err := getServiceControlPolicies(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	principalArn := getOptionalStringQual(d, "principal_arn")
+	principalType := getOptionalStringQual(d, "principal_type")
+	sourceType := getOptionalStringQual(d, "source_type")
+
+	for _, principal := range principals {
+		if (principalArn != "" && principalArn != principal.PrincipalArn) || (principalType != "" && principalType != principal.PrincipalType) {
+			continue
+		}
+
+		var boundary *actionLimit
+		if principal.PermissionsBoundary != nil {
+			boundary = newActionLimit(principal.PermissionsBoundary)
+		}
+
+		// Service-linked roles are not affected by SCPs
+		var scps []*actionLimit
+		applyScps := scpsApply && !strings.HasPrefix(principal.Path, "/aws-service-role/")
+		if applyScps {
+			for _, level := range scpLevels {
+				scps = append(scps, newActionLimit(level...))
+			}
+		}
+
+		sources := append([]*iamPrincipalPolicy{}, principal.Policies...)
+		if principal.PermissionsBoundary != nil {
+			sources = append(sources, principal.PermissionsBoundary)
+		}
+		if applyScps {
+			for _, level := range scpLevels {
+				sources = append(sources, level...)
+			}
+		}
+
+		for _, source := range sources {
+			if sourceType != "" && sourceType != source.SourceType {
+				continue
+			}
+			for i, statement := range source.Policy.Statements {
+				row := &iamPrincipalEffectiveStatement{
+					Principal:      principal,
+					Source:         source,
+					StatementIndex: i,
+					Statement:      statement,
+				}
+				if statement.Effect == "Allow" && source.SourceType != principalPolicyPermissionsBoundary && source.SourceType != principalPolicyServiceControl {
+					row.EffectiveActions, row.EffectiveNotActions, row.IsRestrictedByBoundary, row.IsRestrictedByScp = limitStatementActions(statement, boundary, scps, applyScps)
+				}
+				d.StreamListItem(ctx, row)
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+//// UTILITY FUNCTIONS
+
+// actionLimit is a permissions boundary, or the SCPs attached at one level of
+// the organization. An action must be allowed by every limit to be effective.
+type actionLimit struct {
+	allows []Statement
+	// Deny statements with no conditions, on all resources
+	denies []Statement
+}
+
+func newActionLimit(policies ...*iamPrincipalPolicy) *actionLimit {
+	limit := &actionLimit{}
+	for _, policy := range policies {
+		for _, statement := range policy.Policy.Statements {
+			switch {
+			case statement.Effect == "Allow":
+				limit.allows = append(limit.allows, statement)
+			case len(statement.Condition) == 0 && len(statement.NotResource) == 0 && helpers.StringSliceContains(statement.Resource, "*"):
+				limit.denies = append(limit.denies, statement)
+			}
+		}
+	}
+	return limit
+}
+
+// actionSet is the actions matched by patterns which are not matched by
+// excluded, e.g. the actions of a NotAction statement
+type actionSet struct {
+	patterns []string
+	excluded []string
+}
+
+func (set *actionSet) String() string {
+	return strings.Join(set.patterns, ",") + " except " + strings.Join(set.excluded, ",")
+}
+
+// limitStatementActions applies the permissions boundary and SCPs to the
+// actions of an identity Allow statement. The effective actions are those
+// matched by effective and not by excluded. Conditions and resources of Allow
+// statements in the limits are not taken into account.
+func limitStatementActions(statement Statement, boundary *actionLimit, scps []*actionLimit, applyScps bool) (effective []string, excluded []string, restrictedByBoundary *bool, restrictedByScp *bool) {
+	set := &actionSet{patterns: sortedUniqueStrings(statement.Action), excluded: []string{}}
+	if len(statement.NotAction) > 0 {
+		set = &actionSet{patterns: []string{"*"}, excluded: sortedUniqueStrings(statement.NotAction)}
+	}
+
+	apply := func(limit *actionLimit) bool {
+		limited := limit.apply(set)
+		restricted := limited.String() != set.String()
+		set = limited
+		return restricted
+	}
+
+	if boundary != nil {
+		restricted := apply(boundary)
+		restrictedByBoundary = &restricted
+	}
+	if applyScps {
+		restricted := false
+		for _, limit := range scps {
+			if apply(limit) {
+				restricted = true
+			}
+		}
+		restrictedByScp = &restricted
+	}
+
+	return set.patterns, set.excluded, restrictedByBoundary, restrictedByScp
+}
+
+// apply returns the part of the action set which the limit allows. If a pattern
+// is only partly allowed, e.g. s3:* limited to s3:get*, the narrower patterns of
+// the limit are returned. Actions which the limit partly denies, or leaves out
+// with NotAction, are added to the exclusions, which apply to every pattern.
+func (limit *actionLimit) apply(set *actionSet) *actionSet {
+	excluded := append([]string{}, set.excluded...)
+	exclude := func(pattern string) {
+		if !matchesAnyPattern(excluded, pattern) {
+			excluded = append(excluded, pattern)
+		}
+	}
+
+	var kept []string
+	for _, pattern := range set.patterns {
+		if limit.denied(pattern) {
+			continue
+		}
+		if limit.allowed(pattern) {
+			kept = append(kept, pattern)
+			continue
+		}
+		for _, allow := range limit.allows {
+			for _, narrower := range allow.Action {
+				if wildcardMatch(pattern, narrower) && !limit.denied(narrower) && !matchesAnyPattern(set.excluded, narrower) {
+					kept = append(kept, narrower)
+				}
+			}
+			if len(allow.NotAction) > 0 && !matchesAnyPattern(allow.NotAction, pattern) {
+				// The pattern is allowed except for the narrower NotAction patterns
+				kept = append(kept, pattern)
+				for _, notAction := range allow.NotAction {
+					if wildcardMatch(pattern, notAction) {
+						exclude(notAction)
+					}
+				}
+			}
+		}
+	}
+
+	// Deny statements with NotAction deny everything else, so only the part of
+	// each pattern which is matched by their NotAction is kept, e.g. * limited
+	// by a Deny with NotAction s3:* becomes s3:*
+	for _, deny := range limit.denies {
+		if len(deny.NotAction) == 0 {
+			continue
+		}
+		var intersected []string
+		for _, pattern := range kept {
+			if matchesAnyPattern(deny.NotAction, pattern) {
+				intersected = append(intersected, pattern)
+				continue
+			}
+			for _, notAction := range deny.NotAction {
+				if wildcardMatch(pattern, notAction) {
+					intersected = append(intersected, notAction)
+				}
+			}
+		}
+		kept = intersected
+	}
+
+	// Deny statements which cover part of a pattern remove that part
+	for _, pattern := range kept {
+		for _, deny := range limit.denies {
+			for _, action := range deny.Action {
+				if action != pattern && wildcardMatch(pattern, action) {
+					exclude(action)
+				}
+			}
+		}
+	}
+
+	limited := &actionSet{patterns: []string{}, excluded: []string{}}
+	for _, pattern := range sortedUniqueStrings(kept) {
+		if !matchesAnyPattern(excluded, pattern) {
+			limited.patterns = append(limited.patterns, pattern)
+		}
+	}
+	// Only keep the exclusions which still remove actions from a pattern
+	for _, pattern := range sortedUniqueStrings(excluded) {
+		for _, kept := range limited.patterns {
+			if wildcardMatch(kept, pattern) {
+				limited.excluded = append(limited.excluded, pattern)
+				break
+			}
+		}
+	}
+	return limited
+}
+
+func (limit *actionLimit) allowed(pattern string) bool {
+	for _, allow := range limit.allows {
+		if statementCoversPattern(allow, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+func (limit *actionLimit) denied(pattern string) bool {
+	for _, deny := range limit.denies {
+		if statementCoversPattern(deny, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// statementCoversPattern returns true if every action matched by the pattern is
+// matched by the Action or NotAction of the statement
+func statementCoversPattern(statement Statement, pattern string) bool {
+	if len(statement.NotAction) > 0 {
+		for _, excluded := range statement.NotAction {
+			if wildcardMatch(excluded, pattern) || wildcardMatch(pattern, excluded) {
+				return false
+			}
+		}
+		return true
+	}
+	return matchesAnyPattern(statement.Action, pattern)
+}
+
+func matchesAnyPattern(patterns []string, value string) bool {
+	for _, pattern := range patterns {
+		if wildcardMatch(pattern, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedUniqueStrings(values []string) []string {
+	unique := uniqueStrings(values)
+	sort.Strings(unique)
+	return unique
+}
+
+//// TRANSFORM FUNCTIONS
+
+func iamPrincipalEffectivePolicyTitle(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	row := d.HydrateItem.(*iamPrincipalEffectiveStatement)
+	return strings.Join([]string{row.Principal.PrincipalName, row.Source.SourceName}, " - "), nil
+}
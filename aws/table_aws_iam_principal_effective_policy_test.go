@@ -0,0 +1,164 @@
+package aws
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestLimitStatementActions(t *testing.T) {
+	type testCase struct {
+		name       string
+		statement  string
+		boundary   string
+		scp        string
+		expected   []string
+		excluded   []string
+		restricted bool
+	}
+
+	cases := []testCase{
+		{
+			name:       "allowed by boundary",
+			statement:  `{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}`,
+			boundary:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "s3:*", "Resource": "*"}}`,
+			expected:   []string{"s3:getobject"},
+			excluded:   []string{},
+			restricted: false,
+		},
+		{
+			name:       "narrowed by boundary",
+			statement:  `{"Effect": "Allow", "Action": "s3:*", "Resource": "*"}`,
+			boundary:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": ["s3:Get*", "ec2:*"], "Resource": "*"}}`,
+			expected:   []string{"s3:get*"},
+			excluded:   []string{},
+			restricted: true,
+		},
+		{
+			name:       "denied by boundary",
+			statement:  `{"Effect": "Allow", "Action": ["iam:CreateUser", "s3:GetObject"], "Resource": "*"}`,
+			boundary:   `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "*", "Resource": "*"}, {"Effect": "Deny", "Action": "iam:*", "Resource": "*"}]}`,
+			expected:   []string{"s3:getobject"},
+			excluded:   []string{},
+			restricted: true,
+		},
+		{
+			name:       "not action limited by scp",
+			statement:  `{"Effect": "Allow", "NotAction": "iam:*", "Resource": "*"}`,
+			scp:        `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": ["iam:GetUser", "s3:*"], "Resource": "*"}}`,
+			expected:   []string{"s3:*"},
+			excluded:   []string{},
+			restricted: true,
+		},
+		{
+			name:       "not action under a boundary allowing everything",
+			statement:  `{"Effect": "Allow", "NotAction": "iam:*", "Resource": "*"}`,
+			boundary:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "*", "Resource": "*"}}`,
+			expected:   []string{"*"},
+			excluded:   []string{"iam:*"},
+			restricted: false,
+		},
+		{
+			name:       "not action with no limits",
+			statement:  `{"Effect": "Allow", "NotAction": ["iam:*", "organizations:*"], "Resource": "*"}`,
+			expected:   []string{"*"},
+			excluded:   []string{"iam:*", "organizations:*"},
+			restricted: false,
+		},
+		{
+			name:       "partly denied by boundary",
+			statement:  `{"Effect": "Allow", "Action": "*", "Resource": "*"}`,
+			boundary:   `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "*", "Resource": "*"}, {"Effect": "Deny", "Action": "iam:*", "Resource": "*"}]}`,
+			expected:   []string{"*"},
+			excluded:   []string{"iam:*"},
+			restricted: true,
+		},
+		{
+			name:       "narrowed by not action boundary",
+			statement:  `{"Effect": "Allow", "Action": ["s3:*", "iam:GetUser"], "Resource": "*"}`,
+			boundary:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "NotAction": ["s3:Delete*", "iam:*"], "Resource": "*"}}`,
+			expected:   []string{"s3:*"},
+			excluded:   []string{"s3:delete*"},
+			restricted: true,
+		},
+		{
+			name:       "allowed by not action boundary",
+			statement:  `{"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}`,
+			boundary:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "NotAction": "iam:*", "Resource": "*"}}`,
+			expected:   []string{"s3:getobject"},
+			excluded:   []string{},
+			restricted: false,
+		},
+		{
+			name:       "not action under a not action scp",
+			statement:  `{"Effect": "Allow", "NotAction": "iam:*", "Resource": "*"}`,
+			scp:        `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "NotAction": ["iam:*", "ec2:*"], "Resource": "*"}}`,
+			expected:   []string{"*"},
+			excluded:   []string{"ec2:*", "iam:*"},
+			restricted: true,
+		},
+		{
+			name:       "narrowed by not action deny in boundary",
+			statement:  `{"Effect": "Allow", "Action": "*", "Resource": "*"}`,
+			boundary:   `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "*", "Resource": "*"}, {"Effect": "Deny", "NotAction": "s3:*", "Resource": "*"}]}`,
+			expected:   []string{"s3:*"},
+			excluded:   []string{},
+			restricted: true,
+		},
+		{
+			name:       "within not action deny in boundary",
+			statement:  `{"Effect": "Allow", "Action": "s3:*", "Resource": "*"}`,
+			boundary:   `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "*", "Resource": "*"}, {"Effect": "Deny", "NotAction": "s3:*", "Resource": "*"}]}`,
+			expected:   []string{"s3:*"},
+			excluded:   []string{},
+			restricted: false,
+		},
+		{
+			name:       "not action narrowed by not action deny in scp",
+			statement:  `{"Effect": "Allow", "NotAction": "iam:*", "Resource": "*"}`,
+			scp:        `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "*", "Resource": "*"}, {"Effect": "Deny", "NotAction": ["s3:*", "iam:GetUser"], "Resource": "*"}]}`,
+			expected:   []string{"s3:*"},
+			excluded:   []string{},
+			restricted: true,
+		},
+	}
+
+	for _, c := range cases {
+		var statement Statement
+		if err := json.Unmarshal([]byte(c.statement), &statement); err != nil {
+			t.Errorf("Case '%s': unmarshal failed: %v", c.name, err)
+			continue
+		}
+
+		var boundary *actionLimit
+		var scps []*actionLimit
+		if c.boundary != "" {
+			policy := &iamPrincipalPolicy{}
+			if err := json.Unmarshal([]byte(c.boundary), &policy.Policy); err != nil {
+				t.Errorf("Case '%s': unmarshal failed: %v", c.name, err)
+				continue
+			}
+			boundary = newActionLimit(policy)
+		}
+		if c.scp != "" {
+			policy := &iamPrincipalPolicy{}
+			if err := json.Unmarshal([]byte(c.scp), &policy.Policy); err != nil {
+				t.Errorf("Case '%s': unmarshal failed: %v", c.name, err)
+				continue
+			}
+			scps = append(scps, newActionLimit(policy))
+		}
+
+		effective, excluded, byBoundary, byScp := limitStatementActions(statement, boundary, scps, len(scps) > 0)
+		if !reflect.DeepEqual(effective, c.expected) {
+			t.Errorf("Case '%s': expected %v, got %v", c.name, c.expected, effective)
+		}
+		if !reflect.DeepEqual(excluded, c.excluded) {
+			t.Errorf("Case '%s': expected excluded %v, got %v", c.name, c.excluded, excluded)
+		}
+		restricted := (byBoundary != nil && *byBoundary) || (byScp != nil && *byScp)
+		if restricted != c.restricted {
+			t.Errorf("Case '%s': expected restricted %v, got %v", c.name, c.restricted, restricted)
+		}
+	}
+}
@@ -0,0 +1,406 @@
+package aws
+
+import (
+	"context"
+	"strings"
+
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+// Privilege escalation techniques
+const (
+	escalationAssumeRole             = "assume_role"
+	escalationAttachRolePolicy       = "attach_role_policy"
+	escalationAttachUserPolicy       = "attach_user_policy"
+	escalationCreatePolicyVersion    = "create_policy_version"
+	escalationPassRoleEc2            = "pass_role_ec2"
+	escalationPassRoleLambda         = "pass_role_lambda"
+	escalationUpdateAssumeRolePolicy = "update_assume_role_policy"
+)
+
+var escalationDescriptions = map[string]string{
+	escalationAssumeRole:             "Assume a role which has more permissions.",
+	escalationAttachRolePolicy:       "Attach any managed policy, e.g. AdministratorAccess, to the role itself.",
+	escalationAttachUserPolicy:       "Attach any managed policy, e.g. AdministratorAccess, to the user itself.",
+	escalationCreatePolicyVersion:    "Create a new default version of a customer managed policy attached to the principal, granting any permissions.",
+	escalationPassRoleEc2:            "Pass a role to a new EC2 instance, then use the role's credentials from the instance.",
+	escalationPassRoleLambda:         "Pass a role to a new Lambda function, then invoke the function to act as the role.",
+	escalationUpdateAssumeRolePolicy: "Change the trust policy of a role to trust the principal, then assume it.",
+}
+
+// Privilege levels, from least to most privileged
+const (
+	privilegeLevelStandard   = "standard"
+	privilegeLevelPrivileged = "privileged"
+	privilegeLevelAdmin      = "admin"
+)
+
+var privilegeLevelRank = map[string]int{
+	privilegeLevelStandard:   0,
+	privilegeLevelPrivileged: 1,
+	privilegeLevelAdmin:      2,
+}
+
+// escalationMaxHops limits how many roles are chained together
+const escalationMaxHops = 5
+
+//// TABLE DEFINITION
+
+func tableAwsIamPrivilegeEscalationPath(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_iam_privilege_escalation_path",
+		Description: "AWS IAM Privilege Escalation Path",
+		List: &plugin.ListConfig{
+			Hydrate: listIamPrivilegeEscalationPaths,
+		},
+		Columns: awsColumns([]*plugin.Column{
+			{
+				Name:        "principal_arn",
+				Description: "The ARN of the user or role which can escalate its privileges.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Principal.PrincipalArn"),
+			},
+			{
+				Name:        "principal_name",
+				Description: "The name of the user or role.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Principal.PrincipalName"),
+			},
+			{
+				Name:        "principal_type",
+				Description: "The type of the principal (user | role).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Principal.PrincipalType"),
+			},
+			{
+				Name:        "principal_privilege_level",
+				Description: "The privilege level of the principal's own policies (standard | privileged | admin).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "technique",
+				Description: "The technique used in the last hop of the path (assume_role | attach_role_policy | attach_user_policy | create_policy_version | pass_role_ec2 | pass_role_lambda | update_assume_role_policy).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "description",
+				Description: "A description of the technique.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Technique").Transform(escalationTechniqueDescription),
+			},
+			{
+				Name:        "path",
+				Description: "The ARNs of the principal, the roles assumed on the way, and the target.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "hops",
+				Description: "The number of steps in the path.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("Path").Transform(escalationPathHops),
+			},
+			{
+				Name:        "target_arn",
+				Description: "The ARN of the role, user or managed policy whose privileges are gained.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "target_privilege_level",
+				Description: "The privilege level gained (privileged | admin).",
+				Type:        proto.ColumnType_STRING,
+			},
+
+			// Standard columns
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(iamPrivilegeEscalationPathTitle),
+			},
+		}),
+	}
+}
+
+type iamPrivilegeEscalationPath struct {
+	Principal               *iamPrincipalPolicies
+	PrincipalPrivilegeLevel string
+	Technique               string
+	Path                    []string
+	TargetArn               string
+	TargetPrivilegeLevel    string
+}
+
+//// LIST FUNCTION
+
+func listIamPrivilegeEscalationPaths(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("listIamPrivilegeEscalationPaths")
+
+	principals, err := listIamPrincipalPolicies(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	commonData, err := getCommonColumns(ctx, d, nil)
+	if err != nil {
+		return nil, err
+	}
+	graph := newEscalationGraph(principals, commonData.(*awsCommonColumnData))
+
+	principalArn := getOptionalStringQual(d, "principal_arn")
+	technique := getOptionalStringQual(d, "technique")
+
+	for _, principal := range principals {
+		if principalArn != "" && principalArn != principal.PrincipalArn {
+			continue
+		}
+		for _, path := range graph.escalationPaths(principal) {
+			if technique != "" && technique != path.Technique {
+				continue
+			}
+			d.StreamListItem(ctx, path)
+		}
+	}
+
+	return nil, nil
+}
+
+//// UTILITY FUNCTIONS
+
+// escalationGraph finds the ways principals can gain the permissions of other
+// principals, using their identity policies, permissions boundaries and the
+// trust policies of roles
+type escalationGraph struct {
+	roles     []*iamPrincipalPolicies
+	levels    map[string]string
+	partition string
+	accountID string
+}
+
+func newEscalationGraph(principals []*iamPrincipalPolicies, common *awsCommonColumnData) *escalationGraph {
+	graph := &escalationGraph{
+		levels:    map[string]string{},
+		partition: common.Partition,
+		accountID: common.AccountId,
+	}
+	for _, principal := range principals {
+		if principal.PrincipalType == "role" {
+			graph.roles = append(graph.roles, principal)
+		}
+	}
+	return graph
+}
+
+// escalationPaths returns the paths by which a principal can gain a higher
+// privilege level than its own policies give it. Roles it can assume are
+// followed transitively.
+func (graph *escalationGraph) escalationPaths(origin *iamPrincipalPolicies) []*iamPrivilegeEscalationPath {
+	originLevel := graph.privilegeLevel(origin)
+	var paths []*iamPrivilegeEscalationPath
+	found := map[string]bool{}
+
+	add := func(technique string, path []string, targetArn string, targetLevel string) {
+		key := technique + " " + targetArn
+		if found[key] || privilegeLevelRank[targetLevel] <= privilegeLevelRank[originLevel] {
+			return
+		}
+		found[key] = true
+		paths = append(paths, &iamPrivilegeEscalationPath{
+			Principal:               origin,
+			PrincipalPrivilegeLevel: originLevel,
+			Technique:               technique,
+			Path:                    path,
+			TargetArn:               targetArn,
+			TargetPrivilegeLevel:    targetLevel,
+		})
+	}
+
+	type step struct {
+		node *iamPrincipalPolicies
+		path []string
+	}
+	visited := map[string]bool{origin.PrincipalArn: true}
+	queue := []step{{origin, []string{origin.PrincipalArn}}}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		node := current.node
+		extend := func(arn string) []string {
+			return append(append([]string{}, current.path...), arn)
+		}
+
+		// Rewrite a customer managed policy attached to the principal
+		for _, policy := range node.Policies {
+			if policy.SourceType != principalPolicyManaged && policy.SourceType != principalPolicyGroupManaged {
+				continue
+			}
+			if arnAccountID(policy.SourceArn) == graph.accountID && graph.allows(node, "iam:CreatePolicyVersion", policy.SourceArn, nil) {
+				add(escalationCreatePolicyVersion, extend(policy.SourceArn), policy.SourceArn, privilegeLevelAdmin)
+			}
+		}
+
+		// Attach a managed policy to itself
+		switch {
+		case node.PrincipalType == "user" && graph.allows(node, "iam:AttachUserPolicy", node.PrincipalArn, nil):
+			add(escalationAttachUserPolicy, current.path, node.PrincipalArn, privilegeLevelAdmin)
+		case node.PrincipalType == "role" && graph.allows(node, "iam:AttachRolePolicy", node.PrincipalArn, nil):
+			add(escalationAttachRolePolicy, current.path, node.PrincipalArn, privilegeLevelAdmin)
+		}
+
+		for _, role := range graph.roles {
+			if role.PrincipalArn == node.PrincipalArn {
+				continue
+			}
+			roleLevel := graph.privilegeLevel(role)
+
+			if graph.canPassRoleToService(node, role, "lambda.amazonaws.com") &&
+				graph.allows(node, "lambda:CreateFunction", graph.arn("lambda", "*", "function:*"), nil) &&
+				graph.allows(node, "lambda:InvokeFunction", graph.arn("lambda", "*", "function:*"), nil) {
+				add(escalationPassRoleLambda, extend(role.PrincipalArn), role.PrincipalArn, roleLevel)
+			}
+
+			if graph.canPassRoleToService(node, role, "ec2.amazonaws.com") &&
+				graph.allows(node, "ec2:RunInstances", graph.arn("ec2", "*", "instance/*"), nil) {
+				add(escalationPassRoleEc2, extend(role.PrincipalArn), role.PrincipalArn, roleLevel)
+			}
+
+			if graph.allows(node, "iam:UpdateAssumeRolePolicy", role.PrincipalArn, nil) {
+				add(escalationUpdateAssumeRolePolicy, extend(role.PrincipalArn), role.PrincipalArn, roleLevel)
+			}
+
+			if !visited[role.PrincipalArn] && len(current.path) <= escalationMaxHops && graph.canAssumeRole(node, role) {
+				visited[role.PrincipalArn] = true
+				add(escalationAssumeRole, extend(role.PrincipalArn), role.PrincipalArn, roleLevel)
+				queue = append(queue, step{role, extend(role.PrincipalArn)})
+			}
+		}
+	}
+
+	return paths
+}
+
+// privilegeLevel classifies the permissions a principal's own policies give it
+func (graph *escalationGraph) privilegeLevel(principal *iamPrincipalPolicies) string {
+	if level, ok := graph.levels[principal.PrincipalArn]; ok {
+		return level
+	}
+
+	level := privilegeLevelStandard
+	if graph.allows(principal, "*", "*", nil) && graph.allows(principal, "iam:*", "*", nil) {
+		level = privilegeLevelAdmin
+	} else {
+		for _, action := range iamPrivilegeEscalationActions {
+			if graph.allows(principal, action, "*", nil) {
+				level = privilegeLevelPrivileged
+				break
+			}
+		}
+	}
+
+	graph.levels[principal.PrincipalArn] = level
+	return level
+}
+
+// allows returns true if the identity policies and permissions boundary of the
+// principal allow the action. Statements with conditions only apply if the
+// conditions hold for the given request context.
+func (graph *escalationGraph) allows(principal *iamPrincipalPolicies, action string, resource string, requestContext map[string][]string) bool {
+	if requestContext == nil {
+		requestContext = map[string][]string{}
+	}
+	request := &policyEvaluationRequest{
+		Action:   action,
+		Resource: resource,
+		Context:  requestContext,
+	}
+
+	var policies []Policy
+	for _, policy := range principal.Policies {
+		policies = append(policies, policy.Policy)
+	}
+	if result, err := evaluatePolicies(policies, request); err != nil || result.Decision != policyDecisionAllowed {
+		return false
+	}
+
+	if principal.PermissionsBoundary != nil {
+		result, err := evaluatePolicies([]Policy{principal.PermissionsBoundary.Policy}, request)
+		if err != nil || result.Decision != policyDecisionAllowed {
+			return false
+		}
+	}
+	return true
+}
+
+// canAssumeRole returns true if the role trusts the principal, and either the
+// trust policy names the principal or the principal's policies allow
+// sts:AssumeRole on the role
+func (graph *escalationGraph) canAssumeRole(principal *iamPrincipalPolicies, role *iamPrincipalPolicies) bool {
+	if role.TrustPolicy == nil {
+		return false
+	}
+	result, err := evaluatePolicies([]Policy{*role.TrustPolicy}, &policyEvaluationRequest{
+		Action:    "sts:AssumeRole",
+		Resource:  role.PrincipalArn,
+		Principal: principal.PrincipalArn,
+		Context:   map[string][]string{},
+	})
+	if err != nil || result.Decision != policyDecisionAllowed {
+		return false
+	}
+
+	for _, statement := range result.MatchedStatements {
+		if statement.Effect != "Allow" {
+			continue
+		}
+		if ids, ok := statement.Principal["AWS"].([]string); ok {
+			for _, id := range ids {
+				if id == principal.PrincipalArn {
+					return true
+				}
+			}
+		}
+	}
+	return graph.allows(principal, "sts:AssumeRole", role.PrincipalArn, nil)
+}
+
+// canPassRoleToService returns true if the role trusts the service, and the
+// principal may pass the role to it
+func (graph *escalationGraph) canPassRoleToService(principal *iamPrincipalPolicies, role *iamPrincipalPolicies, service string) bool {
+	if role.TrustPolicy == nil {
+		return false
+	}
+	result, err := evaluatePolicies([]Policy{*role.TrustPolicy}, &policyEvaluationRequest{
+		Action:    "sts:AssumeRole",
+		Resource:  role.PrincipalArn,
+		Principal: service,
+		Context:   map[string][]string{},
+	})
+	if err != nil || result.Decision != policyDecisionAllowed {
+		return false
+	}
+	return graph.allows(principal, "iam:PassRole", role.PrincipalArn, map[string][]string{
+		"iam:passedtoservice": {service},
+	})
+}
+
+// arn builds an ARN in the account, used to check actions which create resources
+func (graph *escalationGraph) arn(service string, region string, resource string) string {
+	return strings.Join([]string{"arn", graph.partition, service, region, graph.accountID, resource}, ":")
+}
+
+//// TRANSFORM FUNCTIONS
+
+func escalationTechniqueDescription(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	return escalationDescriptions[d.Value.(string)], nil
+}
+
+func escalationPathHops(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	return len(d.Value.([]string)) - 1, nil
+}
+
+func iamPrivilegeEscalationPathTitle(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	path := d.HydrateItem.(*iamPrivilegeEscalationPath)
+	return strings.Join([]string{path.Principal.PrincipalName, path.Technique}, " - "), nil
+}
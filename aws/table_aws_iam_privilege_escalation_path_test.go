@@ -0,0 +1,67 @@
+package aws
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestEscalationPaths(t *testing.T) {
+	policy := func(document string) Policy {
+		var p Policy
+		if err := json.Unmarshal([]byte(document), &p); err != nil {
+			t.Fatalf("unmarshal failed: %v", err)
+		}
+		return p
+	}
+	principal := func(principalType string, arn string, document string, trust string) *iamPrincipalPolicies {
+		p := &iamPrincipalPolicies{
+			PrincipalArn:  arn,
+			PrincipalName: arn[len("arn:aws:iam::123456789012:"):],
+			PrincipalType: principalType,
+			Policies: []*iamPrincipalPolicy{{
+				SourceType: principalPolicyInline,
+				SourceArn:  arn,
+				Policy:     policy(document),
+			}},
+		}
+		if trust != "" {
+			trustPolicy := policy(trust)
+			p.TrustPolicy = &trustPolicy
+		}
+		return p
+	}
+
+	admin := principal("role", "arn:aws:iam::123456789012:role/admin",
+		`{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "*", "Resource": "*"}}`,
+		`{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Principal": {"Service": "lambda.amazonaws.com"}, "Action": "sts:AssumeRole"}}`)
+	deployer := principal("role", "arn:aws:iam::123456789012:role/deployer",
+		`{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": ["iam:PassRole", "lambda:CreateFunction", "lambda:InvokeFunction"], "Resource": "*"}}`,
+		`{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Principal": {"AWS": "arn:aws:iam::123456789012:user/dev"}, "Action": "sts:AssumeRole"}}`)
+	dev := principal("user", "arn:aws:iam::123456789012:user/dev",
+		`{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}}`, "")
+
+	graph := newEscalationGraph([]*iamPrincipalPolicies{admin, deployer, dev}, &awsCommonColumnData{Partition: "aws", AccountId: "123456789012"})
+
+	var techniques []string
+	for _, path := range graph.escalationPaths(dev) {
+		techniques = append(techniques, path.Technique)
+		if path.Technique == escalationPassRoleLambda {
+			expected := []string{dev.PrincipalArn, deployer.PrincipalArn, admin.PrincipalArn}
+			if !reflect.DeepEqual(path.Path, expected) {
+				t.Errorf("expected path %v, got %v", expected, path.Path)
+			}
+			if path.TargetPrivilegeLevel != privilegeLevelAdmin {
+				t.Errorf("expected target level admin, got %s", path.TargetPrivilegeLevel)
+			}
+		}
+	}
+	expected := []string{escalationAssumeRole, escalationPassRoleLambda}
+	if !reflect.DeepEqual(techniques, expected) {
+		t.Errorf("expected techniques %v, got %v", expected, techniques)
+	}
+
+	if paths := graph.escalationPaths(admin); len(paths) != 0 {
+		t.Errorf("expected no paths for an admin, got %d", len(paths))
+	}
+}
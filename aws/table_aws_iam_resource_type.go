@@ -0,0 +1,102 @@
+package aws
+
+import (
+	"context"
+	"strings"
+
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableAwsIamResourceType(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_iam_resource_type",
+		Description: "AWS IAM Resource Type",
+		List: &plugin.ListConfig{
+			Hydrate: listIamResourceTypes,
+		},
+		Columns: []*plugin.Column{
+			{
+				Name:        "resource_type",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the resource type, e.g. bucket.",
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "prefix",
+				Type:        proto.ColumnType_STRING,
+				Description: "The service prefix of the resource type, e.g. s3.",
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "service_name",
+				Type:        proto.ColumnType_STRING,
+				Description: "The name of the service.",
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "arn",
+				Type:        proto.ColumnType_STRING,
+				Description: "The ARN format of the resource type, e.g. arn:${Partition}:s3:::${BucketName}.",
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "condition_keys",
+				Type:        proto.ColumnType_JSON,
+				Description: "The condition keys that can be used with the resource type.",
+				Transform:   transform.FromGo(),
+			},
+			{
+				Name:        "actions",
+				Type:        proto.ColumnType_JSON,
+				Description: "The actions which can be applied to the resource type, in lower case.",
+				Transform:   transform.FromGo(),
+			},
+		},
+	}
+}
+
+type awsIamResourceTypeData struct {
+	ResourceType  string
+	Prefix        string
+	ServiceName   string
+	Arn           string
+	ConditionKeys []string
+	Actions       []string
+}
+
+//// LIST FUNCTION
+
+func listIamResourceTypes(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	permissions, err := getIamPermissions(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, service := range permissions {
+		// Actions by resource type, ignoring the marker for required resources
+		actions := map[string][]string{}
+		for _, privilege := range service.Privileges {
+			action := strings.ToLower(service.Prefix + ":" + privilege.Privilege)
+			for _, resourceType := range privilege.ResourceTypes {
+				name := strings.TrimSuffix(resourceType.ResourceType, "*")
+				actions[name] = append(actions[name], action)
+			}
+		}
+
+		for _, resource := range service.Resources {
+			d.StreamListItem(ctx, awsIamResourceTypeData{
+				Actions:       uniqueStrings(actions[resource.Resource]),
+				Arn:           resource.Arn,
+				ConditionKeys: resource.ConditionKeys,
+				Prefix:        service.Prefix,
+				ResourceType:  resource.Resource,
+				ServiceName:   service.ServiceName,
+			})
+		}
+	}
+	return nil, nil
+}
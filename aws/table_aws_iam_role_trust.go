@@ -0,0 +1,308 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableAwsIamRoleTrust(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_iam_role_trust",
+		Description: "AWS IAM Role Trust",
+		List: &plugin.ListConfig{
+			Hydrate: listIamRoleTrusts,
+		},
+		Columns: awsColumns([]*plugin.Column{
+			{
+				Name:        "role_name",
+				Description: "The name of the role.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "role_arn",
+				Description: "The ARN of the role.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "statement_index",
+				Description: "The position of the trust policy statement, starting at 0.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "sid",
+				Description: "The statement ID, if any.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Statement.Sid").Transform(transform.NullIfZeroValue),
+			},
+			{
+				Name:        "principal",
+				Description: "The trusted principal, as written in the trust policy, e.g. an account ID, ARN, service or identity provider.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "principal_type",
+				Description: "The type of the trusted principal (account | service | federated | wildcard).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "principal_account_id",
+				Description: "The account of the trusted principal, for account principals.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "is_cross_account",
+				Description: "True if the trusted principal is in another account. Null for service and federated principals.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "is_external_to_org",
+				Description: "True if the trusted principal's account is not in the organization. Null for service and federated principals, or if the organization's accounts cannot be listed.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "has_external_id_condition",
+				Description: "True if the statement requires an sts:ExternalId.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "has_mfa_condition",
+				Description: "True if the statement has a condition on aws:MultiFactorAuthPresent or aws:MultiFactorAuthAge.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "risky",
+				Description: "True for wildcard trust which is not limited to trusted accounts, organizations or networks, and for trust in third-party accounts or identity providers with no conditions.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "actions",
+				Description: "The actions the statement allows the principal, e.g. sts:assumerole.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Statement.Action"),
+			},
+			{
+				Name:        "conditions",
+				Description: "The conditions of the statement, in canonical form.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Statement.Condition"),
+			},
+
+			// Standard columns
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(iamRoleTrustTitle),
+			},
+		}),
+	}
+}
+
+type iamRoleTrust struct {
+	RoleName               string
+	RoleArn                string
+	StatementIndex         int
+	Statement              Statement
+	Principal              string
+	PrincipalType          string
+	PrincipalAccountId     *string
+	IsCrossAccount         *bool
+	IsExternalToOrg        *bool
+	HasExternalIdCondition bool
+	HasMfaCondition        bool
+	Risky                  bool
+}
+
+//// LIST FUNCTION
+
+func listIamRoleTrusts(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("listIamRoleTrusts")
+
+	// Create Session
+	svc, err := IAMService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	commonData, err := getCommonColumns(ctx, d, nil)
+	if err != nil {
+		return nil, err
+	}
+	accountID := commonData.(*awsCommonColumnData).AccountId
+
+	orgAccounts, err := getOrganizationAccountIDs(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	roleName := getOptionalStringQual(d, "role_name")
+
+	var roles []*iam.Role
+	if roleName != "" {
+		op, err := svc.GetRole(&iam.GetRoleInput{RoleName: aws.String(roleName)})
+		if err != nil {
+			if a, ok := err.(awserr.Error); ok && a.Code() == iam.ErrCodeNoSuchEntityException {
+				return nil, nil
+			}
+			return nil, err
+		}
+		roles = append(roles, op.Role)
+	} else {
+		err = svc.ListRolesPages(
+			&iam.ListRolesInput{},
+			func(page *iam.ListRolesOutput, isLast bool) bool {
+				roles = append(roles, page.Roles...)
+				return !isLast
+			},
+		)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, role := range roles {
+		document, err := url.QueryUnescape(types.SafeString(role.AssumeRolePolicyDocument))
+		if err != nil {
+			return nil, err
+		}
+		var policy Policy
+		if err := json.Unmarshal([]byte(document), &policy); err != nil {
+			return nil, err
+		}
+
+		for i, statement := range policy.Statements {
+			if statement.Effect != "Allow" {
+				continue
+			}
+			for principalType, values := range statement.Principal {
+				ids, ok := values.([]string)
+				if !ok {
+					continue
+				}
+				for _, id := range ids {
+					trust := &iamRoleTrust{
+						RoleName:               types.SafeString(role.RoleName),
+						RoleArn:                types.SafeString(role.Arn),
+						StatementIndex:         i,
+						Statement:              statement,
+						Principal:              id,
+						HasExternalIdCondition: hasConditionKey(statement.Condition, "sts:externalid"),
+						HasMfaCondition:        hasConditionKey(statement.Condition, "aws:multifactorauthpresent", "aws:multifactorauthage"),
+					}
+					classifyRoleTrust(trust, principalType, accountID, orgAccounts)
+					d.StreamListItem(ctx, trust)
+				}
+			}
+		}
+	}
+
+	return nil, nil
+}
+
+//// UTILITY FUNCTIONS
+
+// classifyRoleTrust sets the type of a trusted principal, whether it is outside
+// the account or organization, and whether the trust is risky
+func classifyRoleTrust(trust *iamRoleTrust, principalType string, accountID string, orgAccounts map[string]bool) {
+	switch {
+	case principalType == "Service":
+		trust.PrincipalType = "service"
+	case principalType == "Federated":
+		trust.PrincipalType = "federated"
+		// Identity providers can authenticate anyone unless claims are checked
+		trust.Risky = len(trust.Statement.Condition) == 0
+	case trust.Principal == "*":
+		trust.PrincipalType = "wildcard"
+		trust.Risky = !isRestrictedByConditions(trust.Statement.Condition)
+	default:
+		trust.PrincipalType = "account"
+		account := trust.Principal
+		if !isAccountID(account) {
+			account = arnAccountID(account)
+		}
+		trust.PrincipalAccountId = &account
+
+		crossAccount := account != accountID
+		trust.IsCrossAccount = &crossAccount
+		if orgAccounts != nil {
+			external := !orgAccounts[account]
+			trust.IsExternalToOrg = &external
+		} else if !crossAccount {
+			external := false
+			trust.IsExternalToOrg = &external
+		}
+
+		// Third-party accounts, or other accounts if the organization is unknown
+		thirdParty := crossAccount && (trust.IsExternalToOrg == nil || *trust.IsExternalToOrg)
+		trust.Risky = thirdParty && len(trust.Statement.Condition) == 0
+	}
+}
+
+// hasConditionKey returns true if any condition of a statement tests one of the
+// given keys, which must be in lower case
+func hasConditionKey(conditions map[string]interface{}, keys ...string) bool {
+	for _, values := range conditions {
+		keyValues, ok := values.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key := range keyValues {
+			for _, k := range keys {
+				if strings.ToLower(key) == k {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// getOrganizationAccountIDs returns the IDs of the accounts in the organization,
+// or nil if the account is not in an organization or cannot list its accounts
+func getOrganizationAccountIDs(ctx context.Context, d *plugin.QueryData) (map[string]bool, error) {
+	// Create Session
+	svc, err := OrganizationService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts := map[string]bool{}
+	err = svc.ListAccountsPages(
+		&organizations.ListAccountsInput{},
+		func(page *organizations.ListAccountsOutput, isLast bool) bool {
+			for _, account := range page.Accounts {
+				accounts[types.SafeString(account.Id)] = true
+			}
+			return !isLast
+		},
+	)
+	if err != nil {
+		// Only the management account and delegated administrators can list accounts
+		if a, ok := err.(awserr.Error); ok && (a.Code() == "AWSOrganizationsNotInUseException" || a.Code() == "AccessDeniedException") {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return accounts, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+func iamRoleTrustTitle(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	trust := d.HydrateItem.(*iamRoleTrust)
+	return strings.Join([]string{trust.RoleName, trust.Principal}, " - "), nil
+}
@@ -0,0 +1,201 @@
+package aws
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableAwsIamServerCertificate(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_iam_server_certificate",
+		Description: "AWS IAM Server Certificate",
+		Get: &plugin.GetConfig{
+			KeyColumns:        plugin.AnyColumn([]string{"name", "arn"}),
+			ShouldIgnoreError: isNotFoundError([]string{"ValidationError", "NoSuchEntity", "InvalidParameter"}),
+			Hydrate:           getIamServerCertificateMetadata,
+		},
+		List: &plugin.ListConfig{
+			Hydrate: listIamServerCertificates,
+		},
+		HydrateDependencies: []plugin.HydrateDependencies{
+			{
+				Func:    getIamServerCertificateX509,
+				Depends: []plugin.HydrateFunc{getIamServerCertificate},
+			},
+		},
+		Columns: awsColumns(append([]*plugin.Column{
+			{
+				Name:        "name",
+				Description: "The name of the server certificate.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("ServerCertificateName"),
+			},
+			{
+				Name:        "arn",
+				Description: "The Amazon Resource Name (ARN) of the server certificate.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "server_certificate_id",
+				Description: "The stable and unique string identifying the server certificate.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "path",
+				Description: "The path to the server certificate.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "upload_date",
+				Description: "The date when the server certificate was uploaded.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "expiration",
+				Description: "The date on which the certificate is set to expire.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "certificate_body",
+				Description: "The contents of the public key certificate, PEM encoded.",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     getIamServerCertificate,
+			},
+			{
+				Name:        "certificate_chain",
+				Description: "The contents of the public key certificate chain, PEM encoded.",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     getIamServerCertificate,
+			},
+			{
+				Name:        "tags_src",
+				Description: "A list of tags that are attached to the server certificate.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getIamServerCertificate,
+				Transform:   transform.FromField("Tags"),
+			},
+
+			// Standard columns
+			{
+				Name:        "tags",
+				Description: resourceInterfaceDescription("tags"),
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getIamServerCertificate,
+				Transform:   transform.From(serverCertificateTurbotTags),
+			},
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("ServerCertificateName"),
+			},
+			{
+				Name:        "akas",
+				Description: resourceInterfaceDescription("akas"),
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Arn").Transform(arnToAkas),
+			},
+		}, x509CertificateColumns(getIamServerCertificateX509)...)),
+	}
+}
+
+//// LIST FUNCTION
+
+func listIamServerCertificates(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// Create Session
+	svc, err := IAMService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	err = svc.ListServerCertificatesPages(
+		&iam.ListServerCertificatesInput{},
+		func(page *iam.ListServerCertificatesOutput, lastPage bool) bool {
+			for _, certificate := range page.ServerCertificateMetadataList {
+				d.StreamListItem(ctx, certificate)
+			}
+			return !lastPage
+		},
+	)
+
+	return nil, err
+}
+
+//// HYDRATE FUNCTIONS
+
+func getIamServerCertificateMetadata(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getIamServerCertificateMetadata")
+
+	arn := d.KeyColumnQuals["arn"].GetStringValue()
+	name := d.KeyColumnQuals["name"].GetStringValue()
+	if len(arn) > 0 {
+		name = arn[strings.LastIndex(arn, "/")+1:]
+	}
+
+	certificate, err := getIamServerCertificateByName(ctx, d, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return certificate.ServerCertificateMetadata, nil
+}
+
+func getIamServerCertificate(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getIamServerCertificate")
+	metadata := h.Item.(*iam.ServerCertificateMetadata)
+
+	return getIamServerCertificateByName(ctx, d, *metadata.ServerCertificateName)
+}
+
+func getIamServerCertificateX509(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getIamServerCertificateX509")
+	certificate, ok := h.HydrateResults["getIamServerCertificate"].(*iam.ServerCertificate)
+	if !ok {
+		return nil, nil
+	}
+
+	return getX509CertificateData(ctx, certificate.CertificateBody, certificate.CertificateChain)
+}
+
+func getIamServerCertificateByName(ctx context.Context, d *plugin.QueryData, name string) (*iam.ServerCertificate, error) {
+	// Create Session
+	svc, err := IAMService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &iam.GetServerCertificateInput{
+		ServerCertificateName: aws.String(name),
+	}
+
+	op, err := svc.GetServerCertificate(params)
+	if err != nil {
+		plugin.Logger(ctx).Debug("getIamServerCertificateByName__", "ERROR", err)
+		return nil, err
+	}
+
+	return op.ServerCertificate, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+func serverCertificateTurbotTags(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	certificate := d.HydrateItem.(*iam.ServerCertificate)
+	if certificate.Tags == nil {
+		return nil, nil
+	}
+
+	turbotTagsMap := map[string]string{}
+	for _, i := range certificate.Tags {
+		turbotTagsMap[*i.Key] = *i.Value
+	}
+	return turbotTagsMap, nil
+}
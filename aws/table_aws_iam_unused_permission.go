@@ -0,0 +1,311 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/iam"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+// defaultUnusedDays is the number of days without access after which a granted
+// service is reported as unused
+const defaultUnusedDays = 90
+
+type awsIamUnusedPermissionData struct {
+	PrincipalArn            string
+	PrincipalName           string
+	PrincipalType           string
+	ServiceName             *string
+	ServiceNamespace        *string
+	GrantedActions          []string
+	LastAuthenticated       *time.Time
+	LastAuthenticatedRegion *string
+	DaysSinceLastUsed       *int64
+	UnusedDays              int64
+	UnusedActions           []*awsIamUnusedAction
+}
+
+type awsIamUnusedAction struct {
+	ActionName         *string
+	LastAccessedTime   *time.Time
+	LastAccessedRegion *string
+}
+
+//// TABLE DEFINITION
+
+func tableAwsIamUnusedPermission(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:             "aws_iam_unused_permission",
+		Description:      "AWS IAM Unused Permission",
+		DefaultTransform: transform.FromGo(),
+		List: &plugin.ListConfig{
+			Hydrate: listIamUnusedPermissions,
+		},
+		Columns: awsColumns([]*plugin.Column{
+			{
+				Name:        "principal_arn",
+				Description: "The ARN of the user or role.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "principal_name",
+				Description: "The name of the user or role.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "principal_type",
+				Description: "The type of the principal (user | role).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "service_namespace",
+				Description: "The namespace of the unused service, e.g. s3.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "service_name",
+				Description: "The name of the unused service.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "granted_actions",
+				Description: "The action patterns of the principal's effective policies which grant access to the service, in lower case.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "last_authenticated",
+				Description: "The date and time when the principal last accessed the service, or null if it has never accessed it within the tracking period.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "last_authenticated_region",
+				Description: "The Region from which the principal last accessed the service.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "days_since_last_used",
+				Description: "The number of days since the principal last accessed the service, or null if it has never accessed it within the tracking period.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "unused_days",
+				Description: "The number of days without access after which a service is reported as unused. Defaults to 90, and can be set in the where clause.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "unused_actions",
+				Description: "For services with action-level tracking, the tracked actions which have not been used for unused_days.",
+				Type:        proto.ColumnType_JSON,
+			},
+		}),
+	}
+}
+
+//// LIST FUNCTION
+
+func listIamUnusedPermissions(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("listIamUnusedPermissions")
+
+	unusedDays := int64(defaultUnusedDays)
+	if quals, ok := d.QueryContext.Quals["unused_days"]; ok {
+		for _, qual := range quals.Quals {
+			if qual.GetStringValue() == "=" && qual.Value != nil {
+				unusedDays = qual.Value.GetInt64Value()
+			}
+		}
+	}
+	cutoff := time.Now().AddDate(0, 0, -int(unusedDays))
+
+	principals, err := listIamPrincipalPolicies(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	scpLevels, scpsApply, err := getServiceControlPolicies(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create Session
+	svc, err := IAMService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	principalArn := getOptionalStringQual(d, "principal_arn")
+	principalType := getOptionalStringQual(d, "principal_type")
+
+	var selected []*iamPrincipalPolicies
+	for _, principal := range principals {
+		if (principalArn != "" && principalArn != principal.PrincipalArn) || (principalType != "" && principalType != principal.PrincipalType) {
+			continue
+		}
+		selected = append(selected, principal)
+	}
+
+	// Start a job for every principal before waiting for any of them
+	jobIDs := make([]*string, len(selected))
+	for i, principal := range selected {
+		op, err := svc.GenerateServiceLastAccessedDetails(&iam.GenerateServiceLastAccessedDetailsInput{
+			Arn:         aws.String(principal.PrincipalArn),
+			Granularity: aws.String(iam.AccessAdvisorUsageGranularityTypeActionLevel),
+		})
+		if err != nil {
+			return nil, err
+		}
+		jobIDs[i] = op.JobId
+	}
+
+	for i, principal := range selected {
+		services, err := getServiceLastAccessedResults(ctx, svc, jobIDs[i])
+		if err != nil {
+			return nil, err
+		}
+
+		granted := grantedActionSets(principal, scpLevels, scpsApply)
+
+		for _, service := range services {
+			grantedActions := grantedServiceActions(granted, types.SafeString(service.ServiceNamespace))
+			if len(grantedActions) == 0 {
+				continue
+			}
+
+			row := &awsIamUnusedPermissionData{
+				PrincipalArn:            principal.PrincipalArn,
+				PrincipalName:           principal.PrincipalName,
+				PrincipalType:           principal.PrincipalType,
+				ServiceName:             service.ServiceName,
+				ServiceNamespace:        service.ServiceNamespace,
+				GrantedActions:          grantedActions,
+				LastAuthenticated:       service.LastAuthenticated,
+				LastAuthenticatedRegion: service.LastAuthenticatedRegion,
+				UnusedDays:              unusedDays,
+			}
+			if service.LastAuthenticated != nil {
+				days := int64(time.Since(*service.LastAuthenticated).Hours() / 24)
+				row.DaysSinceLastUsed = &days
+			}
+			for _, action := range service.TrackedActionsLastAccessed {
+				if action.LastAccessedTime == nil || action.LastAccessedTime.Before(cutoff) {
+					row.UnusedActions = append(row.UnusedActions, &awsIamUnusedAction{
+						ActionName:         action.ActionName,
+						LastAccessedTime:   action.LastAccessedTime,
+						LastAccessedRegion: action.LastAccessedRegion,
+					})
+				}
+			}
+
+			// Services used recently are still reported if some tracked actions are unused
+			if service.LastAuthenticated != nil && !service.LastAuthenticated.Before(cutoff) && len(row.UnusedActions) == 0 {
+				continue
+			}
+			d.StreamListItem(ctx, row)
+		}
+	}
+
+	return nil, nil
+}
+
+//// UTILITY FUNCTIONS
+
+// getServiceLastAccessedResults waits for a service last accessed details job
+// to complete, and returns all of its results
+func getServiceLastAccessedResults(ctx context.Context, svc *iam.IAM, jobID *string) ([]*iam.ServiceLastAccessed, error) {
+	params := &iam.GetServiceLastAccessedDetailsInput{
+		JobId: jobID,
+	}
+
+	var services []*iam.ServiceLastAccessed
+	retryNumber := 0
+	for {
+		resp, err := svc.GetServiceLastAccessedDetails(params)
+		if err != nil {
+			return nil, err
+		}
+
+		switch types.SafeString(resp.JobStatus) {
+		case iam.JobStatusTypeInProgress:
+			if retryNumber >= maxRetries {
+				return nil, fmt.Errorf("timed out waiting for service last accessed details job %s", *jobID)
+			}
+			retryNumber++
+			plugin.Logger(ctx).Debug("getServiceLastAccessedResults in progress", "jobId", *jobID, "retryNumber", retryNumber)
+			time.Sleep(retryIntervalMs * time.Millisecond)
+			continue
+		case iam.JobStatusTypeFailed:
+			message := "unknown error"
+			if resp.Error != nil {
+				message = types.SafeString(resp.Error.Message)
+			}
+			return nil, fmt.Errorf("service last accessed details job %s failed: %s", *jobID, message)
+		}
+
+		services = append(services, resp.ServicesLastAccessed...)
+		if !types.BoolValue(resp.IsTruncated) {
+			return services, nil
+		}
+		params.Marker = resp.Marker
+	}
+}
+
+// grantedActionSets returns the actions the identity policies of a principal
+// allow, once its identity Deny statements, permissions boundary and SCPs are
+// applied, with one set for each Allow statement
+func grantedActionSets(principal *iamPrincipalPolicies, scpLevels [][]*iamPrincipalPolicy, scpsApply bool) []*actionSet {
+	var boundary *actionLimit
+	if principal.PermissionsBoundary != nil {
+		boundary = newActionLimit(principal.PermissionsBoundary)
+	}
+	applyScps := scpsApply && !strings.HasPrefix(principal.Path, "/aws-service-role/")
+	var scps []*actionLimit
+	if applyScps {
+		for _, level := range scpLevels {
+			scps = append(scps, newActionLimit(level...))
+		}
+	}
+
+	// Deny statements with no conditions, on all resources, remove actions from
+	// every Allow statement of the principal
+	identityDenies := newActionLimit(principal.Policies...)
+	identityDenies.allows = []Statement{{Effect: "Allow", Action: Value{"*"}}}
+
+	var sets []*actionSet
+	for _, policy := range principal.Policies {
+		for _, statement := range policy.Policy.Statements {
+			if statement.Effect != "Allow" {
+				continue
+			}
+			effective, excluded, _, _ := limitStatementActions(statement, boundary, scps, applyScps)
+			set := identityDenies.apply(&actionSet{patterns: effective, excluded: excluded})
+			if len(set.patterns) > 0 {
+				sets = append(sets, set)
+			}
+		}
+	}
+	return sets
+}
+
+// grantedServiceActions returns the granted action patterns which apply to a
+// service namespace. Sets which exclude the whole service, e.g. * with a
+// NotAction of iam:*, do not grant it.
+func grantedServiceActions(sets []*actionSet, namespace string) []string {
+	var actions []string
+	for _, set := range sets {
+		if matchesAnyPattern(set.excluded, namespace+":*") {
+			continue
+		}
+		for _, pattern := range set.patterns {
+			if wildcardMatch(strings.SplitN(pattern, ":", 2)[0], namespace) {
+				actions = append(actions, pattern)
+			}
+		}
+	}
+	return sortedUniqueStrings(actions)
+}
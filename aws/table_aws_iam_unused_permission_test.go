@@ -0,0 +1,95 @@
+package aws
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestGrantedServiceActions(t *testing.T) {
+	type testCase struct {
+		name      string
+		policy    string
+		boundary  string
+		namespace string
+		expected  []string
+	}
+
+	cases := []testCase{
+		{
+			name:      "granted by action",
+			policy:    `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": ["s3:GetObject", "ec2:*"], "Resource": "*"}}`,
+			namespace: "s3",
+			expected:  []string{"s3:getobject"},
+		},
+		{
+			name:      "not granted",
+			policy:    `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "s3:GetObject", "Resource": "*"}}`,
+			namespace: "iam",
+			expected:  []string{},
+		},
+		{
+			name:      "service excluded by not action",
+			policy:    `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "NotAction": "iam:*", "Resource": "*"}}`,
+			namespace: "iam",
+			expected:  []string{},
+		},
+		{
+			name:      "service granted by not action",
+			policy:    `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "NotAction": ["iam:*", "s3:Delete*"], "Resource": "*"}}`,
+			namespace: "s3",
+			expected:  []string{"*"},
+		},
+		{
+			name:      "service excluded by not action under a boundary",
+			policy:    `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "NotAction": "iam:*", "Resource": "*"}}`,
+			boundary:  `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Action": "*", "Resource": "*"}}`,
+			namespace: "iam",
+			expected:  []string{},
+		},
+		{
+			name:      "service denied by identity policy",
+			policy:    `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "*", "Resource": "*"}, {"Effect": "Deny", "Action": "iam:*", "Resource": "*"}]}`,
+			namespace: "iam",
+			expected:  []string{},
+		},
+		{
+			name:      "service denied by not action deny in identity policy",
+			policy:    `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "*", "Resource": "*"}, {"Effect": "Deny", "NotAction": "s3:*", "Resource": "*"}]}`,
+			namespace: "iam",
+			expected:  []string{},
+		},
+		{
+			name:      "service allowed by not action deny in identity policy",
+			policy:    `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "*", "Resource": "*"}, {"Effect": "Deny", "NotAction": "s3:*", "Resource": "*"}]}`,
+			namespace: "s3",
+			expected:  []string{"s3:*"},
+		},
+		{
+			name:      "conditional deny in identity policy",
+			policy:    `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Action": "iam:*", "Resource": "*"}, {"Effect": "Deny", "Action": "iam:*", "Resource": "*", "Condition": {"Bool": {"aws:MultiFactorAuthPresent": "false"}}}]}`,
+			namespace: "iam",
+			expected:  []string{"iam:*"},
+		},
+	}
+
+	for _, c := range cases {
+		principal := &iamPrincipalPolicies{Policies: []*iamPrincipalPolicy{{}}}
+		if err := json.Unmarshal([]byte(c.policy), &principal.Policies[0].Policy); err != nil {
+			t.Errorf("Case '%s': unmarshal failed: %v", c.name, err)
+			continue
+		}
+		if c.boundary != "" {
+			principal.PermissionsBoundary = &iamPrincipalPolicy{}
+			if err := json.Unmarshal([]byte(c.boundary), &principal.PermissionsBoundary.Policy); err != nil {
+				t.Errorf("Case '%s': unmarshal failed: %v", c.name, err)
+				continue
+			}
+		}
+
+		output := grantedServiceActions(grantedActionSets(principal, nil, false), c.namespace)
+		if !reflect.DeepEqual(output, c.expected) {
+			t.Errorf("Case '%s': expected %v, got %v", c.name, c.expected, output)
+		}
+	}
+}
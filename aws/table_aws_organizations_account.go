@@ -0,0 +1,247 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableAwsOrganizationsAccount(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_organizations_account",
+		Description: "AWS Organizations Account",
+		Get: &plugin.GetConfig{
+			KeyColumns:        plugin.SingleColumn("id"),
+			ShouldIgnoreError: isNotFoundError([]string{"AccountNotFoundException", "InvalidInputException"}),
+			Hydrate:           getOrganizationsAccount,
+		},
+		List: &plugin.ListConfig{
+			Hydrate: listOrganizationsAccounts,
+		},
+		Columns: awsColumns([]*plugin.Column{
+			{
+				Name:        "name",
+				Description: "The friendly name of the account.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "id",
+				Description: "The unique identifier (ID) of the account.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "arn",
+				Description: "The Amazon Resource Name (ARN) of the account.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "email",
+				Description: "The email address associated with the AWS account.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "status",
+				Description: "The status of the account in the organization (ACTIVE | SUSPENDED).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "joined_method",
+				Description: "The method by which the account joined the organization (INVITED | CREATED).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "joined_timestamp",
+				Description: "The date the account became a part of the organization.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "parent_id",
+				Description: "The unique identifier (ID) of the root or organizational unit that contains the account.",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     getOrganizationsAccountParent,
+				Transform:   transform.FromField("Id"),
+			},
+			{
+				Name:        "tags_src",
+				Description: "A list of tags assigned to the account.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getOrganizationsAccountTags,
+				Transform:   transform.FromValue(),
+			},
+
+			// Standard columns
+			{
+				Name:        "tags",
+				Description: resourceInterfaceDescription("tags"),
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getOrganizationsAccountTags,
+				Transform:   transform.FromValue().Transform(organizationsTurbotTags),
+			},
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Name"),
+			},
+			{
+				Name:        "akas",
+				Description: resourceInterfaceDescription("akas"),
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Arn").Transform(arnToAkas),
+			},
+		}),
+	}
+}
+
+//// LIST FUNCTION
+
+func listOrganizationsAccounts(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("listOrganizationsAccounts")
+
+	// Create session
+	svc, err := OrganizationService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	err = svc.ListAccountsPages(
+		&organizations.ListAccountsInput{},
+		func(page *organizations.ListAccountsOutput, isLast bool) bool {
+			for _, account := range page.Accounts {
+				d.StreamListItem(ctx, account)
+			}
+			return !isLast
+		},
+	)
+	if isOrganizationsNotInUseError(err) {
+		return nil, nil
+	}
+
+	return nil, err
+}
+
+//// HYDRATE FUNCTIONS
+
+func getOrganizationsAccount(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getOrganizationsAccount")
+	id := d.KeyColumnQuals["id"].GetStringValue()
+
+	// Create session
+	svc, err := OrganizationService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := svc.DescribeAccount(&organizations.DescribeAccountInput{
+		AccountId: aws.String(id),
+	})
+	if err != nil {
+		if isOrganizationsNotInUseError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return op.Account, nil
+}
+
+func getOrganizationsAccountParent(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getOrganizationsAccountParent")
+	account := h.Item.(*organizations.Account)
+
+	parent, err := getOrganizationsParent(ctx, d, *account.Id)
+	if err != nil || parent == nil {
+		return nil, err
+	}
+
+	return parent, nil
+}
+
+func getOrganizationsAccountTags(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getOrganizationsAccountTags")
+	account := h.Item.(*organizations.Account)
+
+	return getOrganizationsResourceTags(ctx, d, *account.Id)
+}
+
+//// UTILITY FUNCTIONS
+
+// getOrganizationsParent returns the root or organizational unit which directly
+// contains an account or organizational unit
+func getOrganizationsParent(ctx context.Context, d *plugin.QueryData, childID string) (*organizations.Parent, error) {
+	// Create session
+	svc, err := OrganizationService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := svc.ListParents(&organizations.ListParentsInput{
+		ChildId: aws.String(childID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Accounts and organizational units have exactly one parent
+	if len(op.Parents) > 0 {
+		return op.Parents[0], nil
+	}
+
+	return nil, nil
+}
+
+// getOrganizationsResourceTags returns the tags of an account, organizational
+// unit, root or policy
+func getOrganizationsResourceTags(ctx context.Context, d *plugin.QueryData, resourceID string) ([]*organizations.Tag, error) {
+	// Create session
+	svc, err := OrganizationService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []*organizations.Tag
+	err = svc.ListTagsForResourcePages(
+		&organizations.ListTagsForResourceInput{
+			ResourceId: aws.String(resourceID),
+		},
+		func(page *organizations.ListTagsForResourceOutput, isLast bool) bool {
+			tags = append(tags, page.Tags...)
+			return !isLast
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func isOrganizationsNotInUseError(err error) bool {
+	if a, ok := err.(awserr.Error); ok {
+		return a.Code() == "AWSOrganizationsNotInUseException"
+	}
+	return false
+}
+
+//// TRANSFORM FUNCTIONS
+
+func organizationsTurbotTags(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	tags, ok := d.Value.([]*organizations.Tag)
+	if !ok || tags == nil {
+		return nil, nil
+	}
+
+	turbotTagsMap := map[string]string{}
+	for _, i := range tags {
+		turbotTagsMap[*i.Key] = *i.Value
+	}
+
+	return turbotTagsMap, nil
+}
@@ -0,0 +1,247 @@
+package aws
+
+import (
+	"context"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableAwsOrganizationsOrganizationalUnit(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_organizations_organizational_unit",
+		Description: "AWS Organizations Organizational Unit",
+		Get: &plugin.GetConfig{
+			KeyColumns:        plugin.SingleColumn("id"),
+			ShouldIgnoreError: isNotFoundError([]string{"OrganizationalUnitNotFoundException", "InvalidInputException"}),
+			Hydrate:           getOrganizationsOrganizationalUnit,
+		},
+		List: &plugin.ListConfig{
+			Hydrate: listOrganizationsOrganizationalUnits,
+		},
+		Columns: awsColumns([]*plugin.Column{
+			{
+				Name:        "name",
+				Description: "The friendly name of the organizational unit.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("OrganizationalUnit.Name"),
+			},
+			{
+				Name:        "id",
+				Description: "The unique identifier (ID) of the organizational unit.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("OrganizationalUnit.Id"),
+			},
+			{
+				Name:        "arn",
+				Description: "The Amazon Resource Name (ARN) of the organizational unit.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("OrganizationalUnit.Arn"),
+			},
+			{
+				Name:        "parent_id",
+				Description: "The unique identifier (ID) of the root or organizational unit that contains the organizational unit.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "path",
+				Description: "The IDs of the root and each organizational unit from the root down to this organizational unit, separated by '/', e.g. r-ab12/ou-ab12-11111111/ou-ab12-22222222.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Path").Transform(organizationsPathToString),
+			},
+			{
+				Name:        "depth",
+				Description: "The number of organizational units between the root and this organizational unit, including itself.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("Path").Transform(organizationsPathDepth),
+			},
+			{
+				Name:        "tags_src",
+				Description: "A list of tags assigned to the organizational unit.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getOrganizationsOrganizationalUnitTags,
+				Transform:   transform.FromValue(),
+			},
+
+			// Standard columns
+			{
+				Name:        "tags",
+				Description: resourceInterfaceDescription("tags"),
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getOrganizationsOrganizationalUnitTags,
+				Transform:   transform.FromValue().Transform(organizationsTurbotTags),
+			},
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("OrganizationalUnit.Name"),
+			},
+			{
+				Name:        "akas",
+				Description: resourceInterfaceDescription("akas"),
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("OrganizationalUnit.Arn").Transform(arnToAkas),
+			},
+		}),
+	}
+}
+
+type organizationalUnitInfo struct {
+	OrganizationalUnit *organizations.OrganizationalUnit
+	ParentId           *string
+	// IDs from the root down to, and including, the organizational unit
+	Path []string
+}
+
+//// LIST FUNCTION
+
+func listOrganizationsOrganizationalUnits(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("listOrganizationsOrganizationalUnits")
+
+	// Create session
+	svc, err := OrganizationService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	var roots []*organizations.Root
+	err = svc.ListRootsPages(
+		&organizations.ListRootsInput{},
+		func(page *organizations.ListRootsOutput, isLast bool) bool {
+			roots = append(roots, page.Roots...)
+			return !isLast
+		},
+	)
+	if err != nil {
+		if isOrganizationsNotInUseError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	for _, root := range roots {
+		if err := listOrganizationalUnitsForParent(ctx, d, svc, []string{*root.Id}); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+// listOrganizationalUnitsForParent walks the organization tree depth first,
+// streaming each organizational unit below the last element of the path
+func listOrganizationalUnitsForParent(ctx context.Context, d *plugin.QueryData, svc *organizations.Organizations, parentPath []string) error {
+	parentID := parentPath[len(parentPath)-1]
+
+	var units []*organizations.OrganizationalUnit
+	err := svc.ListOrganizationalUnitsForParentPages(
+		&organizations.ListOrganizationalUnitsForParentInput{
+			ParentId: aws.String(parentID),
+		},
+		func(page *organizations.ListOrganizationalUnitsForParentOutput, isLast bool) bool {
+			units = append(units, page.OrganizationalUnits...)
+			return !isLast
+		},
+	)
+	if err != nil {
+		return err
+	}
+
+	for _, unit := range units {
+		path := append(append([]string{}, parentPath...), *unit.Id)
+		d.StreamListItem(ctx, &organizationalUnitInfo{
+			OrganizationalUnit: unit,
+			ParentId:           aws.String(parentID),
+			Path:               path,
+		})
+
+		if err := listOrganizationalUnitsForParent(ctx, d, svc, path); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getOrganizationsOrganizationalUnit(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getOrganizationsOrganizationalUnit")
+	id := d.KeyColumnQuals["id"].GetStringValue()
+
+	// Create session
+	svc, err := OrganizationService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := svc.DescribeOrganizationalUnit(&organizations.DescribeOrganizationalUnitInput{
+		OrganizationalUnitId: aws.String(id),
+	})
+	if err != nil {
+		if isOrganizationsNotInUseError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// Walk up the tree to build the path
+	path := []string{id}
+	for {
+		parent, err := getOrganizationsParent(ctx, d, path[0])
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil {
+			break
+		}
+		path = append([]string{*parent.Id}, path...)
+		if *parent.Type == organizations.ParentTypeRoot {
+			break
+		}
+	}
+
+	var parentID *string
+	if len(path) > 1 {
+		parentID = aws.String(path[len(path)-2])
+	}
+
+	return &organizationalUnitInfo{
+		OrganizationalUnit: op.OrganizationalUnit,
+		ParentId:           parentID,
+		Path:               path,
+	}, nil
+}
+
+func getOrganizationsOrganizationalUnitTags(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getOrganizationsOrganizationalUnitTags")
+	unit := h.Item.(*organizationalUnitInfo)
+
+	return getOrganizationsResourceTags(ctx, d, *unit.OrganizationalUnit.Id)
+}
+
+//// TRANSFORM FUNCTIONS
+
+func organizationsPathToString(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	path, ok := d.Value.([]string)
+	if !ok {
+		return nil, nil
+	}
+	return strings.Join(path, "/"), nil
+}
+
+func organizationsPathDepth(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	path, ok := d.Value.([]string)
+	if !ok || len(path) == 0 {
+		return nil, nil
+	}
+	// the first element is the root
+	return len(path) - 1, nil
+}
@@ -0,0 +1,275 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/organizations"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+// The organization policy types listed by the table
+var organizationsPolicyTypes = []string{
+	organizations.PolicyTypeServiceControlPolicy,
+	organizations.PolicyTypeTagPolicy,
+	organizations.PolicyTypeBackupPolicy,
+	organizations.PolicyTypeAiservicesOptOutPolicy,
+}
+
+//// TABLE DEFINITION
+
+func tableAwsOrganizationsPolicy(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_organizations_policy",
+		Description: "AWS Organizations Policy",
+		Get: &plugin.GetConfig{
+			KeyColumns:        plugin.SingleColumn("id"),
+			ShouldIgnoreError: isNotFoundError([]string{"PolicyNotFoundException", "InvalidInputException"}),
+			Hydrate:           getOrganizationsPolicySummary,
+		},
+		List: &plugin.ListConfig{
+			Hydrate: listOrganizationsPolicies,
+		},
+		Columns: awsColumns([]*plugin.Column{
+			{
+				Name:        "name",
+				Description: "The friendly name of the policy.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "id",
+				Description: "The unique identifier (ID) of the policy.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "arn",
+				Description: "The Amazon Resource Name (ARN) of the policy.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "type",
+				Description: "The type of policy (SERVICE_CONTROL_POLICY | TAG_POLICY | BACKUP_POLICY | AISERVICES_OPT_OUT_POLICY).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "aws_managed",
+				Description: "A boolean value that indicates whether the specified policy is an AWS managed policy.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "description",
+				Description: "The description of the policy.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "content",
+				Description: "The text content of the policy.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getOrganizationsPolicy,
+				Transform:   transform.FromField("Content").Transform(transform.UnmarshalYAML),
+			},
+			{
+				Name:        "content_std",
+				Description: "Contains the policy content in a canonical form for easier searching. Only set for service control policies, which use the IAM policy grammar.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getOrganizationsPolicy,
+				Transform:   transform.From(organizationsPolicyContentToStd),
+			},
+			{
+				Name:        "targets",
+				Description: "The roots, organizational units and accounts to which the policy is attached.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getOrganizationsPolicyTargets,
+				Transform:   transform.FromValue(),
+			},
+			{
+				Name:        "tags_src",
+				Description: "A list of tags assigned to the policy.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getOrganizationsPolicyTags,
+				Transform:   transform.FromValue(),
+			},
+
+			// Standard columns
+			{
+				Name:        "tags",
+				Description: resourceInterfaceDescription("tags"),
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getOrganizationsPolicyTags,
+				Transform:   transform.FromValue().Transform(organizationsTurbotTags),
+			},
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Name"),
+			},
+			{
+				Name:        "akas",
+				Description: resourceInterfaceDescription("akas"),
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Arn").Transform(arnToAkas),
+			},
+		}),
+	}
+}
+
+//// LIST FUNCTION
+
+func listOrganizationsPolicies(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("listOrganizationsPolicies")
+
+	// Create session
+	svc, err := OrganizationService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	policyTypes := organizationsPolicyTypes
+	if policyType := getOptionalStringQual(d, "type"); policyType != "" {
+		if !helpers.StringSliceContains(organizationsPolicyTypes, policyType) {
+			return nil, nil
+		}
+		policyTypes = []string{policyType}
+	}
+
+	for _, policyType := range policyTypes {
+		err = svc.ListPoliciesPages(
+			&organizations.ListPoliciesInput{
+				Filter: aws.String(policyType),
+			},
+			func(page *organizations.ListPoliciesOutput, isLast bool) bool {
+				for _, policy := range page.Policies {
+					d.StreamListItem(ctx, policy)
+				}
+				return !isLast
+			},
+		)
+		if err != nil {
+			if isOrganizationsNotInUseError(err) {
+				return nil, nil
+			}
+			// Policy types which are not enabled for the organization have no policies
+			if a, ok := err.(awserr.Error); ok && a.Code() == "PolicyTypeNotEnabledException" {
+				continue
+			}
+			return nil, err
+		}
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getOrganizationsPolicySummary(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getOrganizationsPolicySummary")
+	id := d.KeyColumnQuals["id"].GetStringValue()
+
+	policy, err := describeOrganizationsPolicy(ctx, d, id)
+	if err != nil || policy == nil {
+		return nil, err
+	}
+
+	return policy.PolicySummary, nil
+}
+
+func getOrganizationsPolicy(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getOrganizationsPolicy")
+	summary := h.Item.(*organizations.PolicySummary)
+
+	policy, err := describeOrganizationsPolicy(ctx, d, *summary.Id)
+	if err != nil || policy == nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+func getOrganizationsPolicyTargets(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getOrganizationsPolicyTargets")
+	summary := h.Item.(*organizations.PolicySummary)
+
+	// Create session
+	svc, err := OrganizationService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []*organizations.PolicyTargetSummary
+	err = svc.ListTargetsForPolicyPages(
+		&organizations.ListTargetsForPolicyInput{
+			PolicyId: summary.Id,
+		},
+		func(page *organizations.ListTargetsForPolicyOutput, isLast bool) bool {
+			targets = append(targets, page.Targets...)
+			return !isLast
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return targets, nil
+}
+
+func getOrganizationsPolicyTags(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getOrganizationsPolicyTags")
+	summary := h.Item.(*organizations.PolicySummary)
+
+	// AWS managed policies cannot be tagged
+	if types.BoolValue(summary.AwsManaged) {
+		return nil, nil
+	}
+
+	return getOrganizationsResourceTags(ctx, d, *summary.Id)
+}
+
+//// UTILITY FUNCTIONS
+
+func describeOrganizationsPolicy(ctx context.Context, d *plugin.QueryData, id string) (*organizations.Policy, error) {
+	// Create session
+	svc, err := OrganizationService(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+
+	op, err := svc.DescribePolicy(&organizations.DescribePolicyInput{
+		PolicyId: aws.String(id),
+	})
+	if err != nil {
+		if isOrganizationsNotInUseError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return op.Policy, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+// organizationsPolicyContentToStd converts service control policies to canonical
+// form. Tag, backup and AI services opt-out policies use their own syntax.
+func organizationsPolicyContentToStd(ctx context.Context, d *transform.TransformData) (interface{}, error) {
+	policy, ok := d.HydrateItem.(*organizations.Policy)
+	if !ok || policy == nil || policy.PolicySummary == nil {
+		return nil, nil
+	}
+
+	if types.SafeString(policy.PolicySummary.Type) != organizations.PolicyTypeServiceControlPolicy {
+		return nil, nil
+	}
+
+	content := types.SafeString(policy.Content)
+	if content == "" {
+		return nil, nil
+	}
+
+	return canonicalPolicy(content)
+}
@@ -0,0 +1,374 @@
+package aws
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+// resourcePolicyRestrictingKeys are the condition keys which, when required to
+// equal specific values, limit a statement to trusted principals or networks
+var resourcePolicyRestrictingKeys = map[string]bool{
+	"aws:principalaccount":  true,
+	"aws:principalarn":      true,
+	"aws:principalorgid":    true,
+	"aws:principalorgpaths": true,
+	"aws:sourceaccount":     true,
+	"aws:sourcearn":         true,
+	"aws:sourceorgid":       true,
+	"aws:sourceorgpaths":    true,
+	"aws:sourceowner":       true,
+	"aws:sourcevpc":         true,
+	"aws:sourcevpce":        true,
+	"aws:userid":            true,
+	"kms:calleraccount":     true,
+}
+
+// resourcePolicyAccountKeys are the condition keys whose values are account IDs
+var resourcePolicyAccountKeys = map[string]bool{
+	"aws:principalaccount": true,
+	"aws:sourceaccount":    true,
+	"aws:sourceowner":      true,
+	"kms:calleraccount":    true,
+}
+
+//// TABLE DEFINITION
+
+func tableAwsResourcePolicy(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_resource_policy",
+		Description: "AWS Resource Policy",
+		List: &plugin.ListConfig{
+			Hydrate: listResourcePolicies,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "arn",
+				Description: "The ARN of the resource.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Source.SourceArn"),
+			},
+			{
+				Name:        "name",
+				Description: "The name or ID of the resource.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Source.SourceName"),
+			},
+			{
+				Name:        "resource_type",
+				Description: "The CloudFormation type of the resource (AWS::S3::Bucket | AWS::KMS::Key | AWS::Lambda::Function | AWS::SNS::Topic | AWS::SQS::Queue | AWS::EC2::VPCEndpoint).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Source.ResourceType"),
+			},
+			{
+				Name:        "source_type",
+				Description: "The kind of policy (s3_bucket_policy | kms_key_policy | lambda_function_policy | sns_topic_policy | sqs_queue_policy | vpc_endpoint_policy).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Source.SourceType"),
+			},
+			{
+				Name:        "is_public",
+				Description: "True if an Allow statement grants access to any principal, and is not limited to trusted accounts, organizations or networks by its conditions or by a Deny statement. Always false for VPC endpoint policies, which only apply inside the VPC. Null if the policy could not be parsed.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("Access.IsPublic"),
+			},
+			{
+				Name:        "allowed_principal_accounts",
+				Description: "The AWS accounts granted access by the policy, from its principals and from account conditions such as aws:SourceAccount.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Access.AllowedPrincipalAccounts"),
+			},
+			{
+				Name:        "allowed_principal_services",
+				Description: "The AWS service principals granted access by the policy, e.g. sns.amazonaws.com.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Access.AllowedPrincipalServices"),
+			},
+			{
+				Name:        "public_actions",
+				Description: "The action patterns granted to any principal, in lower case. Statements using NotAction contribute *.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Access.PublicActions"),
+			},
+			{
+				Name:        "policy",
+				Description: "The resource-based policy document.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Source.Policy").Transform(transform.UnmarshalYAML),
+			},
+			{
+				Name:        "policy_std",
+				Description: "Contains the policy in a canonical form for easier searching.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Source.Policy").Transform(policyToCanonical),
+			},
+
+			// Standard columns
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Source.SourceName"),
+			},
+			{
+				Name:        "akas",
+				Description: resourceInterfaceDescription("akas"),
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Source.SourceArn").Transform(arnToAkas),
+			},
+		}),
+	}
+}
+
+type resourcePolicyData struct {
+	Source *policySourceInfo
+	// Nil if the policy document could not be parsed
+	Access *resourcePolicyAccess
+}
+
+// resourcePolicyAccess summarises who a resource-based policy grants access to
+type resourcePolicyAccess struct {
+	IsPublic                 bool
+	AllowedPrincipalAccounts []string
+	AllowedPrincipalServices []string
+	PublicActions            []string
+}
+
+//// LIST FUNCTION
+
+func listResourcePolicies(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	plugin.Logger(ctx).Trace("listResourcePolicies", "AWS_REGION", region)
+
+	// Only collect the kind of resource requested in the where clause
+	sourceType := getOptionalStringQual(d, "source_type")
+	resourceType := getOptionalStringQual(d, "resource_type")
+	include := func(t string) bool {
+		for _, collector := range resourcePolicyCollectors {
+			if collector.sourceType == t {
+				return (sourceType == "" || sourceType == t) && (resourceType == "" || resourceType == collector.resourceType)
+			}
+		}
+		return false
+	}
+
+	err := listResourcePolicySources(ctx, d, region, include, func(source *policySourceInfo) {
+		row := &resourcePolicyData{Source: source}
+		var policy Policy
+		if err := json.Unmarshal([]byte(source.Policy), &policy); err == nil {
+			row.Access = analyzeResourcePolicy(policy, source.SourceType)
+		}
+		d.StreamListItem(ctx, row)
+	})
+
+	return nil, err
+}
+
+//// UTILITY FUNCTIONS
+
+// analyzeResourcePolicy works out which accounts, services and anonymous
+// principals a resource-based policy allows. VPC endpoint policies only apply
+// to requests made through the endpoint from inside its VPC, so are never
+// public, e.g. with the default policy which allows everyone.
+func analyzeResourcePolicy(policy Policy, sourceType string) *resourcePolicyAccess {
+	access := &resourcePolicyAccess{}
+	accounts := map[string]bool{}
+	services := map[string]bool{}
+	actions := map[string]bool{}
+
+	for _, statement := range policy.Statements {
+		if statement.Effect != "Allow" {
+			continue
+		}
+
+		for _, account := range conditionAccounts(statement.Condition) {
+			accounts[account] = true
+		}
+
+		// NotPrincipal with Allow grants access to everyone not listed
+		public := len(statement.NotPrincipal) > 0
+		for principalType, values := range statement.Principal {
+			ids, ok := values.([]string)
+			if !ok {
+				continue
+			}
+			for _, id := range ids {
+				switch {
+				case principalType == "Service":
+					services[id] = true
+				case principalType != "AWS":
+					// Federated and canonical user principals
+				case id == "*":
+					public = true
+				case isAccountID(id):
+					accounts[id] = true
+				case isAccountID(arnAccountID(id)):
+					accounts[arnAccountID(id)] = true
+				}
+			}
+		}
+
+		if !public || sourceType == policySourceVpcEndpointPolicy || isRestrictedByConditions(statement.Condition) || isRestrictedByDeny(policy, statement) {
+			continue
+		}
+
+		access.IsPublic = true
+		if len(statement.NotAction) > 0 {
+			actions["*"] = true
+		}
+		for _, action := range statement.Action {
+			actions[action] = true
+		}
+	}
+
+	access.AllowedPrincipalAccounts = sortedKeys(accounts)
+	access.AllowedPrincipalServices = sortedKeys(services)
+	access.PublicActions = sortedKeys(actions)
+	return access
+}
+
+// isRestrictedByConditions returns true if the conditions of an Allow statement
+// require a restricting key, e.g. aws:PrincipalOrgID, to have specific values
+func isRestrictedByConditions(conditions map[string]interface{}) bool {
+	for operator, keys := range conditions {
+		op, err := parseConditionOperator(operator)
+		if err != nil || op.name == "null" || op.negated || op.ifExists || op.forAll {
+			continue
+		}
+		keyValues, ok := keys.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, values := range keyValues {
+			if resourcePolicyRestrictingKeys[strings.ToLower(key)] && !containsWildcardOnly(values) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isRestrictedByDeny returns true if the policy has a Deny statement for
+// everyone which covers the actions and resources of the Allow statement, and
+// only applies outside of trusted accounts, organizations or networks, e.g.
+// with StringNotEquals on aws:SourceVpc
+func isRestrictedByDeny(policy Policy, allow Statement) bool {
+	for _, deny := range policy.Statements {
+		if deny.Effect != "Deny" || !hasPublicPrincipal(deny.Principal) || len(deny.NotAction) > 0 || len(deny.NotResource) > 0 || len(deny.Condition) == 0 {
+			continue
+		}
+
+		guard := true
+		for operator, keys := range deny.Condition {
+			op, err := parseConditionOperator(operator)
+			keyValues, ok := keys.(map[string]interface{})
+			if err != nil || !ok || !op.negated || op.forAll || op.forAny {
+				guard = false
+				break
+			}
+			for key, values := range keyValues {
+				if !resourcePolicyRestrictingKeys[strings.ToLower(key)] || containsWildcardOnly(values) {
+					guard = false
+				}
+			}
+		}
+		if !guard {
+			continue
+		}
+
+		denyActions := append([]string{}, deny.Action...)
+		allowActions := append([]string{}, allow.Action...)
+		if len(allow.NotAction) > 0 {
+			allowActions = append(allowActions, "*")
+		}
+		allowResources := append([]string{}, allow.Resource...)
+		if len(allow.Resource) == 0 {
+			allowResources = append(allowResources, "*")
+		}
+		if patternsCover(denyActions, allowActions) && patternsCover(deny.Resource, allowResources) {
+			return true
+		}
+	}
+	return false
+}
+
+// patternsCover returns true if every value is matched by one of the patterns
+func patternsCover(patterns []string, values []string) bool {
+	for _, value := range values {
+		covered := false
+		for _, pattern := range patterns {
+			if wildcardMatch(pattern, value) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			return false
+		}
+	}
+	return true
+}
+
+// conditionAccounts returns the account IDs which the conditions of a statement
+// limit access to
+func conditionAccounts(conditions map[string]interface{}) []string {
+	var accounts []string
+	for operator, keys := range conditions {
+		op, err := parseConditionOperator(operator)
+		if err != nil || op.negated || op.name == "null" {
+			continue
+		}
+		keyValues, ok := keys.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for key, values := range keyValues {
+			key = strings.ToLower(key)
+			ids, ok := values.([]string)
+			if !ok {
+				continue
+			}
+			for _, id := range ids {
+				if resourcePolicyAccountKeys[key] && isAccountID(id) {
+					accounts = append(accounts, id)
+				}
+				if (key == "aws:principalarn" || key == "aws:sourcearn") && isAccountID(arnAccountID(id)) {
+					accounts = append(accounts, arnAccountID(id))
+				}
+			}
+		}
+	}
+	return accounts
+}
+
+func containsWildcardOnly(values interface{}) bool {
+	ids, ok := values.([]string)
+	if !ok {
+		return true
+	}
+	for _, id := range ids {
+		if id == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := []string{}
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
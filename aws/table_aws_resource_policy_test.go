@@ -0,0 +1,108 @@
+package aws
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeResourcePolicy(t *testing.T) {
+	type testCase struct {
+		name       string
+		sourceType string
+		policy     string
+		public     bool
+		accounts   []string
+		services   []string
+		actions    []string
+	}
+
+	cases := []testCase{
+		{
+			name:     "public read",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/*"}}`,
+			public:   true,
+			accounts: []string{},
+			services: []string{},
+			actions:  []string{"s3:getobject"},
+		},
+		{
+			name:     "restricted to organization",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Principal": {"AWS": "*"}, "Action": "s3:GetObject", "Resource": "*", "Condition": {"StringEquals": {"aws:PrincipalOrgID": "o-123456"}}}}`,
+			public:   false,
+			accounts: []string{},
+			services: []string{},
+			actions:  []string{},
+		},
+		{
+			name:     "restricted by source vpc deny",
+			policy:   `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "Resource": "arn:aws:s3:::bucket/*"}, {"Effect": "Deny", "Principal": "*", "Action": "s3:*", "Resource": "arn:aws:s3:::bucket/*", "Condition": {"StringNotEquals": {"aws:SourceVpc": "vpc-1234"}}}]}`,
+			public:   false,
+			accounts: []string{},
+			services: []string{},
+			actions:  []string{},
+		},
+		{
+			name:     "not resource allow with a deny on one resource",
+			policy:   `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "NotResource": "arn:aws:s3:::bucket/private/*"}, {"Effect": "Deny", "Principal": "*", "Action": "s3:*", "Resource": "arn:aws:s3:::bucket/secret", "Condition": {"StringNotEquals": {"aws:SourceVpc": "vpc-1234"}}}]}`,
+			public:   true,
+			accounts: []string{},
+			services: []string{},
+			actions:  []string{"s3:getobject"},
+		},
+		{
+			name:     "not resource allow with a deny on all resources",
+			policy:   `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": "*", "Action": "s3:GetObject", "NotResource": "arn:aws:s3:::bucket/private/*"}, {"Effect": "Deny", "Principal": "*", "Action": "s3:*", "Resource": "*", "Condition": {"StringNotEquals": {"aws:SourceVpc": "vpc-1234"}}}]}`,
+			public:   false,
+			accounts: []string{},
+			services: []string{},
+			actions:  []string{},
+		},
+		{
+			name:     "condition which does not restrict principals",
+			policy:   `{"Version": "2012-10-17", "Statement": {"Effect": "Allow", "Principal": "*", "Action": "sqs:SendMessage", "Resource": "*", "Condition": {"Bool": {"aws:SecureTransport": "true"}}}}`,
+			public:   true,
+			accounts: []string{},
+			services: []string{},
+			actions:  []string{"sqs:sendmessage"},
+		},
+		{
+			name:     "cross account and service principals",
+			policy:   `{"Version": "2012-10-17", "Statement": [{"Effect": "Allow", "Principal": {"AWS": ["arn:aws:iam::111122223333:root", "444455556666"]}, "Action": "kms:Decrypt", "Resource": "*"}, {"Effect": "Allow", "Principal": {"Service": "sns.amazonaws.com"}, "Action": "sqs:SendMessage", "Resource": "*", "Condition": {"ArnEquals": {"aws:SourceArn": "arn:aws:sns:us-east-1:777788889999:topic"}}}]}`,
+			public:   false,
+			accounts: []string{"111122223333", "444455556666", "777788889999"},
+			services: []string{"sns.amazonaws.com"},
+			actions:  []string{},
+		},
+		{
+			name:       "default vpc endpoint policy",
+			sourceType: policySourceVpcEndpointPolicy,
+			policy:     `{"Statement": [{"Action": "*", "Effect": "Allow", "Principal": "*", "Resource": "*"}]}`,
+			public:     false,
+			accounts:   []string{},
+			services:   []string{},
+			actions:    []string{},
+		},
+	}
+
+	for _, c := range cases {
+		var policy Policy
+		if err := json.Unmarshal([]byte(c.policy), &policy); err != nil {
+			t.Errorf("Case '%s': unmarshal failed: %v", c.name, err)
+			continue
+		}
+		access := analyzeResourcePolicy(policy, c.sourceType)
+		if access.IsPublic != c.public {
+			t.Errorf("Case '%s': expected is_public %v, got %v", c.name, c.public, access.IsPublic)
+		}
+		if !reflect.DeepEqual(access.AllowedPrincipalAccounts, c.accounts) {
+			t.Errorf("Case '%s': expected accounts %v, got %v", c.name, c.accounts, access.AllowedPrincipalAccounts)
+		}
+		if !reflect.DeepEqual(access.AllowedPrincipalServices, c.services) {
+			t.Errorf("Case '%s': expected services %v, got %v", c.name, c.services, access.AllowedPrincipalServices)
+		}
+		if !reflect.DeepEqual(access.PublicActions, c.actions) {
+			t.Errorf("Case '%s': expected actions %v, got %v", c.name, c.actions, access.PublicActions)
+		}
+	}
+}
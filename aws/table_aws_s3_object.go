@@ -0,0 +1,380 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+// s3ObjectInfo is a single row of the aws_s3_object table. When a delimiter is
+// given, the common prefixes returned by S3 are listed as rows with only the
+// key set.
+type s3ObjectInfo struct {
+	s3.Object
+	BucketName     string
+	Region         string
+	Prefix         *string
+	Delimiter      *string
+	IsCommonPrefix bool
+}
+
+//// TABLE DEFINITION
+
+func tableAwsS3Object(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_s3_object",
+		Description: "AWS S3 Object",
+		List: &plugin.ListConfig{
+			KeyColumns: plugin.SingleColumn("bucket_name"),
+			Hydrate:    listS3Objects,
+		},
+		Columns: awsS3Columns([]*plugin.Column{
+			{
+				Name:        "key",
+				Description: "The name that you assign to an object. With a delimiter, the common prefixes are also listed as keys.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "bucket_name",
+				Description: "The name of the bucket containing the object.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "prefix",
+				Description: "Limits the listing to keys that begin with the specified prefix.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "delimiter",
+				Description: "A character used to group keys. Keys that contain the delimiter after the prefix are rolled up into a single common prefix row.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "is_common_prefix",
+				Description: "True if the row is a common prefix rolled up by the delimiter rather than an object.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "size",
+				Description: "Size in bytes of the object.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "last_modified",
+				Description: "The date and time the object was last modified.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "etag",
+				Description: "The entity tag is a hash of the object. The ETag reflects changes only to the contents of an object, not its metadata.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("ETag"),
+			},
+			{
+				Name:        "storage_class",
+				Description: "The class of storage used to store the object.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "owner",
+				Description: "The owner of the object.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "content_type",
+				Description: "A standard MIME type describing the format of the object data.",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     headS3Object,
+			},
+			{
+				Name:        "server_side_encryption",
+				Description: "The server-side encryption algorithm used when storing the object (AES256 | aws:kms). Null if the object is not encrypted.",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     headS3Object,
+			},
+			{
+				Name:        "sse_kms_key_id",
+				Description: "The ID of the AWS KMS customer master key (CMK) that was used for the object, if any.",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     headS3Object,
+				Transform:   transform.FromField("SSEKMSKeyId"),
+			},
+			{
+				Name:        "bucket_key_enabled",
+				Description: "Indicates whether the object uses an S3 Bucket Key for server-side encryption with AWS KMS.",
+				Type:        proto.ColumnType_BOOL,
+				Hydrate:     headS3Object,
+			},
+			{
+				Name:        "version_id",
+				Description: "The version ID of the object.",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     headS3Object,
+			},
+			{
+				Name:        "object_lock_mode",
+				Description: "The object lock mode currently in place for the object (GOVERNANCE | COMPLIANCE).",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     headS3Object,
+			},
+			{
+				Name:        "object_lock_retain_until_date",
+				Description: "The date and time when the object lock retention period expires.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Hydrate:     headS3Object,
+			},
+			{
+				Name:        "object_lock_legal_hold_status",
+				Description: "Specifies whether a legal hold is in effect for the object (ON | OFF).",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     headS3Object,
+			},
+			{
+				Name:        "replication_status",
+				Description: "The replication status of the object (COMPLETE | PENDING | FAILED | REPLICA).",
+				Type:        proto.ColumnType_STRING,
+				Hydrate:     headS3Object,
+			},
+			{
+				Name:        "metadata",
+				Description: "A map of user-defined metadata stored with the object.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     headS3Object,
+			},
+			{
+				Name:        "acl",
+				Description: "The access control list (ACL) of the object.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getS3ObjectACL,
+				Transform:   transform.FromValue().NullIfZero(),
+			},
+			{
+				Name:        "tags_src",
+				Description: "A list of tags assigned to the object.",
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getS3ObjectTagging,
+				Transform:   transform.FromField("TagSet"),
+			},
+			{
+				Name:        "tags",
+				Description: resourceInterfaceDescription("tags"),
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getS3ObjectTagging,
+				Transform:   transform.FromField("TagSet").Transform(s3TagsToTurbotTags),
+			},
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Key"),
+			},
+			{
+				Name:        "akas",
+				Description: resourceInterfaceDescription("akas"),
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getS3ObjectAkas,
+				Transform:   transform.FromValue(),
+			},
+			{
+				Name:        "region",
+				Description: "The AWS Region in which the resource is located.",
+				Type:        proto.ColumnType_STRING,
+			},
+		}),
+	}
+}
+
+//// LIST FUNCTION
+
+func listS3Objects(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("listS3Objects")
+	bucketName := d.KeyColumnQuals["bucket_name"].GetStringValue()
+	if bucketName == "" {
+		return nil, nil
+	}
+
+	// Objects must be listed from the region the bucket lives in
+	location, err := getBucketLocation(ctx, d, &plugin.HydrateData{Item: &s3.Bucket{Name: aws.String(bucketName)}})
+	if err != nil {
+		if a, ok := err.(awserr.Error); ok && a.Code() == "NoSuchBucket" {
+			return nil, nil
+		}
+		return nil, err
+	}
+	region := *location.(*s3.GetBucketLocationOutput).LocationConstraint
+
+	// Create Session
+	svc, err := S3Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &s3.ListObjectsV2Input{
+		Bucket:     aws.String(bucketName),
+		FetchOwner: aws.Bool(true),
+	}
+	if prefix := getOptionalStringQual(d, "prefix"); prefix != "" {
+		input.Prefix = aws.String(prefix)
+	}
+	if delimiter := getOptionalStringQual(d, "delimiter"); delimiter != "" {
+		input.Delimiter = aws.String(delimiter)
+	}
+
+	err = svc.ListObjectsV2Pages(
+		input,
+		func(page *s3.ListObjectsV2Output, isLast bool) bool {
+			for _, object := range page.Contents {
+				d.StreamListItem(ctx, &s3ObjectInfo{
+					Object:     *object,
+					BucketName: bucketName,
+					Region:     region,
+					Prefix:     input.Prefix,
+					Delimiter:  input.Delimiter,
+				})
+			}
+			for _, commonPrefix := range page.CommonPrefixes {
+				d.StreamListItem(ctx, &s3ObjectInfo{
+					Object:         s3.Object{Key: commonPrefix.Prefix},
+					BucketName:     bucketName,
+					Region:         region,
+					Prefix:         input.Prefix,
+					Delimiter:      input.Delimiter,
+					IsCommonPrefix: true,
+				})
+			}
+			return !isLast
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func headS3Object(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("headS3Object")
+	object := h.Item.(*s3ObjectInfo)
+
+	// Common prefixes are not objects
+	if object.IsCommonPrefix {
+		return nil, nil
+	}
+
+	// Create Session
+	svc, err := S3Service(ctx, d, object.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &s3.HeadObjectInput{
+		Bucket: aws.String(object.BucketName),
+		Key:    object.Key,
+	}
+
+	op, err := svc.HeadObject(params)
+	if err != nil {
+		if isS3ObjectAccessDeniedError(err) {
+			plugin.Logger(ctx).Warn("headS3Object", "key", object.Key, "error", err)
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return op, nil
+}
+
+func getS3ObjectACL(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getS3ObjectACL")
+	object := h.Item.(*s3ObjectInfo)
+
+	// Common prefixes are not objects
+	if object.IsCommonPrefix {
+		return nil, nil
+	}
+
+	// Create Session
+	svc, err := S3Service(ctx, d, object.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &s3.GetObjectAclInput{
+		Bucket: aws.String(object.BucketName),
+		Key:    object.Key,
+	}
+
+	acl, err := svc.GetObjectAcl(params)
+	if err != nil {
+		if isS3ObjectAccessDeniedError(err) {
+			plugin.Logger(ctx).Warn("getS3ObjectACL", "key", object.Key, "error", err)
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return acl, nil
+}
+
+func getS3ObjectTagging(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getS3ObjectTagging")
+	object := h.Item.(*s3ObjectInfo)
+
+	// Common prefixes are not objects, and have no tags
+	if object.IsCommonPrefix {
+		return &s3.GetObjectTaggingOutput{}, nil
+	}
+
+	// Create Session
+	svc, err := S3Service(ctx, d, object.Region)
+	if err != nil {
+		return nil, err
+	}
+
+	params := &s3.GetObjectTaggingInput{
+		Bucket: aws.String(object.BucketName),
+		Key:    object.Key,
+	}
+
+	tags, err := svc.GetObjectTagging(params)
+	if err != nil {
+		if isS3ObjectAccessDeniedError(err) {
+			plugin.Logger(ctx).Warn("getS3ObjectTagging", "key", object.Key, "error", err)
+			return &s3.GetObjectTaggingOutput{}, nil
+		}
+		return nil, err
+	}
+
+	return tags, nil
+}
+
+func getS3ObjectAkas(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getS3ObjectAkas")
+	object := h.Item.(*s3ObjectInfo)
+	commonData, err := getCommonColumns(ctx, d, h)
+	if err != nil {
+		return nil, err
+	}
+	commonColumnData := commonData.(*awsCommonColumnData)
+
+	return []string{"arn:" + commonColumnData.Partition + ":s3:::" + object.BucketName + "/" + *object.Key}, nil
+}
+
+//// UTILITY FUNCTIONS
+
+// isS3ObjectAccessDeniedError returns true if the caller may list an object but
+// not read its metadata, ACL or tags. HeadObject has no response body, so its
+// error code is the HTTP status text.
+func isS3ObjectAccessDeniedError(err error) bool {
+	if a, ok := err.(awserr.Error); ok {
+		return a.Code() == "AccessDenied" || a.Code() == "Forbidden"
+	}
+	return false
+}
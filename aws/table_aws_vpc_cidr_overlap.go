@@ -0,0 +1,368 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableAwsVpcCidrOverlap(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_vpc_cidr_overlap",
+		Description: "AWS VPC CIDR Overlap",
+		List: &plugin.ListConfig{
+			Hydrate: listVpcCidrOverlaps,
+		},
+		Columns: awsMultiRegionColumns([]*plugin.Column{
+			{
+				Name:        "vpc_id",
+				Description: "The ID of the first VPC of the pair.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Vpc.VpcId"),
+			},
+			{
+				Name:        "owner_id",
+				Description: "The ID of the AWS account that owns the first VPC.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Vpc.OwnerId"),
+			},
+			{
+				Name:        "cidr_block",
+				Description: "The CIDR block of the first VPC which overlaps.",
+				Type:        proto.ColumnType_CIDR,
+			},
+			{
+				Name:        "other_vpc_id",
+				Description: "The ID of the second VPC of the pair.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("OtherVpc.VpcId"),
+			},
+			{
+				Name:        "other_region",
+				Description: "The region of the second VPC.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("OtherVpc.Region"),
+			},
+			{
+				Name:        "other_owner_id",
+				Description: "The ID of the AWS account that owns the second VPC.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("OtherVpc.OwnerId"),
+			},
+			{
+				Name:        "other_cidr_block",
+				Description: "The CIDR block of the second VPC which overlaps.",
+				Type:        proto.ColumnType_CIDR,
+			},
+			{
+				Name:        "overlap_cidr_block",
+				Description: "The range of addresses in both CIDR blocks, which is the smaller of the two.",
+				Type:        proto.ColumnType_CIDR,
+			},
+			{
+				Name:        "is_secondary",
+				Description: "True if either CIDR block is not the primary IPv4 CIDR block of its VPC.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "is_peered",
+				Description: "True if an active peering connection connects the two VPCs.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "vpc_peering_connection_id",
+				Description: "The ID of the active peering connection between the two VPCs.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "is_same_transit_gateway",
+				Description: "True if both VPCs are attached to the same transit gateway.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "transit_gateway_ids",
+				Description: "The IDs of the transit gateways both VPCs are attached to.",
+				Type:        proto.ColumnType_JSON,
+			},
+
+			// Standard columns
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(vpcCidrOverlapTitle),
+			},
+		}, "Vpc.Region"),
+		DefaultTransform: transform.FromGo(),
+	}
+}
+
+// vpcCidrInfo is a VPC and its CIDR blocks, either listed in a region of the
+// connection, or known from a peering connection
+type vpcCidrInfo struct {
+	VpcId   string
+	Region  string
+	OwnerId string
+	// CIDR blocks in association order, the primary CIDR block first
+	CidrBlocks []string
+}
+
+type vpcCidrOverlap struct {
+	Vpc                    *vpcCidrInfo
+	CidrBlock              string
+	OtherVpc               *vpcCidrInfo
+	OtherCidrBlock         string
+	OverlapCidrBlock       string
+	IsSecondary            bool
+	IsPeered               bool
+	VpcPeeringConnectionId *string
+	IsSameTransitGateway   bool
+	TransitGatewayIds      []string
+}
+
+//// LIST FUNCTION
+
+func listVpcCidrOverlaps(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("listVpcCidrOverlaps")
+
+	// VPCs in different regions can overlap, so every region of the connection
+	// is compared, even when the query is for some regions only
+	var vpcs []*vpcCidrInfo
+	var connections []*ec2.VpcPeeringConnection
+	var attachments []*ec2.TransitGatewayAttachment
+	for _, item := range BuildRegionList(ctx, d.Connection) {
+		vpcRegion := item[matrixKeyRegion].(string)
+
+		regionVpcs, err := getVpcVpcs(ctx, d, vpcRegion)
+		if err != nil {
+			return nil, err
+		}
+		for _, vpc := range regionVpcs {
+			vpcs = append(vpcs, &vpcCidrInfo{
+				VpcId:      types.SafeString(vpc.VpcId),
+				Region:     vpcRegion,
+				OwnerId:    types.SafeString(vpc.OwnerId),
+				CidrBlocks: vpcAssociatedCidrBlocks(vpc),
+			})
+		}
+
+		regionConnections, err := getVpcPeeringConnections(ctx, d, vpcRegion)
+		if err != nil {
+			return nil, err
+		}
+		connections = append(connections, regionConnections...)
+
+		regionAttachments, err := getTransitGatewayAttachments(ctx, d, vpcRegion)
+		if err != nil {
+			return nil, err
+		}
+		attachments = append(attachments, regionAttachments...)
+	}
+
+	// The region column is the region of the first VPC, so for queries on some
+	// regions, put the VPC in those regions first
+	regions := getStringQualValues(d, matrixKeyRegion)
+	for _, overlap := range vpcCidrOverlaps(vpcs, connections, attachments) {
+		if regions != nil && !helpers.StringSliceContains(regions, overlap.Vpc.Region) {
+			if !helpers.StringSliceContains(regions, overlap.OtherVpc.Region) {
+				continue
+			}
+			overlap.swap()
+		}
+		d.StreamListItem(ctx, overlap)
+	}
+
+	return nil, nil
+}
+
+//// UTILITY FUNCTIONS
+
+// vpcAssociatedCidrBlocks returns the IPv4 and IPv6 CIDR blocks associated with
+// a VPC, the primary CIDR block first
+func vpcAssociatedCidrBlocks(vpc *ec2.Vpc) []string {
+	var cidrs []string
+	if vpc.CidrBlock != nil {
+		cidrs = append(cidrs, *vpc.CidrBlock)
+	}
+	for _, association := range vpc.CidrBlockAssociationSet {
+		if association.CidrBlockState != nil && types.SafeString(association.CidrBlockState.State) != ec2.VpcCidrBlockStateCodeAssociated {
+			continue
+		}
+		cidrs = append(cidrs, types.SafeString(association.CidrBlock))
+	}
+	for _, association := range vpc.Ipv6CidrBlockAssociationSet {
+		if association.Ipv6CidrBlockState != nil && types.SafeString(association.Ipv6CidrBlockState.State) != ec2.VpcCidrBlockStateCodeAssociated {
+			continue
+		}
+		cidrs = append(cidrs, types.SafeString(association.Ipv6CidrBlock))
+	}
+	return uniqueStrings(cidrs)
+}
+
+// peeringVpcCidrInfo returns the VPC of one side of a peering connection
+func peeringVpcCidrInfo(info *ec2.VpcPeeringConnectionVpcInfo) *vpcCidrInfo {
+	vpc := &vpcCidrInfo{
+		VpcId:   types.SafeString(info.VpcId),
+		Region:  types.SafeString(info.Region),
+		OwnerId: types.SafeString(info.OwnerId),
+	}
+	if info.CidrBlock != nil {
+		vpc.CidrBlocks = append(vpc.CidrBlocks, *info.CidrBlock)
+	}
+	for _, block := range info.CidrBlockSet {
+		vpc.CidrBlocks = append(vpc.CidrBlocks, types.SafeString(block.CidrBlock))
+	}
+	for _, block := range info.Ipv6CidrBlockSet {
+		vpc.CidrBlocks = append(vpc.CidrBlocks, types.SafeString(block.Ipv6CidrBlock))
+	}
+	vpc.CidrBlocks = uniqueStrings(vpc.CidrBlocks)
+	return vpc
+}
+
+// swap exchanges the two VPCs of the pair
+func (overlap *vpcCidrOverlap) swap() {
+	overlap.Vpc, overlap.OtherVpc = overlap.OtherVpc, overlap.Vpc
+	overlap.CidrBlock, overlap.OtherCidrBlock = overlap.OtherCidrBlock, overlap.CidrBlock
+}
+
+// vpcPairKey returns the same key for a pair of VPCs in either order
+func vpcPairKey(a string, b string) string {
+	if a > b {
+		a, b = b, a
+	}
+	return a + "|" + b
+}
+
+// vpcCidrOverlaps returns each pair of overlapping CIDR blocks of different
+// VPCs. VPCs on the other side of a peering connection, which may be in
+// another account or region, are compared too.
+func vpcCidrOverlaps(vpcs []*vpcCidrInfo, connections []*ec2.VpcPeeringConnection, attachments []*ec2.TransitGatewayAttachment) []*vpcCidrOverlap {
+	known := map[string]*vpcCidrInfo{}
+	for _, vpc := range vpcs {
+		known[vpc.VpcId] = vpc
+	}
+
+	peered := map[string]string{}
+	for _, connection := range connections {
+		if connection.RequesterVpcInfo == nil || connection.AccepterVpcInfo == nil {
+			continue
+		}
+		status := ""
+		if connection.Status != nil {
+			status = types.SafeString(connection.Status.Code)
+		}
+		switch status {
+		case ec2.VpcPeeringConnectionStateReasonCodeDeleted, ec2.VpcPeeringConnectionStateReasonCodeDeleting,
+			ec2.VpcPeeringConnectionStateReasonCodeExpired, ec2.VpcPeeringConnectionStateReasonCodeFailed,
+			ec2.VpcPeeringConnectionStateReasonCodeRejected:
+			continue
+		}
+		for _, info := range []*ec2.VpcPeeringConnectionVpcInfo{connection.RequesterVpcInfo, connection.AccepterVpcInfo} {
+			if _, ok := known[types.SafeString(info.VpcId)]; !ok {
+				vpc := peeringVpcCidrInfo(info)
+				known[vpc.VpcId] = vpc
+			}
+		}
+		if status == ec2.VpcPeeringConnectionStateReasonCodeActive {
+			key := vpcPairKey(types.SafeString(connection.RequesterVpcInfo.VpcId), types.SafeString(connection.AccepterVpcInfo.VpcId))
+			peered[key] = types.SafeString(connection.VpcPeeringConnectionId)
+		}
+	}
+
+	transitGateways := map[string][]string{}
+	for _, attachment := range attachments {
+		if types.SafeString(attachment.ResourceType) != ec2.TransitGatewayAttachmentResourceTypeVpc ||
+			types.SafeString(attachment.State) != ec2.TransitGatewayAttachmentStateAvailable {
+			continue
+		}
+		vpcID := types.SafeString(attachment.ResourceId)
+		transitGateways[vpcID] = append(transitGateways[vpcID], types.SafeString(attachment.TransitGatewayId))
+	}
+
+	ids := make([]string, 0, len(known))
+	for id := range known {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var overlaps []*vpcCidrOverlap
+	for i, id := range ids {
+		vpc := known[id]
+		for _, otherID := range ids[i+1:] {
+			other := known[otherID]
+			for j, cidr := range vpc.CidrBlocks {
+				for k, otherCidr := range other.CidrBlocks {
+					overlapCidr, ok := cidrOverlap(cidr, otherCidr)
+					if !ok {
+						continue
+					}
+					overlap := &vpcCidrOverlap{
+						Vpc:              vpc,
+						CidrBlock:        cidr,
+						OtherVpc:         other,
+						OtherCidrBlock:   otherCidr,
+						OverlapCidrBlock: overlapCidr,
+						IsSecondary:      j > 0 || k > 0,
+					}
+					if connectionID, ok := peered[vpcPairKey(id, otherID)]; ok {
+						overlap.IsPeered = true
+						overlap.VpcPeeringConnectionId = &connectionID
+					}
+					overlap.TransitGatewayIds = []string{}
+					for _, transitGatewayID := range transitGateways[id] {
+						if helpers.StringSliceContains(transitGateways[otherID], transitGatewayID) {
+							overlap.TransitGatewayIds = append(overlap.TransitGatewayIds, transitGatewayID)
+						}
+					}
+					overlap.TransitGatewayIds = sortedUniqueStrings(overlap.TransitGatewayIds)
+					overlap.IsSameTransitGateway = len(overlap.TransitGatewayIds) > 0
+					overlaps = append(overlaps, overlap)
+				}
+			}
+		}
+	}
+	return overlaps
+}
+
+// cidrOverlap returns the range of addresses two CIDR blocks have in common.
+// CIDR blocks either contain one another or do not overlap at all, so the
+// overlap is the smaller block.
+func cidrOverlap(a string, b string) (string, bool) {
+	_, netA, err := net.ParseCIDR(a)
+	if err != nil {
+		return "", false
+	}
+	_, netB, err := net.ParseCIDR(b)
+	if err != nil {
+		return "", false
+	}
+	if len(netA.IP) != len(netB.IP) {
+		return "", false
+	}
+	onesA, _ := netA.Mask.Size()
+	onesB, _ := netB.Mask.Size()
+	if onesA >= onesB && netB.Contains(netA.IP) {
+		return netA.String(), true
+	}
+	if onesB >= onesA && netA.Contains(netB.IP) {
+		return netB.String(), true
+	}
+	return "", false
+}
+
+//// TRANSFORM FUNCTIONS
+
+func vpcCidrOverlapTitle(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	overlap := d.HydrateItem.(*vpcCidrOverlap)
+	return fmt.Sprintf("%s_%s_%s", overlap.Vpc.VpcId, overlap.OtherVpc.VpcId, overlap.OverlapCidrBlock), nil
+}
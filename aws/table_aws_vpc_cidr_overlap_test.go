@@ -0,0 +1,96 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestVpcCidrOverlaps(t *testing.T) {
+	vpcs := []*vpcCidrInfo{
+		{VpcId: "vpc-a", Region: "us-east-1", OwnerId: "111111111111", CidrBlocks: []string{"10.0.0.0/16", "100.64.0.0/16"}},
+		{VpcId: "vpc-b", Region: "us-east-1", OwnerId: "111111111111", CidrBlocks: []string{"10.0.128.0/20"}},
+		{VpcId: "vpc-c", Region: "eu-west-1", OwnerId: "111111111111", CidrBlocks: []string{"172.16.0.0/16", "100.64.0.0/20"}},
+		{VpcId: "vpc-d", Region: "us-east-1", OwnerId: "111111111111", CidrBlocks: []string{"192.168.0.0/16", "2600:1f18::/56"}},
+	}
+	connections := []*ec2.VpcPeeringConnection{
+		{
+			VpcPeeringConnectionId: aws.String("pcx-ab"),
+			Status:                 &ec2.VpcPeeringConnectionStateReason{Code: aws.String("active")},
+			RequesterVpcInfo:       &ec2.VpcPeeringConnectionVpcInfo{VpcId: aws.String("vpc-b")},
+			AccepterVpcInfo:        &ec2.VpcPeeringConnectionVpcInfo{VpcId: aws.String("vpc-a")},
+		},
+		{
+			VpcPeeringConnectionId: aws.String("pcx-external"),
+			Status:                 &ec2.VpcPeeringConnectionStateReason{Code: aws.String("pending-acceptance")},
+			RequesterVpcInfo:       &ec2.VpcPeeringConnectionVpcInfo{VpcId: aws.String("vpc-d")},
+			AccepterVpcInfo: &ec2.VpcPeeringConnectionVpcInfo{
+				VpcId:            aws.String("vpc-external"),
+				Region:           aws.String("us-west-2"),
+				OwnerId:          aws.String("222222222222"),
+				CidrBlock:        aws.String("192.168.10.0/24"),
+				CidrBlockSet:     []*ec2.CidrBlock{{CidrBlock: aws.String("192.168.10.0/24")}},
+				Ipv6CidrBlockSet: []*ec2.Ipv6CidrBlock{{Ipv6CidrBlock: aws.String("2600:1f18:0:10::/64")}},
+			},
+		},
+		{
+			VpcPeeringConnectionId: aws.String("pcx-deleted"),
+			Status:                 &ec2.VpcPeeringConnectionStateReason{Code: aws.String("deleted")},
+			RequesterVpcInfo:       &ec2.VpcPeeringConnectionVpcInfo{VpcId: aws.String("vpc-a")},
+			AccepterVpcInfo:        &ec2.VpcPeeringConnectionVpcInfo{VpcId: aws.String("vpc-gone"), CidrBlock: aws.String("10.0.0.0/8")},
+		},
+	}
+	attachments := []*ec2.TransitGatewayAttachment{
+		{TransitGatewayId: aws.String("tgw-1"), ResourceType: aws.String("vpc"), ResourceId: aws.String("vpc-a"), State: aws.String("available")},
+		{TransitGatewayId: aws.String("tgw-1"), ResourceType: aws.String("vpc"), ResourceId: aws.String("vpc-c"), State: aws.String("available")},
+		{TransitGatewayId: aws.String("tgw-2"), ResourceType: aws.String("vpc"), ResourceId: aws.String("vpc-b"), State: aws.String("deleted")},
+	}
+
+	type result struct {
+		vpc, cidr, other, otherCidr, overlap  string
+		secondary, peered, sameTransitGateway bool
+	}
+	expected := []result{
+		{"vpc-a", "10.0.0.0/16", "vpc-b", "10.0.128.0/20", "10.0.128.0/20", false, true, false},
+		{"vpc-a", "100.64.0.0/16", "vpc-c", "100.64.0.0/20", "100.64.0.0/20", true, false, true},
+		{"vpc-d", "192.168.0.0/16", "vpc-external", "192.168.10.0/24", "192.168.10.0/24", false, false, false},
+		{"vpc-d", "2600:1f18::/56", "vpc-external", "2600:1f18:0:10::/64", "2600:1f18:0:10::/64", true, false, false},
+	}
+
+	var results []result
+	for _, overlap := range vpcCidrOverlaps(vpcs, connections, attachments) {
+		results = append(results, result{
+			overlap.Vpc.VpcId, overlap.CidrBlock, overlap.OtherVpc.VpcId, overlap.OtherCidrBlock, overlap.OverlapCidrBlock,
+			overlap.IsSecondary, overlap.IsPeered, overlap.IsSameTransitGateway,
+		})
+		if overlap.IsPeered && aws.StringValue(overlap.VpcPeeringConnectionId) != "pcx-ab" {
+			t.Errorf("Case '%s %s': expected peering connection pcx-ab, got %s", overlap.Vpc.VpcId, overlap.OtherVpc.VpcId, aws.StringValue(overlap.VpcPeeringConnectionId))
+		}
+	}
+	if !reflect.DeepEqual(results, expected) {
+		t.Errorf("Case 'overlaps': expected %v, got %v", expected, results)
+	}
+}
+
+func TestCidrOverlap(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		expected string
+		ok       bool
+	}{
+		{"10.0.0.0/16", "10.0.1.0/24", "10.0.1.0/24", true},
+		{"10.0.1.0/24", "10.0.0.0/16", "10.0.1.0/24", true},
+		{"10.0.0.0/16", "10.0.0.0/16", "10.0.0.0/16", true},
+		{"10.0.0.0/16", "10.1.0.0/16", "", false},
+		{"10.0.0.0/8", "::/0", "", false},
+		{"10.0.0.0/8", "invalid", "", false},
+	}
+	for _, c := range cases {
+		overlap, ok := cidrOverlap(c.a, c.b)
+		if overlap != c.expected || ok != c.ok {
+			t.Errorf("Case '%s %s': expected %s %v, got %s %v", c.a, c.b, c.expected, c.ok, overlap, ok)
+		}
+	}
+}
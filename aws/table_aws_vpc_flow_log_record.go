@@ -0,0 +1,487 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/cloudwatchlogs"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+// The format used by flow logs created without a custom LogFormat
+const defaultFlowLogFormat = "${version} ${account-id} ${interface-id} ${srcaddr} ${dstaddr} ${srcport} ${dstport} ${protocol} ${packets} ${bytes} ${start} ${end} ${action} ${log-status}"
+
+var flowLogFormatField = regexp.MustCompile(`\$\{([a-z0-9-]+)\}`)
+
+//// TABLE DEFINITION
+
+func tableAwsVpcFlowLogRecord(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_vpc_flow_log_record",
+		Description: "AWS VPC Flow Log Record",
+		List: &plugin.ListConfig{
+			KeyColumns: plugin.SingleColumn("log_group_name"),
+			Hydrate:    listVpcFlowLogRecords,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "log_group_name",
+				Description: "The name of the log group to which the flow log records are published.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "log_stream_name",
+				Description: "The name of the log stream to which this record belongs.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "event_id",
+				Description: "The ID of the log event.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "timestamp",
+				Description: "The time when the log event was recorded.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("Timestamp").Transform(transform.UnixMsToTimestamp),
+			},
+			{
+				Name:        "ingestion_time",
+				Description: "The time when the log event was ingested.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("IngestionTime").Transform(transform.UnixMsToTimestamp),
+			},
+			{
+				Name:        "interface_id",
+				Description: "The ID of the network interface for which the traffic is recorded.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "srcaddr",
+				Description: "The source address for incoming traffic, or the IPv4 or IPv6 address of the network interface for outgoing traffic.",
+				Type:        proto.ColumnType_IPADDR,
+				Transform:   transform.FromField("SrcAddr"),
+			},
+			{
+				Name:        "dstaddr",
+				Description: "The destination address for outgoing traffic, or the IPv4 or IPv6 address of the network interface for incoming traffic.",
+				Type:        proto.ColumnType_IPADDR,
+				Transform:   transform.FromField("DstAddr"),
+			},
+			{
+				Name:        "srcport",
+				Description: "The source port of the traffic.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("SrcPort"),
+			},
+			{
+				Name:        "dstport",
+				Description: "The destination port of the traffic.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("DstPort"),
+			},
+			{
+				Name:        "protocol",
+				Description: "The IANA protocol number of the traffic.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "packets",
+				Description: "The number of packets transferred during the flow.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "bytes",
+				Description: "The number of bytes transferred during the flow.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "start_time",
+				Description: "The time when the first packet of the flow was received within the aggregation interval.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("Start").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "end_time",
+				Description: "The time when the last packet of the flow was received within the aggregation interval.",
+				Type:        proto.ColumnType_TIMESTAMP,
+				Transform:   transform.FromField("End").Transform(transform.UnixToTimestamp),
+			},
+			{
+				Name:        "action",
+				Description: "The action that is associated with the traffic (ACCEPT | REJECT).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "log_status",
+				Description: "The logging status of the flow log (OK | NODATA | SKIPDATA).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "version",
+				Description: "The VPC flow logs version.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "vpc_id",
+				Description: "The ID of the VPC that contains the network interface, if included in the log format.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "subnet_id",
+				Description: "The ID of the subnet that contains the network interface, if included in the log format.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "instance_id",
+				Description: "The ID of the instance associated with the network interface, if included in the log format.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "tcp_flags",
+				Description: "The bitmask value for the TCP flags seen during the flow, if included in the log format.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "type",
+				Description: "The type of traffic (IPv4 | IPv6 | EFA), if included in the log format.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "pkt_srcaddr",
+				Description: "The packet-level (original) source IP address of the traffic, if included in the log format.",
+				Type:        proto.ColumnType_IPADDR,
+				Transform:   transform.FromField("PktSrcAddr"),
+			},
+			{
+				Name:        "pkt_dstaddr",
+				Description: "The packet-level (original) destination IP address of the traffic, if included in the log format.",
+				Type:        proto.ColumnType_IPADDR,
+				Transform:   transform.FromField("PktDstAddr"),
+			},
+			{
+				Name:        "message",
+				Description: "The raw flow log record.",
+				Type:        proto.ColumnType_STRING,
+			},
+		}),
+	}
+}
+
+type vpcFlowLogRecord struct {
+	LogGroupName  *string
+	LogStreamName *string
+	EventId       *string
+	Timestamp     *int64
+	IngestionTime *int64
+	Message       *string
+	Version       *int64
+	InterfaceId   *string
+	SrcAddr       *string
+	DstAddr       *string
+	SrcPort       *int64
+	DstPort       *int64
+	Protocol      *int64
+	Packets       *int64
+	Bytes         *int64
+	Start         *int64
+	End           *int64
+	Action        *string
+	LogStatus     *string
+	VpcId         *string
+	SubnetId      *string
+	InstanceId    *string
+	TcpFlags      *int64
+	Type          *string
+	PktSrcAddr    *string
+	PktDstAddr    *string
+}
+
+// columns which can be pushed down to CloudWatch Logs as a filter pattern,
+// keyed by column name, with the flow log field they match
+var flowLogRecordFilterColumns = map[string]string{
+	"action":     "action",
+	"log_status": "log-status",
+	"srcaddr":    "srcaddr",
+	"dstaddr":    "dstaddr",
+	"srcport":    "srcport",
+	"dstport":    "dstport",
+	"protocol":   "protocol",
+}
+
+//// LIST FUNCTION
+
+func listVpcFlowLogRecords(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	// TODO put me in helper function
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	plugin.Logger(ctx).Trace("listVpcFlowLogRecords", "AWS_REGION", region)
+
+	logGroupName := d.KeyColumnQuals["log_group_name"].GetStringValue()
+	if logGroupName == "" {
+		return nil, nil
+	}
+
+	formats, err := getVpcFlowLogFormats(ctx, d, region, logGroupName)
+	if err != nil {
+		return nil, err
+	}
+
+	// Create session
+	svc, err := CloudWatchLogsService(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &cloudwatchlogs.FilterLogEventsInput{
+		LogGroupName: aws.String(logGroupName),
+	}
+
+	// Flow log streams are named after the network interface, e.g. eni-1234567890abcdef0-all
+	if interfaceID := getOptionalStringQual(d, "interface_id"); interfaceID != "" {
+		input.LogStreamNamePrefix = aws.String(interfaceID)
+	}
+
+	start, end := getTimestampQualRange(d, "timestamp")
+	if start != nil {
+		input.StartTime = aws.Int64(start.UnixNano() / 1e6)
+	}
+	if end != nil {
+		input.EndTime = aws.Int64(end.UnixNano() / 1e6)
+	}
+
+	// A filter pattern can only be built if every record in the group has the same format
+	if len(formats) == 1 {
+		if pattern := buildFlowLogFilterPattern(d, formats[0]); pattern != "" {
+			input.FilterPattern = aws.String(pattern)
+		}
+	}
+
+	err = svc.FilterLogEventsPages(
+		input,
+		func(page *cloudwatchlogs.FilterLogEventsOutput, isLast bool) bool {
+			for _, event := range page.Events {
+				d.StreamListItem(ctx, parseVpcFlowLogRecord(logGroupName, event, formats))
+			}
+			return !isLast
+		},
+	)
+	if err != nil {
+		// The log group only exists in one of the regions being queried
+		if a, ok := err.(awserr.Error); ok {
+			if a.Code() == "ResourceNotFoundException" {
+				return nil, nil
+			}
+		}
+		return nil, err
+	}
+
+	return nil, nil
+}
+
+//// UTILITY FUNCTIONS
+
+// getVpcFlowLogFormats returns the field lists of the flow logs which publish to the
+// log group, falling back to the default format if none are found in this region
+func getVpcFlowLogFormats(ctx context.Context, d *plugin.QueryData, region string, logGroupName string) ([][]string, error) {
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	var logFormats []string
+	err = svc.DescribeFlowLogsPages(
+		&ec2.DescribeFlowLogsInput{
+			Filter: []*ec2.Filter{
+				{
+					Name:   aws.String("log-group-name"),
+					Values: []*string{aws.String(logGroupName)},
+				},
+			},
+		},
+		func(page *ec2.DescribeFlowLogsOutput, lastPage bool) bool {
+			for _, flowLog := range page.FlowLogs {
+				logFormats = append(logFormats, types.SafeString(flowLog.LogFormat))
+			}
+			return !lastPage
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(logFormats) == 0 {
+		logFormats = []string{defaultFlowLogFormat}
+	}
+
+	var formats [][]string
+	for _, logFormat := range uniqueStrings(logFormats) {
+		if logFormat == "" {
+			logFormat = defaultFlowLogFormat
+		}
+		formats = append(formats, parseFlowLogFormat(logFormat))
+	}
+
+	return formats, nil
+}
+
+// parseFlowLogFormat converts a LogFormat such as "${version} ${srcaddr}" to its
+// list of field names
+func parseFlowLogFormat(logFormat string) []string {
+	var fields []string
+	for _, match := range flowLogFormatField.FindAllStringSubmatch(logFormat, -1) {
+		fields = append(fields, match[1])
+	}
+	return fields
+}
+
+// buildFlowLogFilterPattern converts the '=' quals on the filterable columns to a
+// space-delimited CloudWatch Logs filter pattern for the given format
+func buildFlowLogFilterPattern(d *plugin.QueryData, format []string) string {
+	conditions := map[string]string{}
+	for column, field := range flowLogRecordFilterColumns {
+		quals, ok := d.QueryContext.Quals[column]
+		if !ok || len(quals.Quals) != 1 {
+			continue
+		}
+		qual := quals.Quals[0]
+		if qual.GetStringValue() != "=" || qual.Value == nil {
+			continue
+		}
+		switch column {
+		case "srcaddr", "dstaddr":
+			if addr := qual.Value.GetInetValue().GetAddr(); addr != "" {
+				conditions[field] = fmt.Sprintf("%s=\"%s\"", fieldToPatternName(field), addr)
+			}
+		case "srcport", "dstport", "protocol":
+			conditions[field] = fmt.Sprintf("%s=%d", fieldToPatternName(field), qual.Value.GetInt64Value())
+		default:
+			if value := qual.Value.GetStringValue(); value != "" {
+				conditions[field] = fmt.Sprintf("%s=\"%s\"", fieldToPatternName(field), value)
+			}
+		}
+	}
+
+	if len(conditions) == 0 {
+		return ""
+	}
+
+	var terms []string
+	matched := 0
+	for _, field := range format {
+		if condition, ok := conditions[field]; ok {
+			terms = append(terms, condition)
+			matched++
+		} else {
+			terms = append(terms, fieldToPatternName(field))
+		}
+	}
+
+	// no filterable field is part of this format
+	if matched == 0 {
+		return ""
+	}
+
+	return "[" + strings.Join(terms, ", ") + "]"
+}
+
+func fieldToPatternName(field string) string {
+	return strings.ReplaceAll(field, "-", "_")
+}
+
+// parseVpcFlowLogRecord parses a flow log event using the first format with the
+// same number of fields as the record
+func parseVpcFlowLogRecord(logGroupName string, event *cloudwatchlogs.FilteredLogEvent, formats [][]string) *vpcFlowLogRecord {
+	record := &vpcFlowLogRecord{
+		LogGroupName:  aws.String(logGroupName),
+		LogStreamName: event.LogStreamName,
+		EventId:       event.EventId,
+		Timestamp:     event.Timestamp,
+		IngestionTime: event.IngestionTime,
+		Message:       event.Message,
+	}
+
+	values := strings.Fields(types.SafeString(event.Message))
+	for _, format := range formats {
+		if len(format) != len(values) {
+			continue
+		}
+		for i, field := range format {
+			setVpcFlowLogRecordField(record, field, values[i])
+		}
+		break
+	}
+
+	return record
+}
+
+func setVpcFlowLogRecordField(record *vpcFlowLogRecord, field string, value string) {
+	// "-" indicates that the field does not apply to, or could not be computed for, this record
+	if value == "-" {
+		return
+	}
+
+	switch field {
+	case "version":
+		record.Version = flowLogInt(value)
+	case "interface-id":
+		record.InterfaceId = aws.String(value)
+	case "srcaddr":
+		record.SrcAddr = aws.String(value)
+	case "dstaddr":
+		record.DstAddr = aws.String(value)
+	case "srcport":
+		record.SrcPort = flowLogInt(value)
+	case "dstport":
+		record.DstPort = flowLogInt(value)
+	case "protocol":
+		record.Protocol = flowLogInt(value)
+	case "packets":
+		record.Packets = flowLogInt(value)
+	case "bytes":
+		record.Bytes = flowLogInt(value)
+	case "start":
+		record.Start = flowLogInt(value)
+	case "end":
+		record.End = flowLogInt(value)
+	case "action":
+		record.Action = aws.String(value)
+	case "log-status":
+		record.LogStatus = aws.String(value)
+	case "vpc-id":
+		record.VpcId = aws.String(value)
+	case "subnet-id":
+		record.SubnetId = aws.String(value)
+	case "instance-id":
+		record.InstanceId = aws.String(value)
+	case "tcp-flags":
+		record.TcpFlags = flowLogInt(value)
+	case "type":
+		record.Type = aws.String(value)
+	case "pkt-srcaddr":
+		record.PktSrcAddr = aws.String(value)
+	case "pkt-dstaddr":
+		record.PktDstAddr = aws.String(value)
+	}
+}
+
+func flowLogInt(value string) *int64 {
+	i, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &i
+}
@@ -0,0 +1,197 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+func tableAwsVpcManagedPrefixList(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_vpc_managed_prefix_list",
+		Description: "AWS VPC Managed Prefix List",
+		Get: &plugin.GetConfig{
+			KeyColumns:        plugin.SingleColumn("id"),
+			ShouldIgnoreError: isNotFoundError([]string{"InvalidPrefixListID.NotFound", "InvalidPrefixListID.Malformed"}),
+			Hydrate:           getVpcManagedPrefixList,
+		},
+		List: &plugin.ListConfig{
+			Hydrate: listVpcManagedPrefixLists,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "id",
+				Description: "The ID of the prefix list.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PrefixListId"),
+			},
+			{
+				Name:        "name",
+				Description: "The name of the prefix list.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PrefixListName"),
+			},
+			{
+				Name:        "arn",
+				Description: "The Amazon Resource Name (ARN) of the prefix list.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PrefixListArn"),
+			},
+			{
+				Name:        "state",
+				Description: "The current state of the prefix list.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "state_message",
+				Description: "The state message of the prefix list.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "address_family",
+				Description: "The IP address version of the prefix list (IPv4 | IPv6).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "max_entries",
+				Description: "The maximum number of entries for the prefix list.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "version",
+				Description: "The version of the prefix list.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "owner_id",
+				Description: "The ID of the owner of the prefix list. AWS-managed prefix lists are owned by AWS.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "tags_src",
+				Description: "A list of tags that are attached to the prefix list.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Tags"),
+			},
+
+			// Standard columns
+			{
+				Name:        "tags",
+				Description: resourceInterfaceDescription("tags"),
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromP(vpcManagedPrefixListTurbotData, "Tags"),
+			},
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromP(vpcManagedPrefixListTurbotData, "Title"),
+			},
+			{
+				Name:        "akas",
+				Description: resourceInterfaceDescription("akas"),
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("PrefixListArn").Transform(arnToAkas),
+			},
+		}),
+	}
+}
+
+//// LIST FUNCTION
+
+func listVpcManagedPrefixLists(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	plugin.Logger(ctx).Trace("listVpcManagedPrefixLists", "AWS_REGION", region)
+
+	// Create session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	// List call
+	err = svc.DescribeManagedPrefixListsPages(
+		&ec2.DescribeManagedPrefixListsInput{},
+		func(page *ec2.DescribeManagedPrefixListsOutput, isLast bool) bool {
+			for _, prefixList := range page.PrefixLists {
+				d.StreamListItem(ctx, prefixList)
+			}
+			return !isLast
+		},
+	)
+
+	return nil, err
+}
+
+//// HYDRATE FUNCTIONS
+
+func getVpcManagedPrefixList(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getVpcManagedPrefixList")
+
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	prefixListID := d.KeyColumnQuals["id"].GetStringValue()
+
+	// get service
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the params
+	params := &ec2.DescribeManagedPrefixListsInput{
+		PrefixListIds: []*string{aws.String(prefixListID)},
+	}
+
+	// Get call
+	op, err := svc.DescribeManagedPrefixLists(params)
+	if err != nil {
+		plugin.Logger(ctx).Debug("getVpcManagedPrefixList__", "ERROR", err)
+		return nil, err
+	}
+
+	if len(op.PrefixLists) > 0 {
+		return op.PrefixLists[0], nil
+	}
+	return nil, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+func vpcManagedPrefixListTurbotData(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	prefixList := d.HydrateItem.(*ec2.ManagedPrefixList)
+	param := d.Param.(string)
+
+	// Get resource title
+	title := prefixList.PrefixListId
+	if prefixList.PrefixListName != nil {
+		title = prefixList.PrefixListName
+	}
+
+	// Get the resource tags
+	var turbotTagsMap map[string]string
+	if prefixList.Tags != nil {
+		turbotTagsMap = map[string]string{}
+		for _, i := range prefixList.Tags {
+			turbotTagsMap[*i.Key] = *i.Value
+		}
+	}
+
+	if param == "Tags" {
+		return turbotTagsMap, nil
+	}
+
+	return title, nil
+}
@@ -0,0 +1,125 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+func tableAwsVpcManagedPrefixListEntry(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_vpc_managed_prefix_list_entry",
+		Description: "AWS VPC Managed Prefix List Entry",
+		List: &plugin.ListConfig{
+			ParentHydrate: listVpcManagedPrefixLists,
+			Hydrate:       listVpcManagedPrefixListEntries,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "prefix_list_id",
+				Description: "The ID of the prefix list.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PrefixList.PrefixListId"),
+			},
+			{
+				Name:        "prefix_list_name",
+				Description: "The name of the prefix list.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PrefixList.PrefixListName"),
+			},
+			{
+				Name:        "cidr",
+				Description: "The CIDR block of the entry.",
+				Type:        proto.ColumnType_CIDR,
+				Transform:   transform.FromField("Entry.Cidr"),
+			},
+			{
+				Name:        "description",
+				Description: "The description of the entry.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Entry.Description"),
+			},
+			{
+				Name:        "address_family",
+				Description: "The IP address version of the prefix list (IPv4 | IPv6).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PrefixList.AddressFamily"),
+			},
+			{
+				Name:        "version",
+				Description: "The version of the prefix list the entry belongs to.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("PrefixList.Version"),
+			},
+			{
+				Name:        "owner_id",
+				Description: "The ID of the owner of the prefix list.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PrefixList.OwnerId"),
+			},
+
+			// Standard columns
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(vpcManagedPrefixListEntryTitle),
+			},
+		}),
+	}
+}
+
+type vpcManagedPrefixListEntry struct {
+	PrefixList *ec2.ManagedPrefixList
+	Entry      *ec2.PrefixListEntry
+}
+
+//// LIST FUNCTION
+
+func listVpcManagedPrefixListEntries(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	prefixList := h.Item.(*ec2.ManagedPrefixList)
+	plugin.Logger(ctx).Trace("listVpcManagedPrefixListEntries", "AWS_REGION", region, "prefixListId", prefixList.PrefixListId)
+
+	// Avoid expanding every list when only one is wanted
+	if prefixListID := getOptionalStringQual(d, "prefix_list_id"); prefixListID != "" && prefixListID != types.SafeString(prefixList.PrefixListId) {
+		return nil, nil
+	}
+
+	// Create session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	err = svc.GetManagedPrefixListEntriesPages(
+		&ec2.GetManagedPrefixListEntriesInput{
+			PrefixListId:  prefixList.PrefixListId,
+			TargetVersion: prefixList.Version,
+		},
+		func(page *ec2.GetManagedPrefixListEntriesOutput, isLast bool) bool {
+			for _, entry := range page.Entries {
+				d.StreamLeafListItem(ctx, &vpcManagedPrefixListEntry{prefixList, entry})
+			}
+			return !isLast
+		},
+	)
+
+	return nil, err
+}
+
+//// TRANSFORM FUNCTIONS
+
+func vpcManagedPrefixListEntryTitle(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	row := d.HydrateItem.(*vpcManagedPrefixListEntry)
+	return types.SafeString(row.PrefixList.PrefixListId) + "_" + types.SafeString(row.Entry.Cidr), nil
+}
@@ -0,0 +1,168 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+func tableAwsVpcNetworkACLEvaluation(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_vpc_network_acl_evaluation",
+		Description: "AWS VPC Network ACL Evaluation",
+		List: &plugin.ListConfig{
+			KeyColumns: plugin.AllColumns([]string{"network_acl_id", "direction", "cidr"}),
+			Hydrate:    listVpcNetworkACLEvaluations,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns(append([]*plugin.Column{
+			// "Key" Columns
+			{
+				Name:        "network_acl_id",
+				Description: "The ID of the network ACL to evaluate.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("NetworkAclId"),
+			},
+			{
+				Name:        "direction",
+				Description: "The direction of the traffic (ingress | egress).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Direction"),
+			},
+			{
+				Name:        "cidr",
+				Description: "The IP address or CIDR the traffic comes from, for ingress, or goes to, for egress. An entry only matches if its range contains every address of the CIDR.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Cidr"),
+			},
+			{
+				Name:        "traffic_protocol",
+				Description: "The protocol of the traffic (tcp | udp | icmp | icmpv6 | -1), or a protocol number. Defaults to tcp.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("TrafficProtocol"),
+			},
+			{
+				Name:        "port",
+				Description: "The port of the traffic, or the ICMP type. Required for tcp and udp.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.FromField("Port"),
+			},
+
+			// Result columns
+			{
+				Name:        "decision",
+				Description: "Whether the network ACL allows or denies the traffic (allow | deny).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Decision"),
+			},
+			{
+				Name:        "vpc_id",
+				Description: "The ID of the VPC of the network ACL.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("NetworkAcl.VpcId"),
+			},
+		}, append(vpcNetworkACLEntryColumns,
+			// Standard columns
+			&plugin.Column{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(vpcNetworkACLEvaluationTitle),
+			},
+		)...)),
+	}
+}
+
+type vpcNetworkACLEvaluation struct {
+	NetworkAclId    string
+	Direction       string
+	Cidr            string
+	TrafficProtocol string
+	Port            *int64
+	Decision        string
+	NetworkAcl      *ec2.NetworkAcl
+	// The deciding entry
+	Entry *ec2.NetworkAclEntry
+}
+
+//// LIST FUNCTION
+
+func listVpcNetworkACLEvaluations(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	plugin.Logger(ctx).Trace("listVpcNetworkACLEvaluations", "AWS_REGION", region)
+
+	networkACLID := d.KeyColumnQuals["network_acl_id"].GetStringValue()
+	direction := d.KeyColumnQuals["direction"].GetStringValue()
+	cidr := d.KeyColumnQuals["cidr"].GetStringValue()
+	if direction != "ingress" && direction != "egress" {
+		return nil, fmt.Errorf("direction must be ingress or egress")
+	}
+	peer, err := parseNetworkAddress(cidr)
+	if err != nil {
+		return nil, err
+	}
+
+	protocol := getOptionalStringQual(d, "traffic_protocol")
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	var port *int64
+	if quals, ok := d.QueryContext.Quals["port"]; ok {
+		for _, qual := range quals.Quals {
+			if qual.GetStringValue() == "=" && qual.Value != nil {
+				value := qual.Value.GetInt64Value()
+				port = &value
+			}
+		}
+	}
+	traffic := networkTraffic{Protocol: ipProtocolNumber(protocol), Port: -1}
+	if port != nil {
+		traffic.Port = *port
+	} else if traffic.Protocol == "6" || traffic.Protocol == "17" {
+		return nil, fmt.Errorf("evaluation of %s traffic requires a port", protocol)
+	}
+
+	acls, err := getVpcNetworkAcls(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, acl := range acls {
+		if types.SafeString(acl.NetworkAclId) != networkACLID {
+			continue
+		}
+		evaluation := &vpcNetworkACLEvaluation{
+			NetworkAclId:    networkACLID,
+			Direction:       direction,
+			Cidr:            cidr,
+			TrafficProtocol: protocol,
+			Port:            port,
+			Decision:        ec2.RuleActionDeny,
+			NetworkAcl:      acl,
+			Entry:           &ec2.NetworkAclEntry{},
+		}
+		if entry := evaluateNetworkACL(acl, direction == "egress", traffic, peer); entry != nil {
+			evaluation.Entry = entry
+			evaluation.Decision = types.SafeString(entry.RuleAction)
+		}
+		d.StreamListItem(ctx, evaluation)
+	}
+
+	return nil, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+func vpcNetworkACLEvaluationTitle(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	evaluation := d.HydrateItem.(*vpcNetworkACLEvaluation)
+	return fmt.Sprintf("%s_%s_%s", evaluation.NetworkAclId, evaluation.Direction, evaluation.Decision), nil
+}
@@ -0,0 +1,172 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+// defaultNetworkACLRuleNumber is the rule number of the entry which denies any
+// traffic no other entry matches. It is shown as '*' in the console.
+const defaultNetworkACLRuleNumber = 32767
+
+func tableAwsVpcNetworkACLRule(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_vpc_network_acl_rule",
+		Description: "AWS VPC Network ACL Rule",
+		List: &plugin.ListConfig{
+			ParentHydrate: listVpcNetworkACLs,
+			Hydrate:       listVpcNetworkACLRules,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns(append([]*plugin.Column{
+			{
+				Name:        "network_acl_id",
+				Description: "The ID of the network ACL.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("NetworkAcl.NetworkAclId"),
+			},
+			{
+				Name:        "vpc_id",
+				Description: "The ID of the VPC of the network ACL.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("NetworkAcl.VpcId"),
+			},
+		}, append(vpcNetworkACLEntryColumns,
+			// Standard columns
+			&plugin.Column{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(vpcNetworkACLRuleTitle),
+			},
+		)...)),
+	}
+}
+
+// vpcNetworkACLEntryColumns are the columns of a network ACL entry, shared by
+// the rule and evaluation tables
+var vpcNetworkACLEntryColumns = []*plugin.Column{
+	{
+		Name:        "rule_number",
+		Description: "The rule number of the entry. Entries are evaluated in order of rule number, and the first match decides. Rule 32767 is the default entry, shown as '*'.",
+		Type:        proto.ColumnType_INT,
+		Transform:   transform.FromField("Entry.RuleNumber"),
+	},
+	{
+		Name:        "egress",
+		Description: "True for egress rules, which apply to traffic leaving the subnet, and false for ingress rules.",
+		Type:        proto.ColumnType_BOOL,
+		Transform:   transform.FromField("Entry.Egress"),
+	},
+	{
+		Name:        "rule_action",
+		Description: "Whether the entry allows or denies matching traffic (allow | deny).",
+		Type:        proto.ColumnType_STRING,
+		Transform:   transform.FromField("Entry.RuleAction"),
+	},
+	{
+		Name:        "protocol",
+		Description: "The protocol number of the entry. -1 means all protocols.",
+		Type:        proto.ColumnType_STRING,
+		Transform:   transform.FromField("Entry.Protocol"),
+	},
+	{
+		Name:        "protocol_name",
+		Description: "The name of the protocol of the entry, e.g. tcp, or all for all protocols.",
+		Type:        proto.ColumnType_STRING,
+		Transform:   transform.FromField("Entry.Protocol").Transform(networkACLProtocolName),
+	},
+	{
+		Name:        "from_port",
+		Description: "The first port of the range, for TCP and UDP entries.",
+		Type:        proto.ColumnType_INT,
+		Transform:   transform.FromField("Entry.PortRange.From"),
+	},
+	{
+		Name:        "to_port",
+		Description: "The last port of the range, for TCP and UDP entries.",
+		Type:        proto.ColumnType_INT,
+		Transform:   transform.FromField("Entry.PortRange.To"),
+	},
+	{
+		Name:        "icmp_type",
+		Description: "The ICMP type, for ICMP entries. -1 means all types.",
+		Type:        proto.ColumnType_INT,
+		Transform:   transform.FromField("Entry.IcmpTypeCode.Type"),
+	},
+	{
+		Name:        "icmp_code",
+		Description: "The ICMP code, for ICMP entries. -1 means all codes.",
+		Type:        proto.ColumnType_INT,
+		Transform:   transform.FromField("Entry.IcmpTypeCode.Code"),
+	},
+	{
+		Name:        "cidr_block",
+		Description: "The IPv4 network range the entry applies to.",
+		Type:        proto.ColumnType_CIDR,
+		Transform:   transform.FromField("Entry.CidrBlock"),
+	},
+	{
+		Name:        "ipv6_cidr_block",
+		Description: "The IPv6 network range the entry applies to.",
+		Type:        proto.ColumnType_CIDR,
+		Transform:   transform.FromField("Entry.Ipv6CidrBlock"),
+	},
+	{
+		Name:        "is_default",
+		Description: "True for the default entry, which denies any traffic no other entry matches.",
+		Type:        proto.ColumnType_BOOL,
+		Transform:   transform.FromField("Entry.RuleNumber").Transform(isDefaultNetworkACLRule),
+	},
+}
+
+type vpcNetworkACLRule struct {
+	NetworkAcl *ec2.NetworkAcl
+	Entry      *ec2.NetworkAclEntry
+}
+
+//// LIST FUNCTION
+
+func listVpcNetworkACLRules(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("listVpcNetworkACLRules")
+	networkACL := h.Item.(*ec2.NetworkAcl)
+
+	for _, entry := range networkACL.Entries {
+		d.StreamLeafListItem(ctx, &vpcNetworkACLRule{networkACL, entry})
+	}
+
+	return nil, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+func networkACLProtocolName(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	protocol := types.SafeString(d.Value)
+	if protocol == "" {
+		return nil, nil
+	}
+	return ipProtocolName(protocol), nil
+}
+
+func isDefaultNetworkACLRule(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	ruleNumber, err := types.ToInt64(d.Value)
+	if d.Value == nil || err != nil {
+		return nil, nil
+	}
+	return ruleNumber == defaultNetworkACLRuleNumber, nil
+}
+
+func vpcNetworkACLRuleTitle(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	rule := d.HydrateItem.(*vpcNetworkACLRule)
+	direction := "ingress"
+	if types.BoolValue(rule.Entry.Egress) {
+		direction = "egress"
+	}
+	return fmt.Sprintf("%s_%s_%d", types.SafeString(rule.NetworkAcl.NetworkAclId), direction, types.Int64Value(rule.Entry.RuleNumber)), nil
+}
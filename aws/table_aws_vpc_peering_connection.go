@@ -0,0 +1,294 @@
+package aws
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+func tableAwsVpcPeeringConnection(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_vpc_peering_connection",
+		Description: "AWS VPC Peering Connection",
+		Get: &plugin.GetConfig{
+			KeyColumns:        plugin.SingleColumn("id"),
+			ShouldIgnoreError: isNotFoundError([]string{"InvalidVpcPeeringConnectionID.NotFound", "InvalidVpcPeeringConnectionID.Malformed"}),
+			Hydrate:           getVpcPeeringConnection,
+		},
+		List: &plugin.ListConfig{
+			Hydrate: listVpcPeeringConnections,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "id",
+				Description: "The ID of the VPC peering connection.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("VpcPeeringConnectionId"),
+			},
+			{
+				Name:        "status_code",
+				Description: "The status of the VPC peering connection (initiating-request | pending-acceptance | active | deleted | rejected | failed | expired | provisioning | deleting).",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Status.Code"),
+			},
+			{
+				Name:        "status_message",
+				Description: "A message that provides more information about the status, if applicable.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Status.Message"),
+			},
+			{
+				Name:        "expiration_time",
+				Description: "The time that an unaccepted VPC peering connection will expire.",
+				Type:        proto.ColumnType_TIMESTAMP,
+			},
+			{
+				Name:        "requester_vpc_id",
+				Description: "The ID of the requester VPC.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("RequesterVpcInfo.VpcId"),
+			},
+			{
+				Name:        "requester_owner_id",
+				Description: "The ID of the AWS account that owns the requester VPC.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("RequesterVpcInfo.OwnerId"),
+			},
+			{
+				Name:        "requester_region",
+				Description: "The region of the requester VPC.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("RequesterVpcInfo.Region"),
+			},
+			{
+				Name:        "requester_cidr_block",
+				Description: "The primary IPv4 CIDR block of the requester VPC.",
+				Type:        proto.ColumnType_CIDR,
+				Transform:   transform.FromField("RequesterVpcInfo.CidrBlock"),
+			},
+			{
+				Name:        "requester_cidr_block_set",
+				Description: "The IPv4 CIDR blocks of the requester VPC.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("RequesterVpcInfo.CidrBlockSet"),
+			},
+			{
+				Name:        "requester_ipv6_cidr_block_set",
+				Description: "The IPv6 CIDR blocks of the requester VPC.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("RequesterVpcInfo.Ipv6CidrBlockSet"),
+			},
+			{
+				Name:        "requester_allow_dns_resolution_from_remote_vpc",
+				Description: "Indicates whether the requester VPC can resolve public DNS hostnames of the accepter VPC to private IP addresses.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("RequesterVpcInfo.PeeringOptions.AllowDnsResolutionFromRemoteVpc"),
+			},
+			{
+				Name:        "requester_allow_egress_from_local_classic_link_to_remote_vpc",
+				Description: "Indicates whether a local ClassicLink connection of the requester can communicate with the accepter VPC.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("RequesterVpcInfo.PeeringOptions.AllowEgressFromLocalClassicLinkToRemoteVpc"),
+			},
+			{
+				Name:        "requester_allow_egress_from_local_vpc_to_remote_classic_link",
+				Description: "Indicates whether the requester VPC can communicate with a ClassicLink connection of the accepter.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("RequesterVpcInfo.PeeringOptions.AllowEgressFromLocalVpcToRemoteClassicLink"),
+			},
+			{
+				Name:        "accepter_vpc_id",
+				Description: "The ID of the accepter VPC.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("AccepterVpcInfo.VpcId"),
+			},
+			{
+				Name:        "accepter_owner_id",
+				Description: "The ID of the AWS account that owns the accepter VPC.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("AccepterVpcInfo.OwnerId"),
+			},
+			{
+				Name:        "accepter_region",
+				Description: "The region of the accepter VPC.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("AccepterVpcInfo.Region"),
+			},
+			{
+				Name:        "accepter_cidr_block",
+				Description: "The primary IPv4 CIDR block of the accepter VPC.",
+				Type:        proto.ColumnType_CIDR,
+				Transform:   transform.FromField("AccepterVpcInfo.CidrBlock"),
+			},
+			{
+				Name:        "accepter_cidr_block_set",
+				Description: "The IPv4 CIDR blocks of the accepter VPC.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("AccepterVpcInfo.CidrBlockSet"),
+			},
+			{
+				Name:        "accepter_ipv6_cidr_block_set",
+				Description: "The IPv6 CIDR blocks of the accepter VPC.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("AccepterVpcInfo.Ipv6CidrBlockSet"),
+			},
+			{
+				Name:        "accepter_allow_dns_resolution_from_remote_vpc",
+				Description: "Indicates whether the accepter VPC can resolve public DNS hostnames of the requester VPC to private IP addresses.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("AccepterVpcInfo.PeeringOptions.AllowDnsResolutionFromRemoteVpc"),
+			},
+			{
+				Name:        "accepter_allow_egress_from_local_classic_link_to_remote_vpc",
+				Description: "Indicates whether a local ClassicLink connection of the accepter can communicate with the requester VPC.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("AccepterVpcInfo.PeeringOptions.AllowEgressFromLocalClassicLinkToRemoteVpc"),
+			},
+			{
+				Name:        "accepter_allow_egress_from_local_vpc_to_remote_classic_link",
+				Description: "Indicates whether the accepter VPC can communicate with a ClassicLink connection of the requester.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("AccepterVpcInfo.PeeringOptions.AllowEgressFromLocalVpcToRemoteClassicLink"),
+			},
+			{
+				Name:        "tags_src",
+				Description: "A list of tags that are attached to the VPC peering connection.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Tags"),
+			},
+			{
+				Name:        "tags",
+				Description: resourceInterfaceDescription("tags"),
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromP(getVpcPeeringConnectionTurbotData, "Tags"),
+			},
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromP(getVpcPeeringConnectionTurbotData, "Title"),
+			},
+			{
+				Name:        "akas",
+				Description: resourceInterfaceDescription("akas"),
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getVpcPeeringConnectionTurbotAkas,
+				Transform:   transform.FromValue(),
+			},
+		}),
+	}
+}
+
+//// LIST FUNCTION
+
+func listVpcPeeringConnections(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	plugin.Logger(ctx).Trace("listVpcPeeringConnections", "AWS_REGION", region)
+
+	// Create session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	// List call
+	err = svc.DescribeVpcPeeringConnectionsPages(
+		&ec2.DescribeVpcPeeringConnectionsInput{},
+		func(page *ec2.DescribeVpcPeeringConnectionsOutput, isLast bool) bool {
+			for _, connection := range page.VpcPeeringConnections {
+				d.StreamListItem(ctx, connection)
+			}
+			return !isLast
+		},
+	)
+
+	return nil, err
+}
+
+//// HYDRATE FUNCTIONS
+
+func getVpcPeeringConnection(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getVpcPeeringConnection")
+
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	connectionID := d.KeyColumnQuals["id"].GetStringValue()
+
+	// Create session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the params
+	params := &ec2.DescribeVpcPeeringConnectionsInput{
+		VpcPeeringConnectionIds: []*string{aws.String(connectionID)},
+	}
+
+	// Get call
+	op, err := svc.DescribeVpcPeeringConnections(params)
+	if err != nil {
+		plugin.Logger(ctx).Debug("getVpcPeeringConnection__", "ERROR", err)
+		return nil, err
+	}
+
+	if op.VpcPeeringConnections != nil && len(op.VpcPeeringConnections) > 0 {
+		return op.VpcPeeringConnections[0], nil
+	}
+	return nil, nil
+}
+
+func getVpcPeeringConnectionTurbotAkas(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getVpcPeeringConnectionTurbotAkas")
+	connection := h.Item.(*ec2.VpcPeeringConnection)
+	commonData, err := getCommonColumns(ctx, d, h)
+	if err != nil {
+		return nil, err
+	}
+	commonColumnData := commonData.(*awsCommonColumnData)
+
+	// Get data for turbot defined properties
+	akas := []string{"arn:" + commonColumnData.Partition + ":ec2:" + commonColumnData.Region + ":" + commonColumnData.AccountId + ":vpc-peering-connection/" + *connection.VpcPeeringConnectionId}
+
+	return akas, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+func getVpcPeeringConnectionTurbotData(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	connection := d.HydrateItem.(*ec2.VpcPeeringConnection)
+	param := d.Param.(string)
+
+	// Get resource title
+	title := connection.VpcPeeringConnectionId
+
+	// Get the resource tags
+	var turbotTagsMap map[string]string
+	if connection.Tags != nil {
+		turbotTagsMap = map[string]string{}
+		for _, i := range connection.Tags {
+			turbotTagsMap[*i.Key] = *i.Value
+			if *i.Key == "Name" {
+				title = i.Value
+			}
+		}
+	}
+
+	if param == "Tags" {
+		return turbotTagsMap, nil
+	}
+
+	return title, nil
+}
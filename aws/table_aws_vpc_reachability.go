@@ -0,0 +1,728 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+// The status of a hop of a reachability path
+const (
+	reachabilityPass    = "pass"
+	reachabilityBlocked = "blocked"
+	reachabilityUnknown = "unknown"
+)
+
+// reachabilityMaxRoutes limits how many route tables a path may go through,
+// e.g. a subnet's route table and then the route table of a NAT gateway's subnet
+const reachabilityMaxRoutes = 4
+
+//// TABLE DEFINITION
+
+func tableAwsVpcReachability(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:             "aws_vpc_reachability",
+		Description:      "AWS VPC Reachability",
+		DefaultTransform: transform.FromGo(),
+		List: &plugin.ListConfig{
+			KeyColumns: plugin.AllColumns([]string{"source", "destination"}),
+			Hydrate:    listVpcReachability,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "source",
+				Description: "The ID of the network interface, instance or subnet the traffic comes from.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "destination",
+				Description: "Where the traffic goes: an IP address or CIDR, 'internet', or the ID of a network interface, instance or subnet.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "protocol",
+				Description: "The protocol of the traffic (tcp | udp | icmp | icmpv6 | -1), or a protocol number. Defaults to tcp.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "port",
+				Description: "The destination port of the traffic, or the ICMP type. Required for tcp and udp.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "hop_index",
+				Description: "The position of the hop in the path, starting at 0.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "component_type",
+				Description: "The type of the component the traffic goes through, e.g. security_group, network_acl, route_table or nat_gateway.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "component_id",
+				Description: "The ID of the component.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "direction",
+				Description: "For security groups and network ACLs, whether the egress or ingress rules are evaluated.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Direction").Transform(transform.NullIfZeroValue),
+			},
+			{
+				Name:        "status",
+				Description: "Whether the component lets the traffic through (pass | blocked | unknown).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "detail",
+				Description: "How the component handles the traffic, e.g. the rule or route which applies.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "is_reachable",
+				Description: "True if the traffic reaches the destination. Null if the path leaves the region or account, goes through a component which is not analysed, or a network ACL only allows return traffic to part of the ephemeral port range.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "blocking_component_id",
+				Description: "The ID of the first component which blocks the traffic.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "blocking_component_type",
+				Description: "The type of the first component which blocks the traffic.",
+				Type:        proto.ColumnType_STRING,
+			},
+
+			// Standard columns
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(vpcReachabilityTitle),
+			},
+		}),
+	}
+}
+
+type vpcReachabilityHop struct {
+	ComponentType string
+	ComponentId   string
+	Direction     string
+	Status        string
+	Detail        string
+}
+
+type vpcReachabilityRow struct {
+	Source                string
+	Destination           string
+	Protocol              string
+	Port                  *int64
+	HopIndex              int
+	IsReachable           *bool
+	BlockingComponentId   *string
+	BlockingComponentType *string
+	ComponentType         string
+	ComponentId           string
+	Direction             string
+	Status                string
+	Detail                string
+}
+
+//// LIST FUNCTION
+
+func listVpcReachability(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	plugin.Logger(ctx).Trace("listVpcReachability", "AWS_REGION", region)
+
+	source := d.KeyColumnQuals["source"].GetStringValue()
+	destination := d.KeyColumnQuals["destination"].GetStringValue()
+
+	protocol := getOptionalStringQual(d, "protocol")
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	var port *int64
+	if quals, ok := d.QueryContext.Quals["port"]; ok {
+		for _, qual := range quals.Quals {
+			if qual.GetStringValue() == "=" && qual.Value != nil {
+				value := qual.Value.GetInt64Value()
+				port = &value
+			}
+		}
+	}
+
+	traffic := networkTraffic{Protocol: ipProtocolNumber(protocol), Port: -1}
+	if port != nil {
+		traffic.Port = *port
+	} else if traffic.Protocol == "6" || traffic.Protocol == "17" {
+		return nil, fmt.Errorf("reachability of %s traffic requires a port", protocol)
+	}
+
+	network, err := loadVpcNetwork(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis, err := network.reachability(source, destination, traffic)
+	if err != nil {
+		return nil, err
+	}
+	// The source is in another region
+	if analysis == nil {
+		return nil, nil
+	}
+
+	var blocking *vpcReachabilityHop
+	for _, hop := range analysis.Hops {
+		if hop.Status == reachabilityBlocked {
+			blocking = hop
+			break
+		}
+	}
+
+	for i, hop := range analysis.Hops {
+		row := &vpcReachabilityRow{
+			Source:        source,
+			Destination:   destination,
+			Protocol:      protocol,
+			Port:          port,
+			HopIndex:      i,
+			IsReachable:   analysis.IsReachable,
+			ComponentType: hop.ComponentType,
+			ComponentId:   hop.ComponentId,
+			Direction:     hop.Direction,
+			Status:        hop.Status,
+			Detail:        hop.Detail,
+		}
+		if blocking != nil {
+			row.BlockingComponentId = &blocking.ComponentId
+			row.BlockingComponentType = &blocking.ComponentType
+		}
+		d.StreamListItem(ctx, row)
+	}
+
+	return nil, nil
+}
+
+//// UTILITY FUNCTIONS
+
+// reachabilityEndpoint is the source or destination of the traffic
+type reachabilityEndpoint struct {
+	ID string
+	// The network interface, for instances and network interfaces
+	Interface *ec2.NetworkInterface
+	Subnet    *ec2.Subnet
+	VpcID     string
+	Address   *net.IPNet
+	GroupIds  []string
+	// For sources, whether the internet can see the address of the source, i.e.
+	// it has a public IPv4 address or the traffic is IPv6
+	HasPublicAddress bool
+}
+
+// vpcReachabilityAnalysis walks the path of traffic from a source to a
+// destination, and stops at the first component which blocks the traffic
+type vpcReachabilityAnalysis struct {
+	network     *vpcNetwork
+	traffic     networkTraffic
+	ipv6        bool
+	destination *reachabilityEndpoint
+	// The address and groups the destination sees the traffic come from, which
+	// change when the traffic goes through a NAT gateway
+	sourceAddress  *net.IPNet
+	sourceGroupIds []string
+
+	Hops        []*vpcReachabilityHop
+	IsReachable *bool
+	err         error
+}
+
+// reachability returns the path of traffic from the source to the destination,
+// or nil if the source is not in the region
+func (network *vpcNetwork) reachability(sourceID string, destinationID string, traffic networkTraffic) (*vpcReachabilityAnalysis, error) {
+	var destination *reachabilityEndpoint
+	if destinationID == "internet" {
+		_, cidr, _ := net.ParseCIDR("0.0.0.0/0")
+		destination = &reachabilityEndpoint{ID: destinationID, Address: cidr}
+	} else if address, err := parseNetworkAddress(destinationID); err == nil {
+		destination = &reachabilityEndpoint{ID: destinationID, Address: address}
+	}
+	ipv6 := destination != nil && destination.Address.IP.To4() == nil
+
+	source, err := network.resolveEndpoint(sourceID, ipv6)
+	if err != nil || source == nil {
+		return nil, err
+	}
+
+	if destination == nil {
+		if destination, err = network.resolveEndpoint(destinationID, false); err != nil {
+			return nil, err
+		}
+		if destination == nil {
+			return nil, fmt.Errorf("destination %s is not an IP address, CIDR, 'internet' or a network interface, instance or subnet in the same region as the source", destinationID)
+		}
+	}
+
+	analysis := &vpcReachabilityAnalysis{
+		network:        network,
+		traffic:        traffic,
+		ipv6:           ipv6,
+		destination:    destination,
+		sourceAddress:  source.Address,
+		sourceGroupIds: source.GroupIds,
+	}
+	analysis.run(source)
+	return analysis, analysis.err
+}
+
+// resolveEndpoint finds a network interface, instance or subnet in the network
+func (network *vpcNetwork) resolveEndpoint(id string, ipv6 bool) (*reachabilityEndpoint, error) {
+	var eni *ec2.NetworkInterface
+	switch {
+	case strings.HasPrefix(id, "eni-"):
+		for _, candidate := range network.NetworkInterfaces {
+			if types.SafeString(candidate.NetworkInterfaceId) == id {
+				eni = candidate
+			}
+		}
+	case strings.HasPrefix(id, "i-"):
+		// Use the primary network interface of the instance
+		for _, candidate := range network.NetworkInterfaces {
+			if candidate.Attachment == nil || types.SafeString(candidate.Attachment.InstanceId) != id {
+				continue
+			}
+			if eni == nil || types.Int64Value(candidate.Attachment.DeviceIndex) < types.Int64Value(eni.Attachment.DeviceIndex) {
+				eni = candidate
+			}
+		}
+	case strings.HasPrefix(id, "subnet-"):
+		subnet := network.subnet(id)
+		if subnet == nil {
+			return nil, nil
+		}
+		endpoint := &reachabilityEndpoint{
+			ID:               id,
+			Subnet:           subnet,
+			VpcID:            types.SafeString(subnet.VpcId),
+			HasPublicAddress: ipv6 || types.BoolValue(subnet.MapPublicIpOnLaunch),
+		}
+		for _, block := range subnetCidrBlocks(subnet) {
+			if cidr, err := parseNetworkAddress(block); err == nil && (cidr.IP.To4() == nil) == ipv6 {
+				endpoint.Address = cidr
+				return endpoint, nil
+			}
+		}
+		return nil, fmt.Errorf("subnet %s has no CIDR block of the IP version of the destination", id)
+	default:
+		return nil, fmt.Errorf("%s is not a network interface, instance or subnet", id)
+	}
+	if eni == nil {
+		return nil, nil
+	}
+
+	endpoint := &reachabilityEndpoint{
+		ID:        id,
+		Interface: eni,
+		Subnet:    network.subnet(types.SafeString(eni.SubnetId)),
+		VpcID:     types.SafeString(eni.VpcId),
+		GroupIds:  networkInterfaceGroupIds(eni),
+	}
+	private, public, ipv6Addresses := networkInterfaceAddresses(eni)
+	addresses := private
+	endpoint.HasPublicAddress = len(public) > 0
+	if ipv6 {
+		addresses = ipv6Addresses
+		endpoint.HasPublicAddress = true
+	}
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("network interface %s has no address of the IP version of the destination", types.SafeString(eni.NetworkInterfaceId))
+	}
+	address, err := parseNetworkAddress(addresses[0])
+	if err != nil {
+		return nil, err
+	}
+	endpoint.Address = address
+	return endpoint, nil
+}
+
+func (analysis *vpcReachabilityAnalysis) run(source *reachabilityEndpoint) {
+	componentType := "network_interface"
+	if source.Interface == nil {
+		componentType = "subnet"
+	}
+	analysis.hop(componentType, source.ID, "", reachabilityPass, "traffic from "+source.Address.String())
+
+	if source.Interface != nil && !analysis.checkSecurityGroups(source, true, analysis.destination) {
+		return
+	}
+	if source.Subnet == nil {
+		analysis.stop("subnet", types.SafeString(source.Interface.SubnetId), reachabilityUnknown, "the subnet of the source was not found")
+		return
+	}
+	// Traffic within a subnet does not go through its network ACL
+	if !analysis.inSubnet(source.Subnet) && !analysis.checkNetworkACL(source.Subnet, true, analysis.destination.Address) {
+		return
+	}
+	analysis.route(source.Subnet, source.HasPublicAddress, 0)
+}
+
+// hop records a hop of the path, and returns false if it blocks the traffic
+func (analysis *vpcReachabilityAnalysis) hop(componentType string, componentID string, direction string, status string, detail string) bool {
+	analysis.Hops = append(analysis.Hops, &vpcReachabilityHop{
+		ComponentType: componentType,
+		ComponentId:   componentID,
+		Direction:     direction,
+		Status:        status,
+		Detail:        detail,
+	})
+	if status == reachabilityBlocked {
+		reachable := false
+		analysis.IsReachable = &reachable
+		return false
+	}
+	return true
+}
+
+// stop records the last hop of the path. The destination is reached if every
+// hop passes, and may or may not be reached if the status of a hop is unknown.
+func (analysis *vpcReachabilityAnalysis) stop(componentType string, componentID string, status string, detail string) {
+	if analysis.hop(componentType, componentID, "", status, detail) && status == reachabilityPass {
+		for _, hop := range analysis.Hops {
+			if hop.Status == reachabilityUnknown {
+				return
+			}
+		}
+		reachable := true
+		analysis.IsReachable = &reachable
+	}
+}
+
+func (analysis *vpcReachabilityAnalysis) inSubnet(subnet *ec2.Subnet) bool {
+	destination := analysis.destination
+	if destination.Subnet != nil {
+		return types.SafeString(destination.Subnet.SubnetId) == types.SafeString(subnet.SubnetId)
+	}
+	for _, block := range subnetCidrBlocks(subnet) {
+		if cidrContains(block, destination.Address) {
+			return true
+		}
+	}
+	return false
+}
+
+func (analysis *vpcReachabilityAnalysis) checkSecurityGroups(endpoint *reachabilityEndpoint, egress bool, peer *reachabilityEndpoint) bool {
+	direction := "ingress"
+	if egress {
+		direction = "egress"
+	}
+	for _, group := range analysis.network.securityGroups(endpoint.GroupIds) {
+		if securityGroupAllows(group, egress, analysis.traffic, peer.Address, peer.GroupIds, analysis.network.PrefixLists) {
+			return analysis.hop("security_group", types.SafeString(group.GroupId), direction, reachabilityPass, "a rule allows the traffic")
+		}
+	}
+	return analysis.hop("security_group", strings.Join(endpoint.GroupIds, ","), direction, reachabilityBlocked, "no "+direction+" rule of the security groups allows the traffic")
+}
+
+func (analysis *vpcReachabilityAnalysis) checkNetworkACL(subnet *ec2.Subnet, egress bool, peer *net.IPNet) bool {
+	direction := "ingress"
+	if egress {
+		direction = "egress"
+	}
+	acl := analysis.network.subnetNetworkACL(subnet)
+	if acl == nil {
+		return analysis.hop("network_acl", "", direction, reachabilityUnknown, "no network ACL is associated with "+types.SafeString(subnet.SubnetId))
+	}
+	aclID := types.SafeString(acl.NetworkAclId)
+
+	entry := evaluateNetworkACL(acl, egress, analysis.traffic, peer)
+	if entry == nil {
+		return analysis.hop("network_acl", aclID, direction, reachabilityBlocked, "no entry matches the traffic")
+	}
+	detail := fmt.Sprintf("rule %d %ss the traffic", types.Int64Value(entry.RuleNumber), types.SafeString(entry.RuleAction))
+	if types.SafeString(entry.RuleAction) != ec2.RuleActionAllow {
+		return analysis.hop("network_acl", aclID, direction, reachabilityBlocked, detail)
+	}
+	if !analysis.hop("network_acl", aclID, direction, reachabilityPass, detail) {
+		return false
+	}
+	return analysis.checkNetworkACLReturn(acl, !egress, peer)
+}
+
+// checkNetworkACLReturn checks that a network ACL, which is stateless, lets the
+// return traffic of a connection through in the opposite direction
+func (analysis *vpcReachabilityAnalysis) checkNetworkACLReturn(acl *ec2.NetworkAcl, egress bool, peer *net.IPNet) bool {
+	direction := "ingress"
+	if egress {
+		direction = "egress"
+	}
+	aclID := types.SafeString(acl.NetworkAclId)
+
+	if !analysis.traffic.isConnection() {
+		entry := evaluateNetworkACL(acl, egress, analysis.traffic.returnTraffic(), peer)
+		if entry == nil {
+			return analysis.hop("network_acl", aclID, direction, reachabilityBlocked, "no entry matches the return traffic")
+		}
+		detail := fmt.Sprintf("rule %d %ss the return traffic", types.Int64Value(entry.RuleNumber), types.SafeString(entry.RuleAction))
+		if types.SafeString(entry.RuleAction) != ec2.RuleActionAllow {
+			return analysis.hop("network_acl", aclID, direction, reachabilityBlocked, detail)
+		}
+		return analysis.hop("network_acl", aclID, direction, reachabilityPass, detail)
+	}
+
+	allowed := allowedEphemeralPorts(acl, egress, analysis.traffic.Protocol, peer)
+	switch {
+	case len(allowed) == 0:
+		return analysis.hop("network_acl", aclID, direction, reachabilityBlocked, fmt.Sprintf("return traffic to ephemeral ports %d-%d is denied", ephemeralPortFrom, ephemeralPortTo))
+	case len(allowed) == 1 && allowed[0] == [2]int64{ephemeralPortFrom, ephemeralPortTo}:
+		return analysis.hop("network_acl", aclID, direction, reachabilityPass, fmt.Sprintf("return traffic to ephemeral ports %d-%d is allowed", ephemeralPortFrom, ephemeralPortTo))
+	}
+	var ranges []string
+	for _, portRange := range allowed {
+		ranges = append(ranges, fmt.Sprintf("%d-%d", portRange[0], portRange[1]))
+	}
+	return analysis.hop("network_acl", aclID, direction, reachabilityUnknown, "return traffic is only allowed to ephemeral ports "+strings.Join(ranges, ", ")+", which may not include the port the connection uses")
+}
+
+// route follows the route the route table of a subnet uses for the destination
+func (analysis *vpcReachabilityAnalysis) route(subnet *ec2.Subnet, hasPublicAddress bool, depth int) {
+	if depth >= reachabilityMaxRoutes {
+		analysis.stop("route_table", "", reachabilityUnknown, "the path goes through too many route tables")
+		return
+	}
+
+	routeTable := analysis.network.subnetRouteTable(subnet)
+	if routeTable == nil {
+		analysis.stop("route_table", "", reachabilityBlocked, "no route table is associated with "+types.SafeString(subnet.SubnetId))
+		return
+	}
+	routeTableID := types.SafeString(routeTable.RouteTableId)
+
+	route := analysis.network.longestRouteMatch(routeTable, analysis.destination.Address)
+	if route == nil {
+		analysis.stop("route_table", routeTableID, reachabilityBlocked, "no route to "+analysis.destination.Address.String())
+		return
+	}
+	targetType, targetID := routeTarget(route)
+	destination := types.SafeString(route.DestinationCidrBlock) + types.SafeString(route.DestinationIpv6CidrBlock) + types.SafeString(route.DestinationPrefixListId)
+	if types.SafeString(route.State) == ec2.RouteStateBlackhole {
+		analysis.stop("route_table", routeTableID, reachabilityBlocked, fmt.Sprintf("the route %s via %s is a blackhole", destination, targetID))
+		return
+	}
+	analysis.hop("route_table", routeTableID, "", reachabilityPass, fmt.Sprintf("route %s via %s", destination, targetID))
+
+	vpcID := types.SafeString(subnet.VpcId)
+	switch targetType {
+	case "local":
+		analysis.arrive(vpcID, subnet)
+	case "internet_gateway":
+		if !hasPublicAddress {
+			analysis.stop(targetType, targetID, reachabilityBlocked, "the source has no public IPv4 address")
+			return
+		}
+		analysis.stop(targetType, targetID, reachabilityPass, "traffic leaves the VPC for the internet")
+	case "egress_only_internet_gateway":
+		if !analysis.ipv6 {
+			analysis.stop(targetType, targetID, reachabilityBlocked, "egress-only internet gateways only carry IPv6 traffic")
+			return
+		}
+		analysis.stop(targetType, targetID, reachabilityPass, "traffic leaves the VPC for the internet")
+	case "nat_gateway":
+		analysis.natGateway(targetID, subnet, depth)
+	case "vpc_peering_connection":
+		analysis.peeringConnection(targetID, vpcID)
+	case "transit_gateway":
+		analysis.transitGateway(targetID, vpcID)
+	default:
+		analysis.stop(targetType, targetID, reachabilityUnknown, "traffic is sent to "+targetID+", which is not analysed")
+	}
+}
+
+func (analysis *vpcReachabilityAnalysis) natGateway(id string, fromSubnet *ec2.Subnet, depth int) {
+	var natGateway *ec2.NatGateway
+	for _, candidate := range analysis.network.NatGateways {
+		if types.SafeString(candidate.NatGatewayId) == id {
+			natGateway = candidate
+		}
+	}
+	if natGateway == nil {
+		analysis.stop("nat_gateway", id, reachabilityUnknown, "the NAT gateway was not found")
+		return
+	}
+	if analysis.ipv6 {
+		analysis.stop("nat_gateway", id, reachabilityBlocked, "NAT gateways only carry IPv4 traffic")
+		return
+	}
+	if state := types.SafeString(natGateway.State); state != ec2.NatGatewayStateAvailable {
+		analysis.stop("nat_gateway", id, reachabilityBlocked, "the NAT gateway is "+state)
+		return
+	}
+	natSubnet := analysis.network.subnet(types.SafeString(natGateway.SubnetId))
+	if natSubnet == nil {
+		analysis.stop("nat_gateway", id, reachabilityUnknown, "the subnet of the NAT gateway was not found")
+		return
+	}
+
+	// Traffic enters the subnet of the NAT gateway, and leaves it from the
+	// address of the NAT gateway
+	if types.SafeString(natSubnet.SubnetId) != types.SafeString(fromSubnet.SubnetId) && !analysis.checkNetworkACL(natSubnet, false, analysis.sourceAddress) {
+		return
+	}
+	var hasPublicAddress bool
+	for _, address := range natGateway.NatGatewayAddresses {
+		if address.PublicIp != nil {
+			hasPublicAddress = true
+		}
+	}
+	if len(natGateway.NatGatewayAddresses) > 0 {
+		if privateAddress, err := parseNetworkAddress(types.SafeString(natGateway.NatGatewayAddresses[0].PrivateIp)); err == nil {
+			analysis.sourceAddress = privateAddress
+		}
+	}
+	analysis.sourceGroupIds = nil
+	analysis.hop("nat_gateway", id, "", reachabilityPass, "the source is translated to the address of the NAT gateway")
+
+	if !analysis.inSubnet(natSubnet) && !analysis.checkNetworkACL(natSubnet, true, analysis.destination.Address) {
+		return
+	}
+	analysis.route(natSubnet, hasPublicAddress, depth+1)
+}
+
+func (analysis *vpcReachabilityAnalysis) peeringConnection(id string, fromVpcID string) {
+	var connection *ec2.VpcPeeringConnection
+	for _, candidate := range analysis.network.PeeringConnections {
+		if types.SafeString(candidate.VpcPeeringConnectionId) == id {
+			connection = candidate
+		}
+	}
+	if connection == nil {
+		analysis.stop("vpc_peering_connection", id, reachabilityUnknown, "the peering connection was not found")
+		return
+	}
+	if connection.Status != nil && types.SafeString(connection.Status.Code) != ec2.VpcPeeringConnectionStateReasonCodeActive {
+		analysis.stop("vpc_peering_connection", id, reachabilityBlocked, "the peering connection is "+types.SafeString(connection.Status.Code))
+		return
+	}
+
+	peer := connection.AccepterVpcInfo
+	if connection.AccepterVpcInfo != nil && types.SafeString(connection.AccepterVpcInfo.VpcId) == fromVpcID {
+		peer = connection.RequesterVpcInfo
+	}
+	if peer == nil {
+		analysis.stop("vpc_peering_connection", id, reachabilityUnknown, "the peer VPC of the connection is unknown")
+		return
+	}
+	peerVpcID := types.SafeString(peer.VpcId)
+	analysis.hop("vpc_peering_connection", id, "", reachabilityPass, "to "+peerVpcID)
+
+	if analysis.network.vpc(peerVpcID) == nil {
+		analysis.stop("vpc", peerVpcID, reachabilityUnknown, "the peer VPC is in another region or account, which is not analysed")
+		return
+	}
+	if !analysis.network.vpcContains(peerVpcID, analysis.destination.Address) {
+		analysis.stop("vpc", peerVpcID, reachabilityBlocked, "the destination is not in the peer VPC, and peering connections are not transitive")
+		return
+	}
+	analysis.arrive(peerVpcID, nil)
+}
+
+func (analysis *vpcReachabilityAnalysis) transitGateway(id string, fromVpcID string) {
+	var attachment *ec2.TransitGatewayAttachment
+	for _, candidate := range analysis.network.TransitGatewayAttachments {
+		if types.SafeString(candidate.TransitGatewayId) == id && types.SafeString(candidate.ResourceType) == ec2.TransitGatewayAttachmentResourceTypeVpc && types.SafeString(candidate.ResourceId) == fromVpcID {
+			attachment = candidate
+		}
+	}
+	if attachment == nil {
+		analysis.stop("transit_gateway", id, reachabilityBlocked, fromVpcID+" is not attached to the transit gateway")
+		return
+	}
+	attachmentID := types.SafeString(attachment.TransitGatewayAttachmentId)
+	if state := types.SafeString(attachment.State); state != ec2.TransitGatewayAttachmentStateAvailable {
+		analysis.stop("transit_gateway_attachment", attachmentID, reachabilityBlocked, "the attachment is "+state)
+		return
+	}
+	if attachment.Association == nil || attachment.Association.TransitGatewayRouteTableId == nil {
+		analysis.stop("transit_gateway_attachment", attachmentID, reachabilityBlocked, "the attachment is not associated with a transit gateway route table")
+		return
+	}
+	analysis.hop("transit_gateway", id, "", reachabilityPass, "through "+attachmentID)
+
+	routeTableID := *attachment.Association.TransitGatewayRouteTableId
+	route, err := analysis.network.searchTransitGatewayRoute(routeTableID, analysis.destination.Address)
+	if err != nil {
+		analysis.err = err
+		return
+	}
+	if route == nil {
+		analysis.stop("transit_gateway_route_table", routeTableID, reachabilityBlocked, "no route to "+analysis.destination.Address.String())
+		return
+	}
+	destination := types.SafeString(route.DestinationCidrBlock) + types.SafeString(route.PrefixListId)
+	if types.SafeString(route.State) == ec2.TransitGatewayRouteStateBlackhole || len(route.TransitGatewayAttachments) == 0 {
+		analysis.stop("transit_gateway_route_table", routeTableID, reachabilityBlocked, "the route "+destination+" is a blackhole")
+		return
+	}
+
+	target := route.TransitGatewayAttachments[0]
+	targetID := types.SafeString(target.TransitGatewayAttachmentId)
+	analysis.hop("transit_gateway_route_table", routeTableID, "", reachabilityPass, fmt.Sprintf("route %s via %s", destination, targetID))
+
+	resourceID := types.SafeString(target.ResourceId)
+	if types.SafeString(target.ResourceType) != ec2.TransitGatewayAttachmentResourceTypeVpc || analysis.network.vpc(resourceID) == nil {
+		analysis.stop("transit_gateway_attachment", targetID, reachabilityUnknown, fmt.Sprintf("traffic leaves through the %s attachment to %s, which is not analysed", types.SafeString(target.ResourceType), resourceID))
+		return
+	}
+	analysis.arrive(resourceID, nil)
+}
+
+// arrive checks the network ACL and security groups of the destination, once
+// the traffic reaches its VPC
+func (analysis *vpcReachabilityAnalysis) arrive(vpcID string, fromSubnet *ec2.Subnet) {
+	destination := analysis.destination
+
+	subnet := destination.Subnet
+	if subnet == nil || destination.VpcID != vpcID {
+		subnet = analysis.network.subnetContaining(vpcID, destination.Address)
+	}
+	if subnet == nil {
+		analysis.stop("vpc", vpcID, reachabilityUnknown, "the destination is not within a single subnet of the VPC, so no network ACL was evaluated")
+		return
+	}
+	if fromSubnet == nil || types.SafeString(fromSubnet.SubnetId) != types.SafeString(subnet.SubnetId) {
+		if !analysis.checkNetworkACL(subnet, false, analysis.sourceAddress) {
+			return
+		}
+	}
+
+	if destination.Interface == nil {
+		analysis.stop("subnet", types.SafeString(subnet.SubnetId), reachabilityPass, "traffic reaches "+destination.Address.String())
+		return
+	}
+	source := &reachabilityEndpoint{Address: analysis.sourceAddress, GroupIds: analysis.sourceGroupIds}
+	if !analysis.checkSecurityGroups(destination, false, source) {
+		return
+	}
+	analysis.stop("network_interface", types.SafeString(destination.Interface.NetworkInterfaceId), reachabilityPass, "traffic reaches "+destination.Address.String())
+}
+
+//// TRANSFORM FUNCTIONS
+
+func vpcReachabilityTitle(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	row := d.HydrateItem.(*vpcReachabilityRow)
+	return fmt.Sprintf("%s - %s: %d %s", row.Source, row.Destination, row.HopIndex, row.ComponentType), nil
+}
@@ -0,0 +1,194 @@
+package aws
+
+import (
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestVpcReachability(t *testing.T) {
+	allowAll := func(ruleNumber int64, egress bool) *ec2.NetworkAclEntry {
+		return &ec2.NetworkAclEntry{RuleNumber: aws.Int64(ruleNumber), Egress: aws.Bool(egress), Protocol: aws.String("-1"), CidrBlock: aws.String("0.0.0.0/0"), RuleAction: aws.String("allow")}
+	}
+	denyAll := func(egress bool) *ec2.NetworkAclEntry {
+		return &ec2.NetworkAclEntry{RuleNumber: aws.Int64(32767), Egress: aws.Bool(egress), Protocol: aws.String("-1"), CidrBlock: aws.String("0.0.0.0/0"), RuleAction: aws.String("deny")}
+	}
+	eni := func(id string, instanceID string, subnetID string, vpcID string, privateIP string, publicIP string, groups ...string) *ec2.NetworkInterface {
+		ni := &ec2.NetworkInterface{
+			NetworkInterfaceId: aws.String(id),
+			SubnetId:           aws.String(subnetID),
+			VpcId:              aws.String(vpcID),
+			Attachment:         &ec2.NetworkInterfaceAttachment{InstanceId: aws.String(instanceID), DeviceIndex: aws.Int64(0)},
+			PrivateIpAddresses: []*ec2.NetworkInterfacePrivateIpAddress{{PrivateIpAddress: aws.String(privateIP)}},
+		}
+		if publicIP != "" {
+			ni.PrivateIpAddresses[0].Association = &ec2.NetworkInterfaceAssociation{PublicIp: aws.String(publicIP)}
+		}
+		for _, group := range groups {
+			ni.Groups = append(ni.Groups, &ec2.GroupIdentifier{GroupId: aws.String(group)})
+		}
+		return ni
+	}
+	egressAll := []*ec2.IpPermission{{IpProtocol: aws.String("-1"), IpRanges: []*ec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}}}}
+
+	network := &vpcNetwork{
+		Vpcs: []*ec2.Vpc{
+			{VpcId: aws.String("vpc-main"), CidrBlock: aws.String("10.0.0.0/16")},
+			{VpcId: aws.String("vpc-peer"), CidrBlock: aws.String("192.168.0.0/16")},
+		},
+		Subnets: []*ec2.Subnet{
+			{SubnetId: aws.String("subnet-public"), VpcId: aws.String("vpc-main"), CidrBlock: aws.String("10.0.0.0/24")},
+			{SubnetId: aws.String("subnet-private"), VpcId: aws.String("vpc-main"), CidrBlock: aws.String("10.0.1.0/24")},
+			{SubnetId: aws.String("subnet-peer"), VpcId: aws.String("vpc-peer"), CidrBlock: aws.String("192.168.0.0/24")},
+			{SubnetId: aws.String("subnet-locked"), VpcId: aws.String("vpc-main"), CidrBlock: aws.String("10.0.2.0/24")},
+			{SubnetId: aws.String("subnet-linux"), VpcId: aws.String("vpc-main"), CidrBlock: aws.String("10.0.3.0/24")},
+		},
+		RouteTables: []*ec2.RouteTable{
+			{
+				RouteTableId: aws.String("rtb-public"),
+				VpcId:        aws.String("vpc-main"),
+				Associations: []*ec2.RouteTableAssociation{{Main: aws.Bool(true)}},
+				Routes: []*ec2.Route{
+					{DestinationCidrBlock: aws.String("10.0.0.0/16"), GatewayId: aws.String("local"), State: aws.String("active")},
+					{DestinationCidrBlock: aws.String("0.0.0.0/0"), GatewayId: aws.String("igw-main"), State: aws.String("active")},
+				},
+			},
+			{
+				RouteTableId: aws.String("rtb-private"),
+				VpcId:        aws.String("vpc-main"),
+				Associations: []*ec2.RouteTableAssociation{{SubnetId: aws.String("subnet-private")}},
+				Routes: []*ec2.Route{
+					{DestinationCidrBlock: aws.String("10.0.0.0/16"), GatewayId: aws.String("local"), State: aws.String("active")},
+					{DestinationCidrBlock: aws.String("192.168.0.0/16"), VpcPeeringConnectionId: aws.String("pcx-main"), State: aws.String("active")},
+					{DestinationCidrBlock: aws.String("0.0.0.0/0"), NatGatewayId: aws.String("nat-main"), State: aws.String("active")},
+				},
+			},
+			{
+				RouteTableId: aws.String("rtb-peer"),
+				VpcId:        aws.String("vpc-peer"),
+				Associations: []*ec2.RouteTableAssociation{{Main: aws.Bool(true)}},
+				Routes: []*ec2.Route{
+					{DestinationCidrBlock: aws.String("192.168.0.0/16"), GatewayId: aws.String("local"), State: aws.String("active")},
+				},
+			},
+		},
+		NetworkAcls: []*ec2.NetworkAcl{
+			{
+				NetworkAclId: aws.String("acl-open"),
+				Associations: []*ec2.NetworkAclAssociation{{SubnetId: aws.String("subnet-public")}, {SubnetId: aws.String("subnet-peer")}},
+				Entries:      []*ec2.NetworkAclEntry{denyAll(true), allowAll(100, true), denyAll(false), allowAll(100, false)},
+			},
+			{
+				NetworkAclId: aws.String("acl-private"),
+				Associations: []*ec2.NetworkAclAssociation{{SubnetId: aws.String("subnet-private")}},
+				Entries: []*ec2.NetworkAclEntry{
+					allowAll(100, true),
+					denyAll(true),
+					allowAll(100, false),
+					{RuleNumber: aws.Int64(50), Egress: aws.Bool(false), Protocol: aws.String("6"), PortRange: &ec2.PortRange{From: aws.Int64(23), To: aws.Int64(23)}, CidrBlock: aws.String("0.0.0.0/0"), RuleAction: aws.String("deny")},
+					denyAll(false),
+				},
+			},
+			{
+				// Only allows the request direction of HTTPS
+				NetworkAclId: aws.String("acl-locked"),
+				Associations: []*ec2.NetworkAclAssociation{{SubnetId: aws.String("subnet-locked")}},
+				Entries: []*ec2.NetworkAclEntry{
+					{RuleNumber: aws.Int64(100), Egress: aws.Bool(true), Protocol: aws.String("6"), PortRange: &ec2.PortRange{From: aws.Int64(443), To: aws.Int64(443)}, CidrBlock: aws.String("0.0.0.0/0"), RuleAction: aws.String("allow")},
+					denyAll(true),
+					{RuleNumber: aws.Int64(100), Egress: aws.Bool(false), Protocol: aws.String("6"), PortRange: &ec2.PortRange{From: aws.Int64(443), To: aws.Int64(443)}, CidrBlock: aws.String("0.0.0.0/0"), RuleAction: aws.String("allow")},
+					denyAll(false),
+				},
+			},
+			{
+				// Only allows return traffic to the Linux ephemeral port range
+				NetworkAclId: aws.String("acl-linux"),
+				Associations: []*ec2.NetworkAclAssociation{{SubnetId: aws.String("subnet-linux")}},
+				Entries: []*ec2.NetworkAclEntry{
+					{RuleNumber: aws.Int64(100), Egress: aws.Bool(true), Protocol: aws.String("6"), PortRange: &ec2.PortRange{From: aws.Int64(32768), To: aws.Int64(60999)}, CidrBlock: aws.String("0.0.0.0/0"), RuleAction: aws.String("allow")},
+					denyAll(true),
+					allowAll(100, false),
+					denyAll(false),
+				},
+			},
+		},
+		SecurityGroups: []*ec2.SecurityGroup{
+			{GroupId: aws.String("sg-web"), IpPermissionsEgress: egressAll},
+			{
+				GroupId:             aws.String("sg-app"),
+				IpPermissionsEgress: egressAll,
+				IpPermissions:       []*ec2.IpPermission{{IpProtocol: aws.String("tcp"), FromPort: aws.Int64(0), ToPort: aws.Int64(65535), IpRanges: []*ec2.IpRange{{CidrIp: aws.String("10.0.0.0/16")}}}},
+			},
+			{
+				GroupId:       aws.String("sg-db"),
+				IpPermissions: []*ec2.IpPermission{{IpProtocol: aws.String("tcp"), FromPort: aws.Int64(5432), ToPort: aws.Int64(5432), UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: aws.String("sg-app")}}}},
+			},
+		},
+		NetworkInterfaces: []*ec2.NetworkInterface{
+			eni("eni-web", "i-web", "subnet-public", "vpc-main", "10.0.0.10", "203.0.113.10", "sg-web"),
+			eni("eni-bastion", "i-bastion", "subnet-public", "vpc-main", "10.0.0.11", "", "sg-web"),
+			eni("eni-app", "i-app", "subnet-private", "vpc-main", "10.0.1.10", "", "sg-app"),
+			eni("eni-db", "i-db", "subnet-private", "vpc-main", "10.0.1.20", "", "sg-db"),
+			eni("eni-peer", "i-peer", "subnet-peer", "vpc-peer", "192.168.0.10", "", "sg-app"),
+			eni("eni-locked", "i-locked", "subnet-locked", "vpc-main", "10.0.2.10", "", "sg-app"),
+			eni("eni-linux", "i-linux", "subnet-linux", "vpc-main", "10.0.3.10", "", "sg-app"),
+		},
+		NatGateways: []*ec2.NatGateway{{
+			NatGatewayId:        aws.String("nat-main"),
+			SubnetId:            aws.String("subnet-public"),
+			State:               aws.String("available"),
+			NatGatewayAddresses: []*ec2.NatGatewayAddress{{PrivateIp: aws.String("10.0.0.5"), PublicIp: aws.String("203.0.113.5")}},
+		}},
+		PeeringConnections: []*ec2.VpcPeeringConnection{{
+			VpcPeeringConnectionId: aws.String("pcx-main"),
+			Status:                 &ec2.VpcPeeringConnectionStateReason{Code: aws.String("active")},
+			RequesterVpcInfo:       &ec2.VpcPeeringConnectionVpcInfo{VpcId: aws.String("vpc-main")},
+			AccepterVpcInfo:        &ec2.VpcPeeringConnectionVpcInfo{VpcId: aws.String("vpc-peer")},
+		}},
+	}
+
+	cases := []struct {
+		name          string
+		source        string
+		destination   string
+		traffic       networkTraffic
+		reachable     *bool
+		lastComponent string
+	}{
+		{"public instance to the internet", "i-web", "internet", networkTraffic{"6", 443}, aws.Bool(true), "internet_gateway"},
+		{"instance without a public address to the internet", "eni-bastion", "internet", networkTraffic{"6", 443}, aws.Bool(false), "internet_gateway"},
+		{"private instance to the internet through a NAT gateway", "i-app", "internet", networkTraffic{"6", 443}, aws.Bool(true), "internet_gateway"},
+		{"app to database allowed by group reference", "i-app", "eni-db", networkTraffic{"6", 5432}, aws.Bool(true), "network_interface"},
+		{"app to database on a closed port", "i-app", "eni-db", networkTraffic{"6", 22}, aws.Bool(false), "security_group"},
+		{"web to app denied by network ACL", "i-web", "i-app", networkTraffic{"6", 23}, aws.Bool(false), "network_acl"},
+		{"app to peered VPC", "i-app", "eni-peer", networkTraffic{"6", 8080}, aws.Bool(true), "network_interface"},
+		{"subnet to a peered CIDR", "subnet-private", "192.168.0.0/28", networkTraffic{"6", 8080}, aws.Bool(true), "subnet"},
+		{"no route", "i-peer", "172.16.0.1", networkTraffic{"6", 443}, aws.Bool(false), "route_table"},
+		{"return traffic denied by network ACL", "i-web", "i-locked", networkTraffic{"6", 443}, aws.Bool(false), "network_acl"},
+		{"return traffic allowed to part of the ephemeral ports", "i-web", "i-linux", networkTraffic{"6", 443}, nil, "network_interface"},
+	}
+
+	for _, c := range cases {
+		analysis, err := network.reachability(c.source, c.destination, c.traffic)
+		if err != nil {
+			t.Errorf("Case '%s': unexpected error: %v", c.name, err)
+			continue
+		}
+		if analysis == nil {
+			t.Errorf("Case '%s': source not found", c.name)
+			continue
+		}
+		if (analysis.IsReachable == nil) != (c.reachable == nil) || (c.reachable != nil && *analysis.IsReachable != *c.reachable) {
+			t.Errorf("Case '%s': expected reachable %v, got %v", c.name, aws.BoolValue(c.reachable), aws.BoolValue(analysis.IsReachable))
+		}
+		last := analysis.Hops[len(analysis.Hops)-1]
+		if last.ComponentType != c.lastComponent {
+			t.Errorf("Case '%s': expected the path to end at %s, got %s (%s)", c.name, c.lastComponent, last.ComponentType, last.Detail)
+		}
+	}
+
+	if analysis, err := network.reachability("i-elsewhere", "internet", networkTraffic{"6", 443}); analysis != nil || err != nil {
+		t.Errorf("Case 'source in another region': expected no analysis, got %v, %v", analysis, err)
+	}
+}
@@ -0,0 +1,355 @@
+package aws
+
+import (
+	"context"
+	"net"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+//// TABLE DEFINITION
+
+func tableAwsVpcSecurityGroupExposure(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:             "aws_vpc_security_group_exposure",
+		Description:      "AWS VPC Security Group Exposure",
+		DefaultTransform: transform.FromGo(),
+		List: &plugin.ListConfig{
+			Hydrate: listVpcSecurityGroupExposures,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "group_id",
+				Description: "The ID of the security group.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "group_name",
+				Description: "The name of the security group.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "vpc_id",
+				Description: "The ID of the VPC of the security group.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "ip_protocol",
+				Description: "The IP protocol name (tcp, udp, icmp, icmpv6) or number of the ingress rules. -1 means all protocols.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "from_port",
+				Description: "The start of the port range, or the ICMP type. Null for all protocols.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "to_port",
+				Description: "The end of the port range, or the ICMP code. Null for all protocols.",
+				Type:        proto.ColumnType_INT,
+			},
+			{
+				Name:        "is_public_ipv4",
+				Description: "True if the ingress rules allow the port range from 0.0.0.0/0, directly or through a prefix list.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "is_public_ipv6",
+				Description: "True if the ingress rules allow the port range from ::/0, directly or through a prefix list.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "is_public",
+				Description: "True if the ingress rules allow the port range from 0.0.0.0/0 or ::/0.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "public_sources",
+				Description: "The CIDRs and prefix lists which open the port range to the world.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "source_cidrs",
+				Description: "Every CIDR the ingress rules allow, with referenced prefix lists expanded into their CIDRs and referenced security groups into the addresses of the network interfaces which use them.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "source_group_ids",
+				Description: "The security groups referenced by the ingress rules.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "source_prefix_list_ids",
+				Description: "The managed prefix lists referenced by the ingress rules.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "network_interface_ids",
+				Description: "The network interfaces which use the security group.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "instance_ids",
+				Description: "The instances attached to the network interfaces which use the security group.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "is_exposed",
+				Description: "True if the port range is open to the world and at least one network interface using the security group has a public address of the same IP version.",
+				Type:        proto.ColumnType_BOOL,
+			},
+			{
+				Name:        "exposed_network_interface_ids",
+				Description: "The network interfaces which use the security group and can be reached from the world on the port range.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "exposed_instance_ids",
+				Description: "The instances attached to the exposed network interfaces.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "exposed_addresses",
+				Description: "The public IPv4 and IPv6 addresses of the exposed network interfaces.",
+				Type:        proto.ColumnType_JSON,
+			},
+
+			// Standard columns
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(vpcSecurityGroupExposureTitle),
+			},
+		}),
+	}
+}
+
+type vpcSecurityGroupExposure struct {
+	GroupId                    *string
+	GroupName                  *string
+	VpcId                      *string
+	IpProtocol                 *string
+	FromPort                   *int64
+	ToPort                     *int64
+	IsPublicIpv4               bool
+	IsPublicIpv6               bool
+	IsPublic                   bool
+	PublicSources              []string
+	SourceCidrs                []string
+	SourceGroupIds             []string
+	SourcePrefixListIds        []string
+	NetworkInterfaceIds        []string
+	InstanceIds                []string
+	IsExposed                  bool
+	ExposedNetworkInterfaceIds []string
+	ExposedInstanceIds         []string
+	ExposedAddresses           []string
+}
+
+//// LIST FUNCTION
+
+func listVpcSecurityGroupExposures(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	plugin.Logger(ctx).Trace("listVpcSecurityGroupExposures", "AWS_REGION", region)
+
+	groups, err := getVpcSecurityGroups(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	interfaces, err := getVpcNetworkInterfaces(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	groupID := getOptionalStringQual(d, "group_id")
+	var selected []*ec2.SecurityGroup
+	for _, group := range groups {
+		if groupID == "" || groupID == types.SafeString(group.GroupId) {
+			selected = append(selected, group)
+		}
+	}
+
+	prefixLists, err := getReferencedPrefixListCidrs(ctx, d, region, selected)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, exposure := range securityGroupExposures(selected, interfaces, prefixLists) {
+		d.StreamListItem(ctx, exposure)
+	}
+
+	return nil, nil
+}
+
+//// UTILITY FUNCTIONS
+
+// securityGroupExposures returns the exposure of each protocol and port range
+// the ingress rules of the security groups allow
+func securityGroupExposures(groups []*ec2.SecurityGroup, interfaces []*ec2.NetworkInterface, prefixLists map[string][]string) []*vpcSecurityGroupExposure {
+	var exposures []*vpcSecurityGroupExposure
+	for _, group := range groups {
+		var users []*ec2.NetworkInterface
+		for _, eni := range interfaces {
+			if helpers.StringSliceContains(networkInterfaceGroupIds(eni), types.SafeString(group.GroupId)) {
+				users = append(users, eni)
+			}
+		}
+
+		// Rules for the same protocol and port range are combined
+		byPortRange := map[string]*vpcSecurityGroupExposure{}
+		for _, permission := range group.IpPermissions {
+			key := securityGroupPortRangeKey(permission)
+			exposure, ok := byPortRange[key]
+			if !ok {
+				exposure = &vpcSecurityGroupExposure{
+					GroupId:    group.GroupId,
+					GroupName:  group.GroupName,
+					VpcId:      group.VpcId,
+					IpProtocol: permission.IpProtocol,
+					FromPort:   permission.FromPort,
+					ToPort:     permission.ToPort,
+				}
+				byPortRange[key] = exposure
+				exposures = append(exposures, exposure)
+			}
+			exposure.addPermission(permission, interfaces, prefixLists)
+		}
+
+		for _, exposure := range byPortRange {
+			exposure.addNetworkInterfaces(users)
+		}
+	}
+	return exposures
+}
+
+// addPermission adds the sources of an ingress rule to the exposure
+func (exposure *vpcSecurityGroupExposure) addPermission(permission *ec2.IpPermission, interfaces []*ec2.NetworkInterface, prefixLists map[string][]string) {
+	addCidr := func(cidr string, source string) {
+		exposure.SourceCidrs = append(exposure.SourceCidrs, cidr)
+		switch cidr {
+		case "0.0.0.0/0":
+			exposure.IsPublicIpv4 = true
+		case "::/0":
+			exposure.IsPublicIpv6 = true
+		default:
+			return
+		}
+		exposure.IsPublic = true
+		exposure.PublicSources = append(exposure.PublicSources, source)
+	}
+
+	for _, r := range permission.IpRanges {
+		addCidr(types.SafeString(r.CidrIp), types.SafeString(r.CidrIp))
+	}
+	for _, r := range permission.Ipv6Ranges {
+		addCidr(types.SafeString(r.CidrIpv6), types.SafeString(r.CidrIpv6))
+	}
+	for _, r := range permission.PrefixListIds {
+		id := types.SafeString(r.PrefixListId)
+		exposure.SourcePrefixListIds = append(exposure.SourcePrefixListIds, id)
+		for _, cidr := range prefixLists[id] {
+			addCidr(cidr, id)
+		}
+	}
+
+	// Referenced security groups allow the private addresses of the network
+	// interfaces which use them
+	for _, r := range permission.UserIdGroupPairs {
+		id := types.SafeString(r.GroupId)
+		exposure.SourceGroupIds = append(exposure.SourceGroupIds, id)
+		for _, eni := range interfaces {
+			if !helpers.StringSliceContains(networkInterfaceGroupIds(eni), id) {
+				continue
+			}
+			private, _, ipv6 := networkInterfaceAddresses(eni)
+			for _, address := range private {
+				exposure.SourceCidrs = append(exposure.SourceCidrs, address+"/32")
+			}
+			for _, address := range ipv6 {
+				exposure.SourceCidrs = append(exposure.SourceCidrs, address+"/128")
+			}
+		}
+	}
+
+	exposure.PublicSources = sortedUniqueStrings(exposure.PublicSources)
+	exposure.SourceCidrs = sortedUniqueStrings(exposure.SourceCidrs)
+	exposure.SourceGroupIds = sortedUniqueStrings(exposure.SourceGroupIds)
+	exposure.SourcePrefixListIds = sortedUniqueStrings(exposure.SourcePrefixListIds)
+}
+
+// addNetworkInterfaces records the network interfaces which use the security
+// group, and which of them the world can reach
+func (exposure *vpcSecurityGroupExposure) addNetworkInterfaces(interfaces []*ec2.NetworkInterface) {
+	for _, eni := range interfaces {
+		id := types.SafeString(eni.NetworkInterfaceId)
+		var instanceID string
+		if eni.Attachment != nil {
+			instanceID = types.SafeString(eni.Attachment.InstanceId)
+		}
+		exposure.NetworkInterfaceIds = append(exposure.NetworkInterfaceIds, id)
+		if instanceID != "" {
+			exposure.InstanceIds = append(exposure.InstanceIds, instanceID)
+		}
+
+		var addresses []string
+		_, public, ipv6 := networkInterfaceAddresses(eni)
+		if exposure.IsPublicIpv4 {
+			addresses = append(addresses, public...)
+		}
+		if exposure.IsPublicIpv6 {
+			for _, address := range ipv6 {
+				// Only global unicast addresses are routable from the internet
+				if ip := net.ParseIP(address); ip != nil && ip.IsGlobalUnicast() {
+					addresses = append(addresses, address)
+				}
+			}
+		}
+		if len(addresses) == 0 {
+			continue
+		}
+		exposure.IsExposed = true
+		exposure.ExposedNetworkInterfaceIds = append(exposure.ExposedNetworkInterfaceIds, id)
+		if instanceID != "" {
+			exposure.ExposedInstanceIds = append(exposure.ExposedInstanceIds, instanceID)
+		}
+		exposure.ExposedAddresses = append(exposure.ExposedAddresses, addresses...)
+	}
+
+	exposure.NetworkInterfaceIds = sortedUniqueStrings(exposure.NetworkInterfaceIds)
+	exposure.InstanceIds = sortedUniqueStrings(exposure.InstanceIds)
+	exposure.ExposedNetworkInterfaceIds = sortedUniqueStrings(exposure.ExposedNetworkInterfaceIds)
+	exposure.ExposedInstanceIds = sortedUniqueStrings(exposure.ExposedInstanceIds)
+	exposure.ExposedAddresses = sortedUniqueStrings(exposure.ExposedAddresses)
+}
+
+// securityGroupPortRangeKey identifies the protocol and port range of a rule
+func securityGroupPortRangeKey(permission *ec2.IpPermission) string {
+	key := types.SafeString(permission.IpProtocol)
+	if permission.FromPort != nil {
+		key = key + "_" + types.IntToString(permission.FromPort) + "_" + types.IntToString(permission.ToPort)
+	}
+	return key
+}
+
+//// TRANSFORM FUNCTIONS
+
+func vpcSecurityGroupExposureTitle(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	exposure := d.HydrateItem.(*vpcSecurityGroupExposure)
+	return types.SafeString(exposure.GroupId) + "_" + securityGroupPortRangeKey(&ec2.IpPermission{
+		IpProtocol: exposure.IpProtocol,
+		FromPort:   exposure.FromPort,
+		ToPort:     exposure.ToPort,
+	}), nil
+}
@@ -0,0 +1,105 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestSecurityGroupExposures(t *testing.T) {
+	web := &ec2.SecurityGroup{
+		GroupId: aws.String("sg-web"),
+		IpPermissions: []*ec2.IpPermission{
+			{
+				IpProtocol: aws.String("tcp"),
+				FromPort:   aws.Int64(22),
+				ToPort:     aws.Int64(22),
+				IpRanges:   []*ec2.IpRange{{CidrIp: aws.String("0.0.0.0/0")}},
+			},
+			{
+				IpProtocol:    aws.String("tcp"),
+				FromPort:      aws.Int64(443),
+				ToPort:        aws.Int64(443),
+				PrefixListIds: []*ec2.PrefixListId{{PrefixListId: aws.String("pl-world")}},
+			},
+			{
+				IpProtocol:       aws.String("tcp"),
+				FromPort:         aws.Int64(5432),
+				ToPort:           aws.Int64(5432),
+				UserIdGroupPairs: []*ec2.UserIdGroupPair{{GroupId: aws.String("sg-app")}},
+			},
+			{
+				IpProtocol: aws.String("tcp"),
+				FromPort:   aws.Int64(5432),
+				ToPort:     aws.Int64(5432),
+				IpRanges:   []*ec2.IpRange{{CidrIp: aws.String("10.0.0.0/8")}},
+			},
+		},
+	}
+	private := &ec2.SecurityGroup{
+		GroupId: aws.String("sg-private"),
+		IpPermissions: []*ec2.IpPermission{
+			{
+				IpProtocol: aws.String("-1"),
+				Ipv6Ranges: []*ec2.Ipv6Range{{CidrIpv6: aws.String("::/0")}},
+			},
+		},
+	}
+	interfaces := []*ec2.NetworkInterface{
+		{
+			NetworkInterfaceId: aws.String("eni-web"),
+			Attachment:         &ec2.NetworkInterfaceAttachment{InstanceId: aws.String("i-web")},
+			Groups:             []*ec2.GroupIdentifier{{GroupId: aws.String("sg-web")}},
+			PrivateIpAddresses: []*ec2.NetworkInterfacePrivateIpAddress{{
+				PrivateIpAddress: aws.String("10.0.0.5"),
+				Association:      &ec2.NetworkInterfaceAssociation{PublicIp: aws.String("203.0.113.5")},
+			}},
+		},
+		{
+			NetworkInterfaceId: aws.String("eni-app"),
+			Groups:             []*ec2.GroupIdentifier{{GroupId: aws.String("sg-app")}, {GroupId: aws.String("sg-private")}},
+			PrivateIpAddress:   aws.String("10.0.1.7"),
+		},
+	}
+	prefixLists := map[string][]string{"pl-world": {"0.0.0.0/0", "10.0.0.0/8"}}
+
+	exposures := securityGroupExposures([]*ec2.SecurityGroup{web, private}, interfaces, prefixLists)
+	if len(exposures) != 4 {
+		t.Fatalf("Expected 4 exposures, got %d", len(exposures))
+	}
+
+	cases := []struct {
+		name          string
+		exposure      *vpcSecurityGroupExposure
+		isPublic      bool
+		isExposed     bool
+		publicSources []string
+		sourceCidrs   []string
+		exposed       []string
+	}{
+		{"ssh open to the world", exposures[0], true, true, []string{"0.0.0.0/0"}, []string{"0.0.0.0/0"}, []string{"i-web"}},
+		{"prefix list including the world", exposures[1], true, true, []string{"pl-world"}, []string{"0.0.0.0/0", "10.0.0.0/8"}, []string{"i-web"}},
+		{"referenced group and private range combined", exposures[2], false, false, []string{}, []string{"10.0.0.0/8", "10.0.1.7/32"}, []string{}},
+		{"open to ipv6 without ipv6 addresses", exposures[3], true, false, []string{"::/0"}, []string{"::/0"}, []string{}},
+	}
+
+	for _, c := range cases {
+		if c.exposure.IsPublic != c.isPublic {
+			t.Errorf("Case '%s': expected is_public %v, got %v", c.name, c.isPublic, c.exposure.IsPublic)
+		}
+		if c.exposure.IsExposed != c.isExposed {
+			t.Errorf("Case '%s': expected is_exposed %v, got %v", c.name, c.isExposed, c.exposure.IsExposed)
+		}
+		if !reflect.DeepEqual(c.exposure.PublicSources, c.publicSources) {
+			t.Errorf("Case '%s': expected public sources %v, got %v", c.name, c.publicSources, c.exposure.PublicSources)
+		}
+		if !reflect.DeepEqual(c.exposure.SourceCidrs, c.sourceCidrs) {
+			t.Errorf("Case '%s': expected source CIDRs %v, got %v", c.name, c.sourceCidrs, c.exposure.SourceCidrs)
+		}
+		if !reflect.DeepEqual(c.exposure.ExposedInstanceIds, c.exposed) {
+			t.Errorf("Case '%s': expected exposed instances %v, got %v", c.name, c.exposed, c.exposure.ExposedInstanceIds)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package aws
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 
 	"github.com/turbot/go-kit/types"
 
@@ -34,6 +36,12 @@ func tableAwsVpcSecurityGroupRule(_ context.Context) *plugin.Table {
 				Type:        proto.ColumnType_STRING,
 				Transform:   transform.FromField("Group.GroupId"),
 			},
+			{
+				Name:        "rule_id",
+				Description: "A stable identifier for the rule, derived from the security group, direction, protocol, port range and source or destination.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(securityGroupRuleID),
+			},
 			{
 				Name:        "type",
 				Description: "Type of the rule ( ingress | egress).",
@@ -117,6 +125,18 @@ func tableAwsVpcSecurityGroupRule(_ context.Context) *plugin.Table {
 				Type:        proto.ColumnType_STRING,
 				Transform:   transform.FromField("UserIDGroupPair.VpcPeeringConnectionId"),
 			},
+			{
+				Name:        "prefix_list_id",
+				Description: "The ID of the prefix list, for rules which allow traffic from or to a managed prefix list.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("PrefixListID.PrefixListId"),
+			},
+			{
+				Name:        "description",
+				Description: "The description of the IPv4 range, IPv6 range, security group pair or prefix list of the rule.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(securityGroupRuleDescription),
+			},
 			{
 				Name:        "title",
 				Description: resourceInterfaceDescription("title"),
@@ -174,20 +194,7 @@ func getSecurityGroupRuleTurbotData(ctx context.Context, d *plugin.QueryData, h
 	commonColumnData := commonData.(*awsCommonColumnData)
 
 	// To create uninque aka
-	hashCode := sgRule.Type + "_" + *sgRule.Permission.IpProtocol
-	if sgRule.Permission.FromPort != nil {
-		hashCode = hashCode + "_" + types.IntToString(sgRule.Permission.FromPort) + "_" + types.IntToString(sgRule.Permission.ToPort)
-	}
-
-	if sgRule.IPRange != nil && sgRule.IPRange.CidrIp != nil {
-		hashCode = hashCode + "_" + *sgRule.IPRange.CidrIp
-	} else if sgRule.Ipv6Range != nil && sgRule.Ipv6Range.CidrIpv6 != nil {
-		hashCode = hashCode + "_" + *sgRule.Ipv6Range.CidrIpv6
-	} else if sgRule.UserIDGroupPair != nil && *sgRule.UserIDGroupPair.GroupId == *sgRule.Group.GroupId {
-		hashCode = hashCode + "_" + *sgRule.Group.GroupId
-	} else if sgRule.UserIDGroupPair != nil && *sgRule.UserIDGroupPair.GroupId == *sgRule.Group.GroupId {
-		hashCode = hashCode + "_" + *sgRule.UserIDGroupPair.GroupId
-	}
+	hashCode := securityGroupRuleHashCode(sgRule)
 
 	// generate aka for the rule
 	akas := []string{"arn:" + commonColumnData.Partition + ":ec2:" + commonColumnData.Region + ":" + *sgRule.Group.OwnerId + ":security-group/" + *sgRule.Group.GroupId + ":" + hashCode}
@@ -202,6 +209,52 @@ func getSecurityGroupRuleTurbotData(ctx context.Context, d *plugin.QueryData, h
 	return turbotData, nil
 }
 
+//// TRANSFORM FUNCTIONS
+
+func securityGroupRuleID(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	sgRule := d.HydrateItem.(*vpcSecurityGroupRulesRowData)
+	sum := sha256.Sum256([]byte(*sgRule.Group.GroupId + "_" + securityGroupRuleHashCode(sgRule)))
+	return "sgrule-" + hex.EncodeToString(sum[:])[:17], nil
+}
+
+func securityGroupRuleDescription(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	sgRule := d.HydrateItem.(*vpcSecurityGroupRulesRowData)
+	switch {
+	case sgRule.IPRange != nil:
+		return sgRule.IPRange.Description, nil
+	case sgRule.Ipv6Range != nil:
+		return sgRule.Ipv6Range.Description, nil
+	case sgRule.UserIDGroupPair != nil:
+		return sgRule.UserIDGroupPair.Description, nil
+	case sgRule.PrefixListID != nil:
+		return sgRule.PrefixListID.Description, nil
+	}
+	return nil, nil
+}
+
+//// UTILITY FUNCTIONS
+
+// securityGroupRuleHashCode identifies a rule within its security group by its
+// direction, protocol, port range and source or destination
+func securityGroupRuleHashCode(sgRule *vpcSecurityGroupRulesRowData) string {
+	hashCode := sgRule.Type + "_" + *sgRule.Permission.IpProtocol
+	if sgRule.Permission.FromPort != nil {
+		hashCode = hashCode + "_" + types.IntToString(sgRule.Permission.FromPort) + "_" + types.IntToString(sgRule.Permission.ToPort)
+	}
+
+	if sgRule.IPRange != nil && sgRule.IPRange.CidrIp != nil {
+		hashCode = hashCode + "_" + *sgRule.IPRange.CidrIp
+	} else if sgRule.Ipv6Range != nil && sgRule.Ipv6Range.CidrIpv6 != nil {
+		hashCode = hashCode + "_" + *sgRule.Ipv6Range.CidrIpv6
+	} else if sgRule.UserIDGroupPair != nil && sgRule.UserIDGroupPair.GroupId != nil {
+		hashCode = hashCode + "_" + *sgRule.UserIDGroupPair.GroupId
+	} else if sgRule.PrefixListID != nil && sgRule.PrefixListID.PrefixListId != nil {
+		hashCode = hashCode + "_" + *sgRule.PrefixListID.PrefixListId
+	}
+
+	return hashCode
+}
+
 // custom struct for security group rule
 type vpcSecurityGroupRulesRowData struct {
 	Group           *ec2.SecurityGroup
@@ -209,6 +262,7 @@ type vpcSecurityGroupRulesRowData struct {
 	IPRange         *ec2.IpRange
 	Ipv6Range       *ec2.Ipv6Range
 	UserIDGroupPair *ec2.UserIdGroupPair
+	PrefixListID    *ec2.PrefixListId
 	Type            string
 }
 
@@ -0,0 +1,148 @@
+package aws
+
+import (
+	"context"
+	"net"
+
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+// subnetReservedIPAddressCount is the number of addresses AWS reserves in every
+// subnet: the network address, the VPC router, the DNS server, one for future
+// use and the broadcast address
+const subnetReservedIPAddressCount = 5
+
+func tableAwsVpcSubnetUtilization(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_vpc_subnet_utilization",
+		Description: "AWS VPC Subnet Utilization",
+		Get: &plugin.GetConfig{
+			KeyColumns:        plugin.SingleColumn("subnet_id"),
+			ShouldIgnoreError: isNotFoundError([]string{"InvalidSubnetID.Malformed", "InvalidSubnetID.NotFound"}),
+			Hydrate:           getVpcSubnet,
+		},
+		List: &plugin.ListConfig{
+			Hydrate: listVpcSubnets,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "subnet_id",
+				Description: "The ID of the subnet.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "vpc_id",
+				Description: "The ID of the VPC the subnet is in.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "cidr_block",
+				Description: "The IPv4 CIDR block of the subnet.",
+				Type:        proto.ColumnType_CIDR,
+			},
+			{
+				Name:        "availability_zone",
+				Description: "The Availability Zone of the subnet.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "total_ip_address_count",
+				Description: "The number of IPv4 addresses in the CIDR block of the subnet.",
+				Type:        proto.ColumnType_INT,
+				Hydrate:     getVpcSubnetUtilization,
+				Transform:   transform.FromField("TotalIPAddressCount"),
+			},
+			{
+				Name:        "reserved_ip_address_count",
+				Description: "The number of IPv4 addresses AWS reserves in the subnet, which is always 5.",
+				Type:        proto.ColumnType_INT,
+				Hydrate:     getVpcSubnetUtilization,
+				Transform:   transform.FromField("ReservedIPAddressCount"),
+			},
+			{
+				Name:        "used_ip_address_count",
+				Description: "The number of IPv4 addresses in use, by network interfaces and other resources in the subnet.",
+				Type:        proto.ColumnType_INT,
+				Hydrate:     getVpcSubnetUtilization,
+				Transform:   transform.FromField("UsedIPAddressCount"),
+			},
+			{
+				Name:        "free_ip_address_count",
+				Description: "The number of IPv4 addresses still available in the subnet.",
+				Type:        proto.ColumnType_INT,
+				Hydrate:     getVpcSubnetUtilization,
+				Transform:   transform.FromField("FreeIPAddressCount"),
+			},
+			{
+				Name:        "percent_used",
+				Description: "The percentage of the usable IPv4 addresses, all but the reserved addresses, which are in use.",
+				Type:        proto.ColumnType_DOUBLE,
+				Hydrate:     getVpcSubnetUtilization,
+				Transform:   transform.FromField("PercentUsed"),
+			},
+			{
+				Name:        "tags",
+				Description: resourceInterfaceDescription("tags"),
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.From(getVpcSubnetTurbotTags),
+			},
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.From(getSubnetTurbotTitle),
+			},
+		}),
+	}
+}
+
+type vpcSubnetUtilization struct {
+	TotalIPAddressCount    int64
+	ReservedIPAddressCount int64
+	UsedIPAddressCount     int64
+	FreeIPAddressCount     int64
+	PercentUsed            float64
+}
+
+//// HYDRATE FUNCTIONS
+
+func getVpcSubnetUtilization(_ context.Context, _ *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	subnet := h.Item.(*ec2.Subnet)
+	utilization := subnetUtilization(subnet)
+	if utilization == nil {
+		return nil, nil
+	}
+	return utilization, nil
+}
+
+//// UTILITY FUNCTIONS
+
+// subnetUtilization returns the IPv4 address usage of a subnet, from the size
+// of its CIDR block and the count of addresses still available
+func subnetUtilization(subnet *ec2.Subnet) *vpcSubnetUtilization {
+	_, cidr, err := net.ParseCIDR(types.SafeString(subnet.CidrBlock))
+	if err != nil {
+		return nil
+	}
+	ones, bits := cidr.Mask.Size()
+
+	utilization := &vpcSubnetUtilization{
+		TotalIPAddressCount:    int64(1) << uint(bits-ones),
+		ReservedIPAddressCount: subnetReservedIPAddressCount,
+		FreeIPAddressCount:     types.Int64Value(subnet.AvailableIpAddressCount),
+	}
+	usable := utilization.TotalIPAddressCount - utilization.ReservedIPAddressCount
+	utilization.UsedIPAddressCount = usable - utilization.FreeIPAddressCount
+	if utilization.UsedIPAddressCount < 0 {
+		utilization.UsedIPAddressCount = 0
+	}
+	if usable > 0 {
+		utilization.PercentUsed = float64(utilization.UsedIPAddressCount) * 100 / float64(usable)
+	}
+	return utilization
+}
@@ -0,0 +1,43 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestSubnetUtilization(t *testing.T) {
+	cases := []struct {
+		name     string
+		subnet   *ec2.Subnet
+		expected *vpcSubnetUtilization
+	}{
+		{
+			"empty /24",
+			&ec2.Subnet{CidrBlock: aws.String("10.0.0.0/24"), AvailableIpAddressCount: aws.Int64(251)},
+			&vpcSubnetUtilization{256, 5, 0, 251, 0},
+		},
+		{
+			"half used /28",
+			&ec2.Subnet{CidrBlock: aws.String("10.0.0.0/28"), AvailableIpAddressCount: aws.Int64(5)},
+			&vpcSubnetUtilization{16, 5, 6, 5, 54.54545454545455},
+		},
+		{
+			"exhausted /20",
+			&ec2.Subnet{CidrBlock: aws.String("10.0.16.0/20"), AvailableIpAddressCount: aws.Int64(0)},
+			&vpcSubnetUtilization{4096, 5, 4091, 0, 100},
+		},
+		{
+			"no CIDR block",
+			&ec2.Subnet{},
+			nil,
+		},
+	}
+	for _, c := range cases {
+		if utilization := subnetUtilization(c.subnet); !reflect.DeepEqual(utilization, c.expected) {
+			t.Errorf("Case '%s': expected %v, got %v", c.name, c.expected, utilization)
+		}
+	}
+}
@@ -0,0 +1,366 @@
+package aws
+
+import (
+	"context"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
+)
+
+func tableAwsVpcVpnConnection(_ context.Context) *plugin.Table {
+	return &plugin.Table{
+		Name:        "aws_vpc_vpn_connection",
+		Description: "AWS VPC VPN Connection",
+		Get: &plugin.GetConfig{
+			KeyColumns:        plugin.SingleColumn("vpn_connection_id"),
+			ShouldIgnoreError: isNotFoundError([]string{"InvalidVpnConnectionID.NotFound", "InvalidVpnConnectionID.Malformed"}),
+			Hydrate:           getVpcVpnConnection,
+		},
+		List: &plugin.ListConfig{
+			Hydrate: listVpcVpnConnections,
+		},
+		GetMatrixItem: BuildRegionList,
+		Columns: awsRegionalColumns([]*plugin.Column{
+			{
+				Name:        "vpn_connection_id",
+				Description: "The ID of the VPN connection.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "state",
+				Description: "The current state of the VPN connection (pending | available | deleting | deleted).",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "type",
+				Description: "The type of VPN connection.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "category",
+				Description: "The category of the VPN connection. VPN for AWS VPN connections, VPN-Classic for AWS Classic VPN connections.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "customer_gateway_id",
+				Description: "The ID of the customer gateway at your end of the VPN connection.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "vpn_gateway_id",
+				Description: "The ID of the virtual private gateway at the AWS side of the VPN connection.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "transit_gateway_id",
+				Description: "The ID of the transit gateway associated with the VPN connection.",
+				Type:        proto.ColumnType_STRING,
+			},
+			{
+				Name:        "static_routes_only",
+				Description: "Indicates whether the VPN connection uses static routes only, rather than BGP.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("Options.StaticRoutesOnly"),
+			},
+			{
+				Name:        "enable_acceleration",
+				Description: "Indicates whether acceleration is enabled for the VPN connection.",
+				Type:        proto.ColumnType_BOOL,
+				Transform:   transform.FromField("Options.EnableAcceleration"),
+			},
+			{
+				Name:        "tunnel_inside_ip_version",
+				Description: "Indicates whether the VPN tunnels process IPv4 or IPv6 traffic.",
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromField("Options.TunnelInsideIpVersion"),
+			},
+			{
+				Name:        "local_ipv4_network_cidr",
+				Description: "The IPv4 CIDR on the customer gateway side of the VPN connection.",
+				Type:        proto.ColumnType_CIDR,
+				Transform:   transform.FromField("Options.LocalIpv4NetworkCidr"),
+			},
+			{
+				Name:        "remote_ipv4_network_cidr",
+				Description: "The IPv4 CIDR on the AWS side of the VPN connection.",
+				Type:        proto.ColumnType_CIDR,
+				Transform:   transform.FromField("Options.RemoteIpv4NetworkCidr"),
+			},
+			{
+				Name:        "local_ipv6_network_cidr",
+				Description: "The IPv6 CIDR on the customer gateway side of the VPN connection.",
+				Type:        proto.ColumnType_CIDR,
+				Transform:   transform.FromField("Options.LocalIpv6NetworkCidr"),
+			},
+			{
+				Name:        "remote_ipv6_network_cidr",
+				Description: "The IPv6 CIDR on the AWS side of the VPN connection.",
+				Type:        proto.ColumnType_CIDR,
+				Transform:   transform.FromField("Options.RemoteIpv6NetworkCidr"),
+			},
+			{
+				Name:        "tunnel_up_count",
+				Description: "The number of tunnels of the VPN connection which are up.",
+				Type:        proto.ColumnType_INT,
+				Transform:   transform.From(vpnConnectionTunnelUpCount),
+			},
+			{
+				Name:        "tunnels",
+				Description: "The tunnels of the VPN connection, with the status and telemetry of each tunnel and its IKE and IPsec options. Pre-shared keys are not included.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.From(vpnConnectionTunnelsData),
+			},
+			{
+				Name:        "vgw_telemetry",
+				Description: "Information about the VPN tunnel.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "routes",
+				Description: "The static routes associated with the VPN connection.",
+				Type:        proto.ColumnType_JSON,
+			},
+			{
+				Name:        "tags_src",
+				Description: "A list of tags that are attached to the VPN connection.",
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromField("Tags"),
+			},
+			{
+				Name:        "tags",
+				Description: resourceInterfaceDescription("tags"),
+				Type:        proto.ColumnType_JSON,
+				Transform:   transform.FromP(getVpcVpnConnectionTurbotData, "Tags"),
+			},
+			{
+				Name:        "title",
+				Description: resourceInterfaceDescription("title"),
+				Type:        proto.ColumnType_STRING,
+				Transform:   transform.FromP(getVpcVpnConnectionTurbotData, "Title"),
+			},
+			{
+				Name:        "akas",
+				Description: resourceInterfaceDescription("akas"),
+				Type:        proto.ColumnType_JSON,
+				Hydrate:     getVpcVpnConnectionTurbotAkas,
+				Transform:   transform.FromValue(),
+			},
+		}),
+	}
+}
+
+// vpnConnectionTunnel is the telemetry and options of a VPN tunnel, matched by
+// outside IP address
+type vpnConnectionTunnel struct {
+	OutsideIpAddress           *string
+	Status                     *string
+	StatusMessage              *string
+	LastStatusChange           *time.Time
+	AcceptedRouteCount         *int64
+	CertificateArn             *string
+	TunnelInsideCidr           *string
+	TunnelInsideIpv6Cidr       *string
+	IkeVersions                []string
+	Phase1EncryptionAlgorithms []string
+	Phase1IntegrityAlgorithms  []string
+	Phase1DHGroupNumbers       []int64
+	Phase1LifetimeSeconds      *int64
+	Phase2EncryptionAlgorithms []string
+	Phase2IntegrityAlgorithms  []string
+	Phase2DHGroupNumbers       []int64
+	Phase2LifetimeSeconds      *int64
+	DpdTimeoutAction           *string
+	DpdTimeoutSeconds          *int64
+	StartupAction              *string
+}
+
+//// LIST FUNCTION
+
+func listVpcVpnConnections(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	plugin.Logger(ctx).Trace("listVpcVpnConnections", "AWS_REGION", region)
+
+	// Create session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	// List call
+	resp, err := svc.DescribeVpnConnections(&ec2.DescribeVpnConnectionsInput{})
+	if err != nil {
+		return nil, err
+	}
+	for _, vpnConnection := range resp.VpnConnections {
+		d.StreamListItem(ctx, vpnConnection)
+	}
+
+	return nil, nil
+}
+
+//// HYDRATE FUNCTIONS
+
+func getVpcVpnConnection(ctx context.Context, d *plugin.QueryData, _ *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getVpcVpnConnection")
+
+	var region string
+	matrixRegion := plugin.GetMatrixItem(ctx)[matrixKeyRegion]
+	if matrixRegion != nil {
+		region = matrixRegion.(string)
+	}
+	vpnConnectionID := d.KeyColumnQuals["vpn_connection_id"].GetStringValue()
+
+	// Create session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	// Build the params
+	params := &ec2.DescribeVpnConnectionsInput{
+		VpnConnectionIds: []*string{aws.String(vpnConnectionID)},
+	}
+
+	// Get call
+	op, err := svc.DescribeVpnConnections(params)
+	if err != nil {
+		plugin.Logger(ctx).Debug("getVpcVpnConnection__", "ERROR", err)
+		return nil, err
+	}
+
+	if op.VpnConnections != nil && len(op.VpnConnections) > 0 {
+		return op.VpnConnections[0], nil
+	}
+	return nil, nil
+}
+
+func getVpcVpnConnectionTurbotAkas(ctx context.Context, d *plugin.QueryData, h *plugin.HydrateData) (interface{}, error) {
+	plugin.Logger(ctx).Trace("getVpcVpnConnectionTurbotAkas")
+	vpnConnection := h.Item.(*ec2.VpnConnection)
+	commonData, err := getCommonColumns(ctx, d, h)
+	if err != nil {
+		return nil, err
+	}
+	commonColumnData := commonData.(*awsCommonColumnData)
+
+	// Get data for turbot defined properties
+	akas := []string{"arn:" + commonColumnData.Partition + ":ec2:" + commonColumnData.Region + ":" + commonColumnData.AccountId + ":vpn-connection/" + *vpnConnection.VpnConnectionId}
+
+	return akas, nil
+}
+
+//// TRANSFORM FUNCTIONS
+
+func vpnConnectionTunnelsData(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	vpnConnection := d.HydrateItem.(*ec2.VpnConnection)
+	return vpnConnectionTunnels(vpnConnection), nil
+}
+
+func vpnConnectionTunnelUpCount(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	vpnConnection := d.HydrateItem.(*ec2.VpnConnection)
+	count := 0
+	for _, telemetry := range vpnConnection.VgwTelemetry {
+		if types.SafeString(telemetry.Status) == ec2.TelemetryStatusUp {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func getVpcVpnConnectionTurbotData(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	vpnConnection := d.HydrateItem.(*ec2.VpnConnection)
+	param := d.Param.(string)
+
+	// Get resource title
+	title := vpnConnection.VpnConnectionId
+
+	// Get the resource tags
+	var turbotTagsMap map[string]string
+	if vpnConnection.Tags != nil {
+		turbotTagsMap = map[string]string{}
+		for _, i := range vpnConnection.Tags {
+			turbotTagsMap[*i.Key] = *i.Value
+			if *i.Key == "Name" {
+				title = i.Value
+			}
+		}
+	}
+
+	if param == "Tags" {
+		return turbotTagsMap, nil
+	}
+
+	return title, nil
+}
+
+//// UTILITY FUNCTIONS
+
+// vpnConnectionTunnels combines the telemetry and the options of each tunnel of
+// a VPN connection. The pre-shared key of the options is left out.
+func vpnConnectionTunnels(vpnConnection *ec2.VpnConnection) []*vpnConnectionTunnel {
+	tunnels := []*vpnConnectionTunnel{}
+	byAddress := map[string]*vpnConnectionTunnel{}
+	tunnel := func(address *string) *vpnConnectionTunnel {
+		if t, ok := byAddress[types.SafeString(address)]; ok {
+			return t
+		}
+		t := &vpnConnectionTunnel{OutsideIpAddress: address}
+		byAddress[types.SafeString(address)] = t
+		tunnels = append(tunnels, t)
+		return t
+	}
+
+	for _, telemetry := range vpnConnection.VgwTelemetry {
+		t := tunnel(telemetry.OutsideIpAddress)
+		t.Status = telemetry.Status
+		t.StatusMessage = telemetry.StatusMessage
+		t.LastStatusChange = telemetry.LastStatusChange
+		t.AcceptedRouteCount = telemetry.AcceptedRouteCount
+		t.CertificateArn = telemetry.CertificateArn
+	}
+
+	if vpnConnection.Options == nil {
+		return tunnels
+	}
+	for _, options := range vpnConnection.Options.TunnelOptions {
+		t := tunnel(options.OutsideIpAddress)
+		t.TunnelInsideCidr = options.TunnelInsideCidr
+		t.TunnelInsideIpv6Cidr = options.TunnelInsideIpv6Cidr
+		t.Phase1LifetimeSeconds = options.Phase1LifetimeSeconds
+		t.Phase2LifetimeSeconds = options.Phase2LifetimeSeconds
+		t.DpdTimeoutAction = options.DpdTimeoutAction
+		t.DpdTimeoutSeconds = options.DpdTimeoutSeconds
+		t.StartupAction = options.StartupAction
+		for _, v := range options.IkeVersions {
+			t.IkeVersions = append(t.IkeVersions, types.SafeString(v.Value))
+		}
+		for _, v := range options.Phase1EncryptionAlgorithms {
+			t.Phase1EncryptionAlgorithms = append(t.Phase1EncryptionAlgorithms, types.SafeString(v.Value))
+		}
+		for _, v := range options.Phase1IntegrityAlgorithms {
+			t.Phase1IntegrityAlgorithms = append(t.Phase1IntegrityAlgorithms, types.SafeString(v.Value))
+		}
+		for _, v := range options.Phase1DHGroupNumbers {
+			t.Phase1DHGroupNumbers = append(t.Phase1DHGroupNumbers, types.Int64Value(v.Value))
+		}
+		for _, v := range options.Phase2EncryptionAlgorithms {
+			t.Phase2EncryptionAlgorithms = append(t.Phase2EncryptionAlgorithms, types.SafeString(v.Value))
+		}
+		for _, v := range options.Phase2IntegrityAlgorithms {
+			t.Phase2IntegrityAlgorithms = append(t.Phase2IntegrityAlgorithms, types.SafeString(v.Value))
+		}
+		for _, v := range options.Phase2DHGroupNumbers {
+			t.Phase2DHGroupNumbers = append(t.Phase2DHGroupNumbers, types.Int64Value(v.Value))
+		}
+	}
+	return tunnels
+}
@@ -0,0 +1,55 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestVpnConnectionTunnels(t *testing.T) {
+	vpnConnection := &ec2.VpnConnection{
+		VgwTelemetry: []*ec2.VgwTelemetry{
+			{OutsideIpAddress: aws.String("198.51.100.1"), Status: aws.String("UP"), AcceptedRouteCount: aws.Int64(3)},
+			{OutsideIpAddress: aws.String("198.51.100.2"), Status: aws.String("DOWN"), StatusMessage: aws.String("IPSEC IS DOWN")},
+		},
+		Options: &ec2.VpnConnectionOptions{
+			TunnelOptions: []*ec2.TunnelOption{
+				{
+					OutsideIpAddress: aws.String("198.51.100.2"),
+					TunnelInsideCidr: aws.String("169.254.10.0/30"),
+					PreSharedKey:     aws.String("secret"),
+					IkeVersions:      []*ec2.IKEVersionsListValue{{Value: aws.String("ikev2")}},
+					Phase1DHGroupNumbers: []*ec2.Phase1DHGroupNumbersListValue{
+						{Value: aws.Int64(14)},
+						{Value: aws.Int64(20)},
+					},
+				},
+				{OutsideIpAddress: aws.String("198.51.100.3"), TunnelInsideCidr: aws.String("169.254.11.0/30")},
+			},
+		},
+	}
+
+	expected := []*vpnConnectionTunnel{
+		{OutsideIpAddress: aws.String("198.51.100.1"), Status: aws.String("UP"), AcceptedRouteCount: aws.Int64(3)},
+		{
+			OutsideIpAddress:     aws.String("198.51.100.2"),
+			Status:               aws.String("DOWN"),
+			StatusMessage:        aws.String("IPSEC IS DOWN"),
+			TunnelInsideCidr:     aws.String("169.254.10.0/30"),
+			IkeVersions:          []string{"ikev2"},
+			Phase1DHGroupNumbers: []int64{14, 20},
+		},
+		{OutsideIpAddress: aws.String("198.51.100.3"), TunnelInsideCidr: aws.String("169.254.11.0/30")},
+	}
+
+	tunnels := vpnConnectionTunnels(vpnConnection)
+	if !reflect.DeepEqual(tunnels, expected) {
+		t.Errorf("Case 'tunnels': expected %v, got %v", expected, tunnels)
+	}
+
+	if tunnels := vpnConnectionTunnels(&ec2.VpnConnection{}); len(tunnels) != 0 {
+		t.Errorf("Case 'no tunnels': expected none, got %v", tunnels)
+	}
+}
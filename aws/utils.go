@@ -2,6 +2,7 @@ package aws
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"math"
 	"net/url"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/aws/aws-sdk-go/service/ec2"
 	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/plugin/transform"
 )
 
@@ -87,6 +89,7 @@ func rowSourceFromIPPermission(group *ec2.SecurityGroup, permission *ec2.IpPermi
 				IPRange:         r,
 				Ipv6Range:       nil,
 				UserIDGroupPair: nil,
+				PrefixListID:    nil,
 				Type:            groupType,
 			})
 		}
@@ -101,6 +104,7 @@ func rowSourceFromIPPermission(group *ec2.SecurityGroup, permission *ec2.IpPermi
 				IPRange:         nil,
 				Ipv6Range:       r,
 				UserIDGroupPair: nil,
+				PrefixListID:    nil,
 				Type:            groupType,
 			})
 		}
@@ -115,6 +119,22 @@ func rowSourceFromIPPermission(group *ec2.SecurityGroup, permission *ec2.IpPermi
 				IPRange:         nil,
 				Ipv6Range:       nil,
 				UserIDGroupPair: r,
+				PrefixListID:    nil,
+				Type:            groupType,
+			})
+		}
+	}
+
+	// create 1 row per prefix list
+	if permission.PrefixListIds != nil {
+		for _, r := range permission.PrefixListIds {
+			rowSource = append(rowSource, &vpcSecurityGroupRulesRowData{
+				Group:           group,
+				Permission:      permission,
+				IPRange:         nil,
+				Ipv6Range:       nil,
+				UserIDGroupPair: nil,
+				PrefixListID:    r,
 				Type:            groupType,
 			})
 		}
@@ -123,6 +143,23 @@ func rowSourceFromIPPermission(group *ec2.SecurityGroup, permission *ec2.IpPermi
 	return rowSource
 }
 
+// parseJSONString parses a JSON document held in a string. Unlike
+// transform.UnmarshalYAML the string is not URL decoded first, so it is safe
+// for documents which may contain '+' or '%'.
+func parseJSONString(_ context.Context, d *transform.TransformData) (interface{}, error) {
+	inputStr := types.SafeString(d.Value)
+	if inputStr == "" {
+		return nil, nil
+	}
+
+	var result interface{}
+	if err := json.Unmarshal([]byte(inputStr), &result); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 func resourceInterfaceDescription(key string) string {
 	switch key {
 	case "akas":
@@ -145,4 +182,75 @@ func getLastPathElement(path string) string {
 
 func lastPathElement(_ context.Context, d *transform.TransformData) (interface{}, error) {
 	return getLastPathElement(types.SafeString(d.Value)), nil
-}
\ No newline at end of file
+}
+
+// getOptionalStringQual returns the value of a single '=' qual on a column which is
+// not a key column, or "" if there is none. Used to push optional filters down to
+// AWS; postgres re-checks all quals so it is safe to ignore anything else.
+func getOptionalStringQual(d *plugin.QueryData, column string) string {
+	quals, ok := d.QueryContext.Quals[column]
+	if !ok || len(quals.Quals) != 1 {
+		return ""
+	}
+	qual := quals.Quals[0]
+	if qual.GetStringValue() != "=" || qual.Value == nil {
+		return ""
+	}
+	return qual.Value.GetStringValue()
+}
+
+// getStringQualValues returns the values of an '=' qual on a column, which may
+// be a list of values for IN (...) clauses, or nil if there is none
+func getStringQualValues(d *plugin.QueryData, column string) []string {
+	quals, ok := d.QueryContext.Quals[column]
+	if !ok {
+		return nil
+	}
+	for _, qual := range quals.Quals {
+		if qual.GetStringValue() != "=" || qual.Value == nil {
+			continue
+		}
+		if list := qual.Value.GetListValue(); list != nil {
+			var values []string
+			for _, value := range list.Values {
+				values = append(values, value.GetStringValue())
+			}
+			return values
+		}
+		return []string{qual.Value.GetStringValue()}
+	}
+	return nil
+}
+
+// getTimestampQualRange returns the earliest and latest times allowed by the
+// '=', '>', '>=', '<' and '<=' quals on a timestamp column. Either bound is nil
+// if the quals do not restrict it.
+func getTimestampQualRange(d *plugin.QueryData, column string) (*time.Time, *time.Time) {
+	var start, end *time.Time
+
+	quals, ok := d.QueryContext.Quals[column]
+	if !ok {
+		return nil, nil
+	}
+
+	for _, qual := range quals.Quals {
+		if qual.Value == nil || qual.Value.GetTimestampValue() == nil {
+			continue
+		}
+		value := qual.Value.GetTimestampValue().AsTime()
+		switch qual.GetStringValue() {
+		case "=":
+			start, end = &value, &value
+		case ">", ">=":
+			if start == nil || value.After(*start) {
+				start = &value
+			}
+		case "<", "<=":
+			if end == nil || value.Before(*end) {
+				end = &value
+			}
+		}
+	}
+
+	return start, end
+}
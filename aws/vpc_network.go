@@ -0,0 +1,817 @@
+package aws
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/turbot/go-kit/helpers"
+	"github.com/turbot/go-kit/types"
+	"github.com/turbot/steampipe-plugin-sdk/plugin"
+)
+
+//
+// Collection of the network configuration of a region, for tables which
+// analyse how traffic flows between resources. The configuration is read for
+// each query rather than cached for the connection, so that a rule which has
+// just been changed is reported straight away.
+//
+
+// getVpcSecurityGroups returns all the security groups of a region
+func getVpcSecurityGroups(ctx context.Context, d *plugin.QueryData, region string) ([]*ec2.SecurityGroup, error) {
+	// Create session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	var groups []*ec2.SecurityGroup
+	err = svc.DescribeSecurityGroupsPages(
+		&ec2.DescribeSecurityGroupsInput{},
+		func(page *ec2.DescribeSecurityGroupsOutput, isLast bool) bool {
+			groups = append(groups, page.SecurityGroups...)
+			return !isLast
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return groups, nil
+}
+
+// getVpcNetworkInterfaces returns all the network interfaces of a region
+func getVpcNetworkInterfaces(ctx context.Context, d *plugin.QueryData, region string) ([]*ec2.NetworkInterface, error) {
+	// Create session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	var interfaces []*ec2.NetworkInterface
+	err = svc.DescribeNetworkInterfacesPages(
+		&ec2.DescribeNetworkInterfacesInput{},
+		func(page *ec2.DescribeNetworkInterfacesOutput, isLast bool) bool {
+			interfaces = append(interfaces, page.NetworkInterfaces...)
+			return !isLast
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return interfaces, nil
+}
+
+// getVpcVpcs returns all the VPCs of a region
+func getVpcVpcs(ctx context.Context, d *plugin.QueryData, region string) ([]*ec2.Vpc, error) {
+	// Create session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	var vpcs []*ec2.Vpc
+	err = svc.DescribeVpcsPages(
+		&ec2.DescribeVpcsInput{},
+		func(page *ec2.DescribeVpcsOutput, isLast bool) bool {
+			vpcs = append(vpcs, page.Vpcs...)
+			return !isLast
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return vpcs, nil
+}
+
+// getVpcSubnets returns all the subnets of a region
+func getVpcSubnets(ctx context.Context, d *plugin.QueryData, region string) ([]*ec2.Subnet, error) {
+	// Create session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	var subnets []*ec2.Subnet
+	err = svc.DescribeSubnetsPages(
+		&ec2.DescribeSubnetsInput{},
+		func(page *ec2.DescribeSubnetsOutput, isLast bool) bool {
+			subnets = append(subnets, page.Subnets...)
+			return !isLast
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return subnets, nil
+}
+
+// getVpcRouteTables returns all the route tables of a region
+func getVpcRouteTables(ctx context.Context, d *plugin.QueryData, region string) ([]*ec2.RouteTable, error) {
+	// Create session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	var routeTables []*ec2.RouteTable
+	err = svc.DescribeRouteTablesPages(
+		&ec2.DescribeRouteTablesInput{},
+		func(page *ec2.DescribeRouteTablesOutput, isLast bool) bool {
+			routeTables = append(routeTables, page.RouteTables...)
+			return !isLast
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return routeTables, nil
+}
+
+// getVpcNetworkAcls returns all the network ACLs of a region
+func getVpcNetworkAcls(ctx context.Context, d *plugin.QueryData, region string) ([]*ec2.NetworkAcl, error) {
+	// Create session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	var acls []*ec2.NetworkAcl
+	err = svc.DescribeNetworkAclsPages(
+		&ec2.DescribeNetworkAclsInput{},
+		func(page *ec2.DescribeNetworkAclsOutput, isLast bool) bool {
+			acls = append(acls, page.NetworkAcls...)
+			return !isLast
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return acls, nil
+}
+
+// getVpcNatGateways returns all the NAT gateways of a region
+func getVpcNatGateways(ctx context.Context, d *plugin.QueryData, region string) ([]*ec2.NatGateway, error) {
+	// Create session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	var natGateways []*ec2.NatGateway
+	err = svc.DescribeNatGatewaysPages(
+		&ec2.DescribeNatGatewaysInput{},
+		func(page *ec2.DescribeNatGatewaysOutput, isLast bool) bool {
+			natGateways = append(natGateways, page.NatGateways...)
+			return !isLast
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return natGateways, nil
+}
+
+// getVpcPeeringConnections returns all the VPC peering connections of a region
+func getVpcPeeringConnections(ctx context.Context, d *plugin.QueryData, region string) ([]*ec2.VpcPeeringConnection, error) {
+	// Create session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	var connections []*ec2.VpcPeeringConnection
+	err = svc.DescribeVpcPeeringConnectionsPages(
+		&ec2.DescribeVpcPeeringConnectionsInput{},
+		func(page *ec2.DescribeVpcPeeringConnectionsOutput, isLast bool) bool {
+			connections = append(connections, page.VpcPeeringConnections...)
+			return !isLast
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return connections, nil
+}
+
+// getTransitGatewayAttachments returns all the transit gateway attachments of a region
+func getTransitGatewayAttachments(ctx context.Context, d *plugin.QueryData, region string) ([]*ec2.TransitGatewayAttachment, error) {
+	// Create session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	var attachments []*ec2.TransitGatewayAttachment
+	err = svc.DescribeTransitGatewayAttachmentsPages(
+		&ec2.DescribeTransitGatewayAttachmentsInput{},
+		func(page *ec2.DescribeTransitGatewayAttachmentsOutput, isLast bool) bool {
+			attachments = append(attachments, page.TransitGatewayAttachments...)
+			return !isLast
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+// getManagedPrefixListCidrs returns the CIDRs of the current version of a
+// managed prefix list
+func getManagedPrefixListCidrs(ctx context.Context, d *plugin.QueryData, region string, prefixListID string) ([]string, error) {
+	// Create session
+	svc, err := Ec2Service(ctx, d, region)
+	if err != nil {
+		return nil, err
+	}
+
+	var cidrs []string
+	err = svc.GetManagedPrefixListEntriesPages(
+		&ec2.GetManagedPrefixListEntriesInput{PrefixListId: aws.String(prefixListID)},
+		func(page *ec2.GetManagedPrefixListEntriesOutput, isLast bool) bool {
+			for _, entry := range page.Entries {
+				cidrs = append(cidrs, types.SafeString(entry.Cidr))
+			}
+			return !isLast
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return cidrs, nil
+}
+
+// getReferencedPrefixListCidrs returns the CIDRs of every prefix list referenced
+// by the rules of the security groups, by prefix list ID
+func getReferencedPrefixListCidrs(ctx context.Context, d *plugin.QueryData, region string, groups []*ec2.SecurityGroup) (map[string][]string, error) {
+	prefixLists := map[string][]string{}
+	for _, group := range groups {
+		for _, permission := range append(append([]*ec2.IpPermission{}, group.IpPermissions...), group.IpPermissionsEgress...) {
+			for _, prefixList := range permission.PrefixListIds {
+				id := types.SafeString(prefixList.PrefixListId)
+				if _, ok := prefixLists[id]; ok {
+					continue
+				}
+				cidrs, err := getManagedPrefixListCidrs(ctx, d, region, id)
+				if err != nil {
+					return nil, err
+				}
+				prefixLists[id] = cidrs
+			}
+		}
+	}
+	return prefixLists, nil
+}
+
+// networkInterfaceAddresses returns the private and public IPv4 addresses and
+// the IPv6 addresses of a network interface
+func networkInterfaceAddresses(eni *ec2.NetworkInterface) (private []string, public []string, ipv6 []string) {
+	for _, address := range eni.PrivateIpAddresses {
+		private = append(private, types.SafeString(address.PrivateIpAddress))
+		if address.Association != nil && address.Association.PublicIp != nil {
+			public = append(public, *address.Association.PublicIp)
+		}
+	}
+	if len(private) == 0 && eni.PrivateIpAddress != nil {
+		private = append(private, *eni.PrivateIpAddress)
+	}
+	if len(public) == 0 && eni.Association != nil && eni.Association.PublicIp != nil {
+		public = append(public, *eni.Association.PublicIp)
+	}
+	for _, address := range eni.Ipv6Addresses {
+		ipv6 = append(ipv6, types.SafeString(address.Ipv6Address))
+	}
+	return private, public, ipv6
+}
+
+// networkInterfaceGroupIds returns the IDs of the security groups of a network
+// interface
+func networkInterfaceGroupIds(eni *ec2.NetworkInterface) []string {
+	var ids []string
+	for _, group := range eni.Groups {
+		ids = append(ids, types.SafeString(group.GroupId))
+	}
+	return ids
+}
+
+//// TRAFFIC EVALUATION
+
+// networkTraffic is the protocol and port of the traffic being evaluated
+type networkTraffic struct {
+	// The protocol number, or -1 for all protocols
+	Protocol string
+	// The port for TCP and UDP, or the ICMP type. -1 for all ports.
+	Port int64
+}
+
+var ipProtocolNumbers = map[string]string{
+	"-1":     "-1",
+	"all":    "-1",
+	"icmp":   "1",
+	"tcp":    "6",
+	"udp":    "17",
+	"icmpv6": "58",
+}
+
+// ipProtocolNumber returns the number of a protocol given by name or number
+func ipProtocolNumber(protocol string) string {
+	if number, ok := ipProtocolNumbers[strings.ToLower(protocol)]; ok {
+		return number
+	}
+	return protocol
+}
+
+// ipProtocolName returns the name of a protocol given by number, or the number
+// for protocols with no common name
+func ipProtocolName(protocol string) string {
+	number := ipProtocolNumber(protocol)
+	for name, n := range ipProtocolNumbers {
+		if n == number && name != "-1" && (number != "-1" || name == "all") {
+			return name
+		}
+	}
+	return number
+}
+
+// The ephemeral ports return traffic of TCP and UDP may be sent to. Clients use
+// part of the range depending on their operating system, and NAT gateways and
+// load balancers use all of it.
+const (
+	ephemeralPortFrom = 1024
+	ephemeralPortTo   = 65535
+)
+
+// isConnection returns true for TCP and UDP, whose return traffic goes to an
+// ephemeral port
+func (traffic networkTraffic) isConnection() bool {
+	return traffic.Protocol == "6" || traffic.Protocol == "17"
+}
+
+// returnTraffic returns the traffic which answers an ICMP or other non-TCP,
+// non-UDP request, e.g. an echo reply for an echo request
+func (traffic networkTraffic) returnTraffic() networkTraffic {
+	switch {
+	case traffic.Protocol == "1" && traffic.Port == 8:
+		return networkTraffic{Protocol: "1", Port: 0}
+	case traffic.Protocol == "58" && traffic.Port == 128:
+		return networkTraffic{Protocol: "58", Port: 129}
+	}
+	return traffic
+}
+
+func (traffic networkTraffic) isIcmp() bool {
+	return traffic.Protocol == "1" || traffic.Protocol == "58"
+}
+
+func (traffic networkTraffic) matchesProtocol(protocol string) bool {
+	number := ipProtocolNumber(protocol)
+	return number == "-1" || number == traffic.Protocol
+}
+
+// matchesPortRange returns true if the range covers the port. A nil range
+// covers all ports. When all ports are evaluated, the range must cover them all.
+func (traffic networkTraffic) matchesPortRange(from *int64, to *int64) bool {
+	if from == nil || *from == -1 {
+		return true
+	}
+	if traffic.Port == -1 {
+		return *from <= 0 && to != nil && *to >= 65535
+	}
+	return *from <= traffic.Port && to != nil && traffic.Port <= *to
+}
+
+// matchesIcmpType returns true if the ICMP type of a rule, where -1 means all
+// types, covers the traffic
+func (traffic networkTraffic) matchesIcmpType(icmpType *int64) bool {
+	return icmpType == nil || *icmpType == -1 || *icmpType == traffic.Port
+}
+
+// parseNetworkAddress parses a CIDR, or a single IP address as a /32 or /128
+func parseNetworkAddress(address string) (*net.IPNet, error) {
+	if !strings.Contains(address, "/") {
+		ip := net.ParseIP(address)
+		if ip == nil {
+			return nil, fmt.Errorf("%s is not an IP address or CIDR", address)
+		}
+		if ip.To4() != nil {
+			address = address + "/32"
+		} else {
+			address = address + "/128"
+		}
+	}
+	_, cidr, err := net.ParseCIDR(address)
+	return cidr, err
+}
+
+// cidrContains returns true if every address of the inner CIDR is in the outer
+// CIDR
+func cidrContains(outer string, inner *net.IPNet) bool {
+	_, outerCidr, err := net.ParseCIDR(outer)
+	if err != nil || inner == nil {
+		return false
+	}
+	outerOnes, outerBits := outerCidr.Mask.Size()
+	innerOnes, innerBits := inner.Mask.Size()
+	return outerBits == innerBits && outerOnes <= innerOnes && outerCidr.Contains(inner.IP)
+}
+
+// evaluateNetworkACL returns the entry which decides the traffic to or from a
+// peer. Entries are evaluated in order of rule number, and the first entry
+// which matches decides.
+func evaluateNetworkACL(acl *ec2.NetworkAcl, egress bool, traffic networkTraffic, peer *net.IPNet) *ec2.NetworkAclEntry {
+	var entries []*ec2.NetworkAclEntry
+	for _, entry := range acl.Entries {
+		if types.BoolValue(entry.Egress) == egress {
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return types.Int64Value(entries[i].RuleNumber) < types.Int64Value(entries[j].RuleNumber)
+	})
+
+	for _, entry := range entries {
+		if !cidrContains(types.SafeString(entry.CidrBlock), peer) && !cidrContains(types.SafeString(entry.Ipv6CidrBlock), peer) {
+			continue
+		}
+		if !traffic.matchesProtocol(types.SafeString(entry.Protocol)) {
+			continue
+		}
+		switch {
+		case ipProtocolNumber(types.SafeString(entry.Protocol)) == "-1":
+		case traffic.isIcmp():
+			if entry.IcmpTypeCode != nil && !traffic.matchesIcmpType(entry.IcmpTypeCode.Type) {
+				continue
+			}
+		case entry.PortRange != nil:
+			if !traffic.matchesPortRange(entry.PortRange.From, entry.PortRange.To) {
+				continue
+			}
+		}
+		return entry
+	}
+	return nil
+}
+
+// allowedEphemeralPorts returns the ranges of ephemeral ports which a network
+// ACL allows the return traffic of a TCP or UDP connection to or from a peer on
+func allowedEphemeralPorts(acl *ec2.NetworkAcl, egress bool, protocol string, peer *net.IPNet) [][2]int64 {
+	// The ACL decides the same way for every port between the bounds of its
+	// port ranges, so only the first port of each span is evaluated
+	bounds := []int64{ephemeralPortFrom, ephemeralPortTo + 1}
+	for _, entry := range acl.Entries {
+		if entry.PortRange != nil && entry.PortRange.From != nil && entry.PortRange.To != nil {
+			bounds = append(bounds, *entry.PortRange.From, *entry.PortRange.To+1)
+		}
+	}
+	sort.Slice(bounds, func(i, j int) bool { return bounds[i] < bounds[j] })
+
+	var allowed [][2]int64
+	for i := 0; i < len(bounds)-1; i++ {
+		from, to := bounds[i], bounds[i+1]-1
+		if from > to || from < ephemeralPortFrom || to > ephemeralPortTo {
+			continue
+		}
+		entry := evaluateNetworkACL(acl, egress, networkTraffic{Protocol: protocol, Port: from}, peer)
+		if entry == nil || types.SafeString(entry.RuleAction) != ec2.RuleActionAllow {
+			continue
+		}
+		if n := len(allowed); n > 0 && allowed[n-1][1] == from-1 {
+			allowed[n-1][1] = to
+		} else {
+			allowed = append(allowed, [2]int64{from, to})
+		}
+	}
+	return allowed
+}
+
+// securityGroupAllows returns true if a rule of the security group allows the
+// traffic to or from a peer. peerGroupIds are the security groups of the peer,
+// for rules which reference other groups.
+func securityGroupAllows(group *ec2.SecurityGroup, egress bool, traffic networkTraffic, peer *net.IPNet, peerGroupIds []string, prefixLists map[string][]string) bool {
+	permissions := group.IpPermissions
+	if egress {
+		permissions = group.IpPermissionsEgress
+	}
+
+	for _, permission := range permissions {
+		if !traffic.matchesProtocol(types.SafeString(permission.IpProtocol)) {
+			continue
+		}
+		if traffic.isIcmp() {
+			if !traffic.matchesIcmpType(permission.FromPort) {
+				continue
+			}
+		} else if !traffic.matchesPortRange(permission.FromPort, permission.ToPort) {
+			continue
+		}
+
+		for _, r := range permission.IpRanges {
+			if cidrContains(types.SafeString(r.CidrIp), peer) {
+				return true
+			}
+		}
+		for _, r := range permission.Ipv6Ranges {
+			if cidrContains(types.SafeString(r.CidrIpv6), peer) {
+				return true
+			}
+		}
+		for _, r := range permission.PrefixListIds {
+			for _, cidr := range prefixLists[types.SafeString(r.PrefixListId)] {
+				if cidrContains(cidr, peer) {
+					return true
+				}
+			}
+		}
+		for _, r := range permission.UserIdGroupPairs {
+			if helpers.StringSliceContains(peerGroupIds, types.SafeString(r.GroupId)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+//// NETWORK MODEL
+
+// vpcNetwork is the network configuration of a region
+type vpcNetwork struct {
+	Vpcs                      []*ec2.Vpc
+	Subnets                   []*ec2.Subnet
+	RouteTables               []*ec2.RouteTable
+	NetworkAcls               []*ec2.NetworkAcl
+	SecurityGroups            []*ec2.SecurityGroup
+	NetworkInterfaces         []*ec2.NetworkInterface
+	NatGateways               []*ec2.NatGateway
+	PeeringConnections        []*ec2.VpcPeeringConnection
+	TransitGatewayAttachments []*ec2.TransitGatewayAttachment
+	// The CIDRs of the prefix lists referenced by security groups and routes
+	PrefixLists map[string][]string
+	// searchTransitGatewayRoute returns the route a transit gateway route table
+	// uses for a destination, or nil if there is none
+	searchTransitGatewayRoute func(routeTableID string, destination *net.IPNet) (*ec2.TransitGatewayRoute, error)
+}
+
+// loadVpcNetwork returns the network configuration of a region
+func loadVpcNetwork(ctx context.Context, d *plugin.QueryData, region string) (*vpcNetwork, error) {
+	var err error
+	network := &vpcNetwork{}
+	if network.Vpcs, err = getVpcVpcs(ctx, d, region); err != nil {
+		return nil, err
+	}
+	if network.Subnets, err = getVpcSubnets(ctx, d, region); err != nil {
+		return nil, err
+	}
+	if network.RouteTables, err = getVpcRouteTables(ctx, d, region); err != nil {
+		return nil, err
+	}
+	if network.NetworkAcls, err = getVpcNetworkAcls(ctx, d, region); err != nil {
+		return nil, err
+	}
+	if network.SecurityGroups, err = getVpcSecurityGroups(ctx, d, region); err != nil {
+		return nil, err
+	}
+	if network.NetworkInterfaces, err = getVpcNetworkInterfaces(ctx, d, region); err != nil {
+		return nil, err
+	}
+	if network.NatGateways, err = getVpcNatGateways(ctx, d, region); err != nil {
+		return nil, err
+	}
+	if network.PeeringConnections, err = getVpcPeeringConnections(ctx, d, region); err != nil {
+		return nil, err
+	}
+	if network.TransitGatewayAttachments, err = getTransitGatewayAttachments(ctx, d, region); err != nil {
+		return nil, err
+	}
+
+	if network.PrefixLists, err = getReferencedPrefixListCidrs(ctx, d, region, network.SecurityGroups); err != nil {
+		return nil, err
+	}
+	for _, routeTable := range network.RouteTables {
+		for _, route := range routeTable.Routes {
+			id := types.SafeString(route.DestinationPrefixListId)
+			if _, ok := network.PrefixLists[id]; id == "" || ok {
+				continue
+			}
+			if network.PrefixLists[id], err = getManagedPrefixListCidrs(ctx, d, region, id); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	network.searchTransitGatewayRoute = func(routeTableID string, destination *net.IPNet) (*ec2.TransitGatewayRoute, error) {
+		svc, err := Ec2Service(ctx, d, region)
+		if err != nil {
+			return nil, err
+		}
+		op, err := svc.SearchTransitGatewayRoutes(&ec2.SearchTransitGatewayRoutesInput{
+			TransitGatewayRouteTableId: aws.String(routeTableID),
+			Filters: []*ec2.Filter{{
+				Name:   aws.String("route-search.longest-prefix-match"),
+				Values: []*string{aws.String(destination.String())},
+			}},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if len(op.Routes) == 0 {
+			return nil, nil
+		}
+		return op.Routes[0], nil
+	}
+
+	return network, nil
+}
+
+func (network *vpcNetwork) vpc(id string) *ec2.Vpc {
+	for _, vpc := range network.Vpcs {
+		if types.SafeString(vpc.VpcId) == id {
+			return vpc
+		}
+	}
+	return nil
+}
+
+func (network *vpcNetwork) subnet(id string) *ec2.Subnet {
+	for _, subnet := range network.Subnets {
+		if types.SafeString(subnet.SubnetId) == id {
+			return subnet
+		}
+	}
+	return nil
+}
+
+// subnetContaining returns the subnet of a VPC which contains every address of
+// the CIDR, if any
+func (network *vpcNetwork) subnetContaining(vpcID string, cidr *net.IPNet) *ec2.Subnet {
+	for _, subnet := range network.Subnets {
+		if types.SafeString(subnet.VpcId) != vpcID {
+			continue
+		}
+		for _, block := range subnetCidrBlocks(subnet) {
+			if cidrContains(block, cidr) {
+				return subnet
+			}
+		}
+	}
+	return nil
+}
+
+// vpcContains returns true if one of the CIDR blocks of the VPC contains every
+// address of the CIDR
+func (network *vpcNetwork) vpcContains(vpcID string, cidr *net.IPNet) bool {
+	vpc := network.vpc(vpcID)
+	if vpc == nil {
+		return false
+	}
+	for _, block := range vpcCidrBlocks(vpc) {
+		if cidrContains(block, cidr) {
+			return true
+		}
+	}
+	return false
+}
+
+// subnetRouteTable returns the route table associated with a subnet, or the
+// main route table of its VPC
+func (network *vpcNetwork) subnetRouteTable(subnet *ec2.Subnet) *ec2.RouteTable {
+	var main *ec2.RouteTable
+	for _, routeTable := range network.RouteTables {
+		for _, association := range routeTable.Associations {
+			if types.SafeString(association.SubnetId) == types.SafeString(subnet.SubnetId) {
+				return routeTable
+			}
+			if types.BoolValue(association.Main) && types.SafeString(routeTable.VpcId) == types.SafeString(subnet.VpcId) {
+				main = routeTable
+			}
+		}
+	}
+	return main
+}
+
+// subnetNetworkACL returns the network ACL associated with a subnet
+func (network *vpcNetwork) subnetNetworkACL(subnet *ec2.Subnet) *ec2.NetworkAcl {
+	for _, acl := range network.NetworkAcls {
+		for _, association := range acl.Associations {
+			if types.SafeString(association.SubnetId) == types.SafeString(subnet.SubnetId) {
+				return acl
+			}
+		}
+	}
+	return nil
+}
+
+func (network *vpcNetwork) securityGroups(ids []string) []*ec2.SecurityGroup {
+	var groups []*ec2.SecurityGroup
+	for _, group := range network.SecurityGroups {
+		if helpers.StringSliceContains(ids, types.SafeString(group.GroupId)) {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// routeDestinations returns the CIDRs a route applies to
+func (network *vpcNetwork) routeDestinations(route *ec2.Route) []string {
+	switch {
+	case route.DestinationCidrBlock != nil:
+		return []string{*route.DestinationCidrBlock}
+	case route.DestinationIpv6CidrBlock != nil:
+		return []string{*route.DestinationIpv6CidrBlock}
+	case route.DestinationPrefixListId != nil:
+		return network.PrefixLists[*route.DestinationPrefixListId]
+	}
+	return nil
+}
+
+// longestRouteMatch returns the most specific route of a route table which
+// contains every address of the destination
+func (network *vpcNetwork) longestRouteMatch(routeTable *ec2.RouteTable, destination *net.IPNet) *ec2.Route {
+	var match *ec2.Route
+	matchOnes := -1
+	for _, route := range routeTable.Routes {
+		for _, cidr := range network.routeDestinations(route) {
+			if !cidrContains(cidr, destination) {
+				continue
+			}
+			_, routeCidr, _ := net.ParseCIDR(cidr)
+			if ones, _ := routeCidr.Mask.Size(); ones > matchOnes {
+				match = route
+				matchOnes = ones
+			}
+		}
+	}
+	return match
+}
+
+// routeTarget returns the type and ID of the target of a route
+func routeTarget(route *ec2.Route) (string, string) {
+	switch {
+	case route.NatGatewayId != nil:
+		return "nat_gateway", *route.NatGatewayId
+	case route.EgressOnlyInternetGatewayId != nil:
+		return "egress_only_internet_gateway", *route.EgressOnlyInternetGatewayId
+	case route.VpcPeeringConnectionId != nil:
+		return "vpc_peering_connection", *route.VpcPeeringConnectionId
+	case route.TransitGatewayId != nil:
+		return "transit_gateway", *route.TransitGatewayId
+	case route.NetworkInterfaceId != nil:
+		return "network_interface", *route.NetworkInterfaceId
+	case route.InstanceId != nil:
+		return "instance", *route.InstanceId
+	case route.LocalGatewayId != nil:
+		return "local_gateway", *route.LocalGatewayId
+	case route.CarrierGatewayId != nil:
+		return "carrier_gateway", *route.CarrierGatewayId
+	}
+
+	gatewayID := types.SafeString(route.GatewayId)
+	switch {
+	case gatewayID == "local":
+		return "local", gatewayID
+	case strings.HasPrefix(gatewayID, "igw-"):
+		return "internet_gateway", gatewayID
+	case strings.HasPrefix(gatewayID, "vgw-"):
+		return "vpn_gateway", gatewayID
+	case strings.HasPrefix(gatewayID, "vpce-"):
+		return "vpc_endpoint", gatewayID
+	}
+	return "gateway", gatewayID
+}
+
+func vpcCidrBlocks(vpc *ec2.Vpc) []string {
+	var blocks []string
+	for _, association := range vpc.CidrBlockAssociationSet {
+		blocks = append(blocks, types.SafeString(association.CidrBlock))
+	}
+	for _, association := range vpc.Ipv6CidrBlockAssociationSet {
+		blocks = append(blocks, types.SafeString(association.Ipv6CidrBlock))
+	}
+	if len(blocks) == 0 && vpc.CidrBlock != nil {
+		blocks = append(blocks, *vpc.CidrBlock)
+	}
+	return blocks
+}
+
+func subnetCidrBlocks(subnet *ec2.Subnet) []string {
+	var blocks []string
+	if subnet.CidrBlock != nil {
+		blocks = append(blocks, *subnet.CidrBlock)
+	}
+	for _, association := range subnet.Ipv6CidrBlockAssociationSet {
+		blocks = append(blocks, types.SafeString(association.Ipv6CidrBlock))
+	}
+	return blocks
+}
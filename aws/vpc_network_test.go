@@ -0,0 +1,102 @@
+package aws
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/ec2"
+)
+
+func TestEvaluateNetworkACL(t *testing.T) {
+	acl := &ec2.NetworkAcl{
+		NetworkAclId: aws.String("acl-test"),
+		Entries: []*ec2.NetworkAclEntry{
+			{RuleNumber: aws.Int64(32767), Egress: aws.Bool(false), Protocol: aws.String("-1"), CidrBlock: aws.String("0.0.0.0/0"), RuleAction: aws.String("deny")},
+			{RuleNumber: aws.Int64(32767), Egress: aws.Bool(false), Protocol: aws.String("-1"), Ipv6CidrBlock: aws.String("::/0"), RuleAction: aws.String("deny")},
+			{RuleNumber: aws.Int64(100), Egress: aws.Bool(false), Protocol: aws.String("6"), PortRange: &ec2.PortRange{From: aws.Int64(22), To: aws.Int64(22)}, CidrBlock: aws.String("10.0.0.0/8"), RuleAction: aws.String("allow")},
+			{RuleNumber: aws.Int64(90), Egress: aws.Bool(false), Protocol: aws.String("6"), PortRange: &ec2.PortRange{From: aws.Int64(0), To: aws.Int64(1023)}, CidrBlock: aws.String("10.1.0.0/16"), RuleAction: aws.String("deny")},
+			{RuleNumber: aws.Int64(110), Egress: aws.Bool(false), Protocol: aws.String("1"), IcmpTypeCode: &ec2.IcmpTypeCode{Type: aws.Int64(8), Code: aws.Int64(-1)}, CidrBlock: aws.String("0.0.0.0/0"), RuleAction: aws.String("allow")},
+			{RuleNumber: aws.Int64(120), Egress: aws.Bool(false), Protocol: aws.String("-1"), Ipv6CidrBlock: aws.String("2001:db8::/32"), RuleAction: aws.String("allow")},
+			{RuleNumber: aws.Int64(100), Egress: aws.Bool(true), Protocol: aws.String("-1"), CidrBlock: aws.String("0.0.0.0/0"), RuleAction: aws.String("allow")},
+		},
+	}
+
+	cases := []struct {
+		name       string
+		egress     bool
+		traffic    networkTraffic
+		cidr       string
+		ruleNumber int64
+	}{
+		{"allowed by a later rule", false, networkTraffic{"6", 22}, "10.2.0.1", 100},
+		{"denied by a lower rule number", false, networkTraffic{"6", 22}, "10.1.2.3", 90},
+		{"CIDR wider than the rule range", false, networkTraffic{"6", 22}, "10.0.0.0/7", 32767},
+		{"port outside the range", false, networkTraffic{"6", 443}, "10.2.0.1", 32767},
+		{"ICMP echo request", false, networkTraffic{"1", 8}, "192.0.2.1", 110},
+		{"other ICMP type", false, networkTraffic{"1", 0}, "192.0.2.1", 32767},
+		{"IPv6 range", false, networkTraffic{"17", 53}, "2001:db8::1", 120},
+		{"IPv6 default", false, networkTraffic{"17", 53}, "2001:db9::1", 32767},
+		{"egress rules", true, networkTraffic{"6", 443}, "0.0.0.0/0", 100},
+		{"all ports not covered by a port range", false, networkTraffic{"6", -1}, "10.2.0.1", 32767},
+	}
+
+	for _, c := range cases {
+		peer, err := parseNetworkAddress(c.cidr)
+		if err != nil {
+			t.Fatalf("Case '%s': %v", c.name, err)
+		}
+		entry := evaluateNetworkACL(acl, c.egress, c.traffic, peer)
+		if entry == nil {
+			t.Errorf("Case '%s': expected rule %d, got no entry", c.name, c.ruleNumber)
+			continue
+		}
+		if *entry.RuleNumber != c.ruleNumber || *entry.Egress != c.egress {
+			t.Errorf("Case '%s': expected rule %d, got %d", c.name, c.ruleNumber, *entry.RuleNumber)
+		}
+	}
+}
+
+func TestAllowedEphemeralPorts(t *testing.T) {
+	acl := &ec2.NetworkAcl{
+		NetworkAclId: aws.String("acl-test"),
+		Entries: []*ec2.NetworkAclEntry{
+			{RuleNumber: aws.Int64(32767), Egress: aws.Bool(true), Protocol: aws.String("-1"), CidrBlock: aws.String("0.0.0.0/0"), RuleAction: aws.String("deny")},
+			{RuleNumber: aws.Int64(90), Egress: aws.Bool(true), Protocol: aws.String("6"), PortRange: &ec2.PortRange{From: aws.Int64(40000), To: aws.Int64(40100)}, CidrBlock: aws.String("0.0.0.0/0"), RuleAction: aws.String("deny")},
+			{RuleNumber: aws.Int64(100), Egress: aws.Bool(true), Protocol: aws.String("6"), PortRange: &ec2.PortRange{From: aws.Int64(32768), To: aws.Int64(65535)}, CidrBlock: aws.String("0.0.0.0/0"), RuleAction: aws.String("allow")},
+			{RuleNumber: aws.Int64(110), Egress: aws.Bool(true), Protocol: aws.String("-1"), CidrBlock: aws.String("10.0.0.0/8"), RuleAction: aws.String("allow")},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		protocol string
+		cidr     string
+		expected [][2]int64
+	}{
+		{"span denied by a lower rule number", "6", "10.1.2.3", [][2]int64{{1024, 39999}, {40101, 65535}}},
+		{"part of the range allowed", "6", "192.0.2.1", [][2]int64{{32768, 39999}, {40101, 65535}}},
+		{"no ports allowed", "17", "192.0.2.1", nil},
+		{"whole range allowed", "17", "10.1.2.3", [][2]int64{{1024, 65535}}},
+	}
+
+	for _, c := range cases {
+		peer, err := parseNetworkAddress(c.cidr)
+		if err != nil {
+			t.Fatalf("Case '%s': %v", c.name, err)
+		}
+		allowed := allowedEphemeralPorts(acl, true, c.protocol, peer)
+		if !reflect.DeepEqual(allowed, c.expected) {
+			t.Errorf("Case '%s': expected %v, got %v", c.name, c.expected, allowed)
+		}
+	}
+}
+
+func TestIpProtocolName(t *testing.T) {
+	cases := map[string]string{"6": "tcp", "17": "udp", "1": "icmp", "58": "icmpv6", "-1": "all", "50": "50", "TCP": "tcp"}
+	for protocol, expected := range cases {
+		if name := ipProtocolName(protocol); name != expected {
+			t.Errorf("Case '%s': expected %s, got %s", protocol, expected, name)
+		}
+	}
+}
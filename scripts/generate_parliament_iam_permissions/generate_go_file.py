@@ -138,36 +138,36 @@ def generate_go_file(iam_permissions):
         go_file.write("""package aws
 
 type ParliamentCondition struct {
-Condition   string
-Description string
-Type string
+Condition   string `json:"condition"`
+Description string `json:"description"`
+Type string `json:"type"`
 }
 
 type ParliamentResourceType struct {
-ConditionKeys []string
-DependentActions []string
-ResourceType string
+ConditionKeys []string `json:"condition_keys"`
+DependentActions []string `json:"dependent_actions"`
+ResourceType string `json:"resource_type"`
 }
 
 type ParliamentPrivilege struct {
-AccessLevel string
-Description string
-Privilege string
-ResourceTypes []ParliamentResourceType
+AccessLevel string `json:"access_level"`
+Description string `json:"description"`
+Privilege string `json:"privilege"`
+ResourceTypes []ParliamentResourceType `json:"resource_types"`
 }
 
 type ParliamentResource struct {
-Arn           string
-ConditionKeys []string
-Resource      string
+Arn           string `json:"arn"`
+ConditionKeys []string `json:"condition_keys"`
+Resource      string `json:"resource"`
 }
 
 type ParliamentService struct {
-Conditions []ParliamentCondition
-Prefix string
-Privileges []ParliamentPrivilege
-Resources []ParliamentResource
-ServiceName string
+Conditions []ParliamentCondition `json:"conditions"`
+Prefix string `json:"prefix"`
+Privileges []ParliamentPrivilege `json:"privileges"`
+Resources []ParliamentResource `json:"resources"`
+ServiceName string `json:"service_name"`
 }
 
 type ParliamentPermissions []ParliamentService